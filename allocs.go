@@ -0,0 +1,64 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"sync/atomic"
+)
+
+// SetAllocSampleRate makes a random fraction of the traced Spans started on
+// this Registry, between 0 and 1, record roughly how many bytes were
+// allocated while they ran as an alloc.bytes annotation. It is 0, off, by
+// default.
+//
+// The number is the growth of the process-wide count of allocated heap bytes
+// between the start and the end of the Span, so it includes everything other
+// goroutines allocated in the meantime, and is only meaningful for Spans that
+// ran while little else did. Stack allocations are not counted. Reading the
+// count is cheap where runtime/metrics is supported, but under TinyGo it
+// uses runtime.ReadMemStats, which stops the world, so keep the rate low
+// there.
+func (r *Registry) SetAllocSampleRate(rate float64) {
+	atomic.StoreUint64(&r.allocSampleRate, math.Float64bits(rate))
+}
+
+// AllocSampleRate returns the rate set with SetAllocSampleRate.
+func (r *Registry) AllocSampleRate() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&r.allocSampleRate))
+}
+
+func (r *Registry) sampleAllocs() bool {
+	rate := r.AllocSampleRate()
+	return rate > 0 && (rate >= 1 || rand.Float64() < rate)
+}
+
+// startAllocs is called before the Span is shared with anyone.
+func (s *Span) startAllocs() {
+	if s.f.scope.r.sampleAllocs() {
+		s.trackAllocs = true
+		s.allocStart = heapAllocBytes()
+	}
+}
+
+func (s *Span) annotateAllocs() {
+	if !s.trackAllocs {
+		return
+	}
+	allocated := heapAllocBytes() - s.allocStart
+	s.Annotate("alloc.bytes", strconv.FormatUint(allocated, 10))
+}
@@ -0,0 +1,42 @@
+package monkit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+var allocSink []byte
+
+func TestAllocSampleRate(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("allocs")
+
+	run := func() *Span {
+		ctx := context.Background()
+		defer mon.Task()(&ctx)(nil)
+		allocSink = make([]byte, 1<<20)
+		return SpanFromCtx(ctx)
+	}
+	allocBytes := func(s *Span) (string, bool) {
+		for _, a := range s.Annotations() {
+			if a.Name == "alloc.bytes" {
+				return a.Value, true
+			}
+		}
+		return "", false
+	}
+
+	if v, ok := allocBytes(run()); ok {
+		t.Fatal("unexpected alloc.bytes while disabled:", v)
+	}
+
+	r.SetAllocSampleRate(1)
+	v, ok := allocBytes(run())
+	if !ok {
+		t.Fatal("expected an alloc.bytes annotation")
+	}
+	if n, err := strconv.ParseUint(v, 10, 64); err != nil || n == 0 {
+		t.Fatalf("expected a positive byte count, got %q", v)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"strconv"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// AnnotationPolicy decides what happens to an annotation added to a Span that
+// already has an annotation with the same name, whether it's added with
+// Span.Annotate or when the Span starts, like Func tags, context annotations
+// and baggage.
+type AnnotationPolicy int32
+
+const (
+	// AnnotationList keeps every annotation, including repeated names. This
+	// is the default.
+	AnnotationList AnnotationPolicy = iota
+	// AnnotationFirstWins ignores annotations with a name the Span already
+	// has.
+	AnnotationFirstWins
+	// AnnotationLastWins replaces the value of an existing annotation with the
+	// same name, keeping its original position.
+	AnnotationLastWins
+)
+
+// SetAnnotationPolicy sets the AnnotationPolicy Spans created after this call
+// start out with. See also Span.SetAnnotationPolicy.
+func (r *Registry) SetAnnotationPolicy(policy AnnotationPolicy) {
+	atomic.StoreInt32(&r.annotationPolicy, int32(policy))
+}
+
+// AnnotationPolicy returns the policy set with SetAnnotationPolicy.
+func (r *Registry) AnnotationPolicy() AnnotationPolicy {
+	return AnnotationPolicy(atomic.LoadInt32(&r.annotationPolicy))
+}
+
+// SetAnnotationPolicy changes how subsequent calls to Annotate treat repeated
+// annotation names on this Span. Annotations added before the call are left
+// alone.
+func (s *Span) SetAnnotationPolicy(policy AnnotationPolicy) {
+	s.mtx.Lock()
+	s.annotationPolicy = policy
+	s.mtx.Unlock()
+}
+
+// DefaultMaxAnnotationLength is the length annotation values are truncated
+// to unless configured otherwise with SetMaxAnnotationLength.
+const DefaultMaxAnnotationLength = 64 << 10
+
+// truncationMarker is appended to truncated annotation values.
+const truncationMarker = "…(truncated)"
+
+// SetMaxAnnotationLength sets how many bytes of an annotation value Spans
+// created after this call keep. Longer values, whether added with
+// Span.Annotate or when the Span starts, like Func tags, context annotations
+// and the baggage of ContinueTrace, are cut at a UTF-8 character boundary and
+// get a "…(truncated)" marker appended, and the Span counts them, see
+// Span.TruncatedAnnotations. A length
+// <= 0 keeps values of any length. It is DefaultMaxAnnotationLength by
+// default.
+func (r *Registry) SetMaxAnnotationLength(length int) {
+	atomic.StoreInt64(&r.maxAnnotationLength, int64(length))
+}
+
+// MaxAnnotationLength returns the length set with SetMaxAnnotationLength.
+func (r *Registry) MaxAnnotationLength() int {
+	return int(atomic.LoadInt64(&r.maxAnnotationLength))
+}
+
+// TruncatedAnnotations returns how many annotation values of the Span were
+// truncated because of SetMaxAnnotationLength. When a Span with truncated
+// values finishes, the count is also added as the annotation.truncated
+// annotation.
+func (s *Span) TruncatedAnnotations() (n int) {
+	s.mtx.Lock()
+	n = s.truncated
+	s.mtx.Unlock()
+	return n
+}
+
+// annotateTruncated must be called with s.mtx held.
+func (s *Span) annotateTruncated() {
+	if s.truncated > 0 {
+		s.annotations = append(s.annotations, Annotation{
+			Name: "annotation.truncated", Value: strconv.Itoa(s.truncated)})
+	}
+}
+
+// addAnnotation must be called with s.mtx held. It returns the value as the
+// Span stored it, which may be truncated, and false if the AnnotationPolicy
+// dropped it.
+func (s *Span) addAnnotation(name, val string) (stored string, ok bool) {
+	truncated := false
+	if max := s.maxAnnotationLength; max > 0 && len(val) > max {
+		for max > 0 && !utf8.RuneStart(val[max]) {
+			max--
+		}
+		val = val[:max] + truncationMarker
+		truncated = true
+	}
+	switch s.annotationPolicy {
+	case AnnotationList:
+		// repeated names are kept.
+	case AnnotationLastWins:
+		if s.replaceAnnotation(name, val) {
+			if truncated {
+				s.truncated++
+			}
+			return val, true
+		}
+	default:
+		for _, a := range s.annotations {
+			if a.Name == name {
+				return "", false
+			}
+		}
+	}
+	s.annotations = append(s.annotations, Annotation{Name: name, Value: val})
+	if truncated {
+		s.truncated++
+	}
+	return val, true
+}
+
+// replaceAnnotation must be called with s.mtx held. It sets the value of the
+// first annotation called name, or returns false if there is none.
+func (s *Span) replaceAnnotation(name, val string) bool {
+	for i, a := range s.annotations {
+		if a.Name == name {
+			// Annotations copies the slice after releasing s.mtx, so the
+			// annotations are replaced rather than modified in place.
+			annotations := append([]Annotation(nil), s.annotations...)
+			annotations[i].Value = val
+			s.annotations = annotations
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package monkit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnnotationPolicy(t *testing.T) {
+	annotate := func(r *Registry, policy *AnnotationPolicy) []Annotation {
+		ctx := context.Background()
+		finish := r.ScopeNamed("annotations").Task()(&ctx)
+		s := SpanFromCtx(ctx)
+		if policy != nil {
+			s.SetAnnotationPolicy(*policy)
+		}
+		s.Annotate("a", "1")
+		s.Annotate("b", "2")
+		s.Annotate("a", "3")
+		finish(nil)
+		return s.Annotations()
+	}
+
+	for _, test := range []struct {
+		policy   AnnotationPolicy
+		expected []Annotation
+	}{
+		{AnnotationList, []Annotation{{"a", "1"}, {"b", "2"}, {"a", "3"}}},
+		{AnnotationFirstWins, []Annotation{{"a", "1"}, {"b", "2"}}},
+		{AnnotationLastWins, []Annotation{{"a", "3"}, {"b", "2"}}},
+	} {
+		policy := test.policy
+		if actual := annotate(NewRegistry(), &policy); !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf("span policy %d: got %v, expected %v", test.policy, actual, test.expected)
+		}
+
+		r := NewRegistry()
+		r.SetAnnotationPolicy(test.policy)
+		if actual := annotate(r, nil); !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf("registry policy %d: got %v, expected %v", test.policy, actual, test.expected)
+		}
+	}
+}
+
+func TestMaxAnnotationLength(t *testing.T) {
+	r := NewRegistry()
+	if r.MaxAnnotationLength() != DefaultMaxAnnotationLength {
+		t.Fatalf("unexpected default length %d", r.MaxAnnotationLength())
+	}
+	r.SetMaxAnnotationLength(10)
+	mon := r.ScopeNamed("truncate")
+
+	ctx := context.Background()
+	finish := mon.Task()(&ctx)
+	s := SpanFromCtx(ctx)
+	s.Annotate("short", "fits")
+	s.Annotate("query", strings.Repeat("x", 100))
+	// "é" is two bytes, so the cut has to move back to keep valid UTF-8.
+	s.Annotate("error", "aaaaaaaaaé")
+	finish(nil)
+
+	if n := s.TruncatedAnnotations(); n != 2 {
+		t.Fatalf("expected 2 truncated annotations, got %d", n)
+	}
+	expected := map[string]string{
+		"short":                "fits",
+		"query":                "xxxxxxxxxx…(truncated)",
+		"error":                "aaaaaaaaa…(truncated)",
+		"annotation.truncated": "2",
+	}
+	for name, value := range expected {
+		if !hasAnnotation(s, name, value) {
+			t.Fatalf("expected %s=%q, got %v", name, value, s.Annotations())
+		}
+	}
+}
+
+func TestInitialAnnotationsLimited(t *testing.T) {
+	r := NewRegistry()
+	r.SetMaxAnnotationLength(10)
+	r.SetAnnotationPolicy(AnnotationFirstWins)
+	r.AddContextAnnotator(func(ctx context.Context) map[string]string {
+		return map[string]string{"user": "bob"}
+	})
+	mon := r.ScopeNamed("initial")
+
+	// baggage from a remote caller is truncated, and the policy applies to
+	// the context annotations.
+	ctx := context.Background()
+	finish := mon.ContinueTrace(&ctx, NewId(), NewId(), true, map[string]string{
+		"baggage": strings.Repeat("x", 100),
+		"user":    "mallory",
+	})
+	s := SpanFromCtx(ctx)
+	finish(nil)
+
+	if n := s.TruncatedAnnotations(); n != 1 {
+		t.Fatalf("expected 1 truncated annotation, got %d", n)
+	}
+	if !hasAnnotation(s, "baggage", "xxxxxxxxxx…(truncated)") {
+		t.Fatalf("expected the truncated baggage, got %v", s.Annotations())
+	}
+	var users int
+	for _, a := range s.Annotations() {
+		if a.Name == "user" {
+			users++
+		}
+	}
+	if users != 1 {
+		t.Fatalf("expected the first user annotation only, got %v", s.Annotations())
+	}
+}
+
+func TestMarkDuplicate(t *testing.T) {
+	ctx := context.Background()
+	defer NewRegistry().ScopeNamed("queue").Task()(&ctx)(nil)
+	s := SpanFromCtx(ctx)
+
+	s.MarkDuplicate(false)
+	if !hasAnnotation(s, "operation.duplicate", "false") {
+		t.Fatalf("unexpected annotations %v", s.Annotations())
+	}
+
+	// marking again replaces the value instead of adding another one.
+	before := s.Annotations()
+	s.MarkDuplicate(true)
+	annotations := s.Annotations()
+	if len(annotations) != 1 || annotations[0] != (Annotation{Name: "operation.duplicate", Value: "true"}) {
+		t.Fatalf("unexpected annotations %v", annotations)
+	}
+	if before[0].Value != "false" {
+		t.Fatal("annotations handed out earlier were modified")
+	}
+}
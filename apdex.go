@@ -0,0 +1,103 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type apdexCounts struct {
+	enabled int32
+
+	mtx                              sync.Mutex
+	target, tolerating               time.Duration
+	satisfied, tolerated, frustrated int64
+}
+
+// SetApdex makes the Func report its Apdex score, with target as the
+// threshold of satisfied calls and, by convention, four times target as the
+// threshold of tolerated ones. See SetApdexThresholds.
+func (f *Func) SetApdex(target time.Duration) {
+	f.SetApdexThresholds(target, 4*target)
+}
+
+// SetApdexThresholds makes the Func report its Apdex score: successful calls
+// that take at most target are satisfied, ones that take at most tolerating
+// are tolerated, and all other calls, including failed ones, are frustrated.
+// The score, reported as the apdex stat, is the number of satisfied calls
+// plus half of the tolerated ones, divided by the number of calls, so 1 means
+// every call was satisfied and 0 that every one was frustrated. Without any
+// calls the score is NaN, which most backends show as a gap, rather than a
+// perfect score for an endpoint that isn't used. The counts of the three
+// bands are reported as apdex_satisfied, apdex_tolerated and
+// apdex_frustrated, so that scores can be combined across processes.
+//
+// The counts are kept from this call on; calling it again starts over. A
+// target of zero or less stops reporting the score.
+func (f *Func) SetApdexThresholds(target, tolerating time.Duration) {
+	a := &f.apdex
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.target, a.tolerating = target, tolerating
+	a.satisfied, a.tolerated, a.frustrated = 0, 0, 0
+	if target > 0 {
+		atomic.StoreInt32(&a.enabled, 1)
+	} else {
+		atomic.StoreInt32(&a.enabled, 0)
+	}
+}
+
+// observe counts a finished call towards the apdex score. FuncStats.end
+// calls it, so that every way of observing a Func's calls counts.
+func (a *apdexCounts) observe(err error, panicked bool, duration time.Duration) {
+	if atomic.LoadInt32(&a.enabled) == 0 {
+		return
+	}
+	a.mtx.Lock()
+	switch {
+	case a.target <= 0:
+	case err != nil || panicked:
+		a.frustrated++
+	case duration <= a.target:
+		a.satisfied++
+	case duration <= a.tolerating:
+		a.tolerated++
+	default:
+		a.frustrated++
+	}
+	a.mtx.Unlock()
+}
+
+func (f *Func) apdexStats(cb func(key SeriesKey, field string, val float64)) {
+	a := &f.apdex
+	if atomic.LoadInt32(&a.enabled) == 0 {
+		return
+	}
+	a.mtx.Lock()
+	satisfied, tolerated, frustrated := a.satisfied, a.tolerated, a.frustrated
+	a.mtx.Unlock()
+
+	score := math.NaN()
+	if total := satisfied + tolerated + frustrated; total > 0 {
+		score = (float64(satisfied) + float64(tolerated)/2) / float64(total)
+	}
+	cb(f.key, "apdex", score)
+	cb(f.key, "apdex_satisfied", float64(satisfied))
+	cb(f.key, "apdex_tolerated", float64(tolerated))
+	cb(f.key, "apdex_frustrated", float64(frustrated))
+}
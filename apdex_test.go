@@ -0,0 +1,87 @@
+package monkit
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestApdex(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("apdex")
+	f := mon.FuncNamed("handler")
+	observe := func(err error, duration time.Duration) {
+		f.start(nil)
+		f.end(err, false, duration)
+	}
+	key := "function,name=handler,scope=apdex "
+
+	// without a target there is no score.
+	observe(nil, time.Millisecond)
+	if _, ok := Collect(mon)[key+"apdex"]; ok {
+		t.Fatal("unexpected apdex stat")
+	}
+
+	f.SetApdex(100 * time.Millisecond)
+	if score := Collect(mon)[key+"apdex"]; !math.IsNaN(score) {
+		t.Fatalf("expected NaN without calls, got %v", score)
+	}
+
+	for _, d := range []time.Duration{10, 100} {
+		observe(nil, d*time.Millisecond) // satisfied
+	}
+	for _, d := range []time.Duration{101, 400} {
+		observe(nil, d*time.Millisecond) // tolerated
+	}
+	observe(nil, 401*time.Millisecond)            // frustrated
+	observe(errors.New("boom"), time.Millisecond) // failed calls are frustrated
+	observe(nil, time.Second)                     // frustrated
+	observe(nil, 50*time.Millisecond)             // satisfied
+
+	stats := Collect(mon)
+	for field, expected := range map[string]float64{
+		"apdex":            (3 + 2.0/2) / 8,
+		"apdex_satisfied":  3,
+		"apdex_tolerated":  2,
+		"apdex_frustrated": 3,
+	} {
+		if got := stats[key+field]; got != expected {
+			t.Fatalf("%s: expected %v, got %v", field, expected, got)
+		}
+	}
+
+	// custom thresholds start over.
+	f.SetApdexThresholds(10*time.Millisecond, 20*time.Millisecond)
+	observe(nil, 5*time.Millisecond)
+	observe(nil, 30*time.Millisecond)
+	if got := Collect(mon)[key+"apdex"]; got != 0.5 {
+		t.Fatalf("expected 0.5, got %v", got)
+	}
+
+	f.SetApdex(0)
+	if _, ok := Collect(mon)[key+"apdex"]; ok {
+		t.Fatal("apdex still reported after disabling it")
+	}
+}
+
+func TestApdexObserve(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("apdex")
+	f := mon.FuncNamed("observed")
+	f.SetApdex(time.Hour)
+	key := "function,name=observed,scope=apdex "
+
+	// calls observed through the promoted FuncStats.Observe count as well.
+	func() {
+		var err error
+		defer f.Observe()(&err)
+	}()
+	func() {
+		err := errors.New("failed")
+		defer f.Observe()(&err)
+	}()
+
+	stats := Collect(mon)
+	if stats[key+"apdex_satisfied"] != 1 || stats[key+"apdex_frustrated"] != 1 || stats[key+"apdex"] != 0.5 {
+		t.Fatalf("unexpected apdex stats %v", stats)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+const (
+	// MaxAttachmentSize is the largest attachment Span.Attach accepts.
+	MaxAttachmentSize = 16 << 10
+
+	// MaxSpanAttachmentsSize is how much data Span.Attach accepts for a
+	// single Span in total.
+	MaxSpanAttachmentsSize = 64 << 10
+)
+
+// Attachment is a named binary blob attached to a Span, see Span.Attach.
+type Attachment struct {
+	Name string
+	Data []byte
+}
+
+// Attach attaches a copy of data to the Span under name, such as an encoded
+// request for inspecting it later. Attachments are kept apart from the
+// annotations, so that only exporters that ask for them with Attachments,
+// such as present.AttachmentsToJSON, carry them. Attach returns false and
+// drops data if it is larger than MaxAttachmentSize, or if it would bring the
+// attachments of the Span above MaxSpanAttachmentsSize.
+func (s *Span) Attach(name string, data []byte) bool {
+	if len(data) > MaxAttachmentSize {
+		s.f.scope.r.logf("monkit: attachment %q of span %s dropped, %d bytes is above MaxAttachmentSize",
+			name, s.f.FullName(), len(data))
+		return false
+	}
+	s.mtx.Lock()
+	size := len(data)
+	for _, a := range s.attachments {
+		size += len(a.Data)
+	}
+	if size > MaxSpanAttachmentsSize {
+		s.mtx.Unlock()
+		s.f.scope.r.logf("monkit: attachment %q of span %s dropped, the span would have %d bytes of attachments, above MaxSpanAttachmentsSize",
+			name, s.f.FullName(), size)
+		return false
+	}
+	s.attachments = append(s.attachments, Attachment{
+		Name: name,
+		Data: append([]byte(nil), data...),
+	})
+	s.mtx.Unlock()
+	return true
+}
+
+// Attachments returns the attachments added with Attach, in order. The data
+// must not be modified.
+func (s *Span) Attachments() []Attachment {
+	s.mtx.Lock()
+	attachments := s.attachments // okay cause we only ever append to this slice
+	s.mtx.Unlock()
+	return append([]Attachment(nil), attachments...)
+}
@@ -0,0 +1,50 @@
+package monkit
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestSpanAttach(t *testing.T) {
+	ctx := context.Background()
+	defer NewRegistry().ScopeNamed("attach").Task()(&ctx)(nil)
+	s := SpanFromCtx(ctx)
+
+	request := []byte{0x0a, 0x03, 'f', 'o', 'o'}
+	if !s.Attach("request", request) {
+		t.Fatal("expected the attachment to be accepted")
+	}
+	// the data is copied.
+	request[2] = 'b'
+
+	if s.Attach("huge", make([]byte, MaxAttachmentSize+1)) {
+		t.Fatal("expected an attachment above MaxAttachmentSize to be dropped")
+	}
+	for i := 0; i < MaxSpanAttachmentsSize/MaxAttachmentSize-1; i++ {
+		if !s.Attach("filler", make([]byte, MaxAttachmentSize)) {
+			t.Fatalf("expected filler %d to be accepted", i)
+		}
+	}
+	// 5 bytes plus 3 full attachments leave room for MaxAttachmentSize-5.
+	if s.Attach("over", make([]byte, MaxAttachmentSize-4)) {
+		t.Fatal("expected an attachment above MaxSpanAttachmentsSize to be dropped")
+	}
+	if !s.Attach("last", make([]byte, MaxAttachmentSize-5)) {
+		t.Fatal("expected the attachment filling the span up to be accepted")
+	}
+
+	attachments := s.Attachments()
+	if len(attachments) != 5 {
+		t.Fatalf("expected 5 attachments, got %d", len(attachments))
+	}
+	if attachments[0].Name != "request" || !bytes.Equal(attachments[0].Data, []byte{0x0a, 0x03, 'f', 'o', 'o'}) {
+		t.Fatalf("unexpected attachment %q: %x", attachments[0].Name, attachments[0].Data)
+	}
+	if attachments[4].Name != "last" {
+		t.Fatalf("unexpected attachment %q", attachments[4].Name)
+	}
+	if len(s.Annotations()) != 0 {
+		t.Fatalf("attachments showed up as annotations: %v", s.Annotations())
+	}
+}
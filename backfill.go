@@ -0,0 +1,93 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrFinishBeforeStart is returned by Span.FinishAt for a finish time before
+// the start of the Span.
+var ErrFinishBeforeStart = errors.New("monkit: span finishes before it starts")
+
+// NewSpanAt creates a Span of f in the Trace that started at start, a wall
+// clock time, such as one read from a log, for reconstructing traces that
+// happened in the past and exporting them. parentId is the id of the
+// parent Span, which doesn't have to be live anymore, or 0 for a root Span.
+// The Span gets the annotations of f and is passed to the span observers of
+// the Trace, but it doesn't count towards the stats of f and isn't tracked by
+// the Registry, as it doesn't run in this process. Finish it with FinishAt.
+func (t *Trace) NewSpanAt(f *Func, parentId int64, start time.Time,
+	args ...interface{}) *Span {
+	s := &Span{
+		id:      NewId(),
+		start:   t.monotonic(start),
+		f:       f,
+		trace:   t,
+		args:    args,
+		Context: context.Background(),
+
+		annotations:         append([]Annotation(nil), f.annotations...),
+		annotationPolicy:    f.scope.r.AnnotationPolicy(),
+		maxAnnotationLength: f.scope.r.MaxAnnotationLength(),
+	}
+	if parentId != 0 {
+		s.parentId = &parentId
+	} else {
+		t.initRootName(f)
+	}
+	t.incrementSpans()
+
+	s.backfillCtx = s
+	if observer := t.getObserver(); observer != nil {
+		s.backfillCtx = observer.Start(s, s)
+		t.observeTransition(s, SpanTransition{Kind: SpanCreated, Time: s.start})
+	}
+	return s
+}
+
+// FinishAt finishes a Span created with NewSpanAt at end, a wall clock time
+// like the start passed to NewSpanAt, passing it on to the span observers of
+// its Trace. It returns ErrFinishBeforeStart, leaving the Span running, if
+// end is before the start of the Span, and an error if the Span was not
+// created with NewSpanAt or is already finished.
+func (s *Span) FinishAt(err error, end time.Time) error {
+	if s.backfillCtx == nil {
+		return errors.New("monkit: FinishAt needs a span of NewSpanAt")
+	}
+	finish := s.trace.monotonic(end)
+	if finish.Before(s.start) {
+		return ErrFinishBeforeStart
+	}
+	if !s.claimFinish() {
+		return errors.New("monkit: span already finished")
+	}
+
+	s.trace.decrementSpans()
+	if observer := s.trace.getObserver(); observer != nil {
+		s.trace.observeTransition(s, SpanTransition{
+			Kind: SpanFinished, Time: finish, Err: err})
+		s.f.scope.r.observeFinish(observer, s.backfillCtx, s, err, false, finish)
+	}
+	return nil
+}
+
+// monotonic converts a wall clock time into the monotonic clock of Span
+// start and finish times, the inverse of WallTime.
+func (t *Trace) monotonic(wall time.Time) time.Time {
+	return t.monoStart.Add(wall.Sub(t.wallStart))
+}
@@ -0,0 +1,57 @@
+package monkit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewSpanAt(t *testing.T) {
+	r := NewRegistry()
+	f := r.ScopeNamed("backfill").FuncNamed("replayed")
+	trace := NewTrace(NewId())
+	observer := &countingObserver{}
+	trace.ObserveSpans(observer)
+
+	start := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	root := trace.NewSpanAt(f, 0, start)
+	child := trace.NewSpanAt(f, root.Id(), start.Add(time.Second))
+	if !root.Start().Equal(trace.monotonic(start)) || !trace.WallTime(root.Start()).Equal(start) {
+		t.Fatalf("unexpected start %v", trace.WallTime(root.Start()))
+	}
+	if parentId, ok := child.ParentId(); !ok || parentId != root.Id() {
+		t.Fatalf("unexpected parent %d %v", parentId, ok)
+	}
+	if _, ok := root.ParentId(); ok {
+		t.Fatal("expected a root span")
+	}
+	if trace.Spans() != 2 || observer.starts != 2 {
+		t.Fatalf("got %d spans, %d starts", trace.Spans(), observer.starts)
+	}
+
+	if err := child.FinishAt(nil, start); !errors.Is(err, ErrFinishBeforeStart) {
+		t.Fatalf("expected ErrFinishBeforeStart, got %v", err)
+	}
+	if err := child.FinishAt(errors.New("boom"), start.Add(2*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := child.FinishAt(nil, start.Add(3*time.Second)); err == nil {
+		t.Fatal("expected an error finishing twice")
+	}
+	if err := root.FinishAt(nil, start.Add(3*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if trace.Spans() != 0 || observer.finishes != 2 {
+		t.Fatalf("got %d spans, %d finishes", trace.Spans(), observer.finishes)
+	}
+
+	// backfilled spans don't count towards the stats and aren't tracked.
+	if f.Success() != 0 || len(f.Errors()) != 0 {
+		t.Fatal("backfilled spans were recorded in the func stats")
+	}
+	r.RootSpans(func(s *Span) { t.Fatal("backfilled span is tracked") })
+
+	if err := (&Span{}).FinishAt(nil, start); err == nil {
+		t.Fatal("expected an error for a span not of NewSpanAt")
+	}
+}
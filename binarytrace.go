@@ -0,0 +1,88 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The binary trace context layout matches the OpenTelemetry/OpenCensus binary
+// propagation format (the grpc-trace-bin metadata value):
+//
+//	byte  0      version, always 0
+//	byte  1      field id 0, followed by the 16 byte trace id
+//	byte  18     field id 1, followed by the 8 byte span id
+//	byte  27     field id 2, followed by 1 byte of trace options
+//
+// All numbers are big-endian. monkit trace ids are 64 bits, so they occupy
+// the low 8 bytes of the 16 byte trace id.
+const (
+	binaryTraceVersion   = 0
+	binaryTraceIDField   = 0
+	binaryTraceSpanField = 1
+	binaryTraceOptsField = 2
+	binaryTraceSampled   = 1
+
+	binaryTraceContextLen = 29
+)
+
+// ErrBinaryTraceContext is wrapped by all errors DecodeBinaryTraceContext
+// returns.
+var ErrBinaryTraceContext = errors.New("invalid binary trace context")
+
+// EncodeBinaryTraceContext encodes a trace id, span id and sampling decision
+// in the OpenTelemetry binary propagation format. See
+// DecodeBinaryTraceContext.
+func EncodeBinaryTraceContext(traceID, spanID int64, sampled bool) []byte {
+	buf := make([]byte, binaryTraceContextLen)
+	buf[0] = binaryTraceVersion
+	buf[1] = binaryTraceIDField
+	binary.BigEndian.PutUint64(buf[10:18], uint64(traceID))
+	buf[18] = binaryTraceSpanField
+	binary.BigEndian.PutUint64(buf[19:27], uint64(spanID))
+	buf[27] = binaryTraceOptsField
+	if sampled {
+		buf[28] = binaryTraceSampled
+	}
+	return buf
+}
+
+// DecodeBinaryTraceContext parses a value created by EncodeBinaryTraceContext
+// or another implementation of the OpenTelemetry binary propagation format.
+// The high 8 bytes of 128 bit trace ids are ignored.
+func DecodeBinaryTraceContext(data []byte) (traceID, spanID int64, sampled bool, err error) {
+	if len(data) < binaryTraceContextLen {
+		return 0, 0, false, fmt.Errorf("%w: got %d bytes, need %d",
+			ErrBinaryTraceContext, len(data), binaryTraceContextLen)
+	}
+	if data[0] != binaryTraceVersion {
+		return 0, 0, false, fmt.Errorf("%w: unsupported version %d",
+			ErrBinaryTraceContext, data[0])
+	}
+	if data[1] != binaryTraceIDField || data[18] != binaryTraceSpanField ||
+		data[27] != binaryTraceOptsField {
+		return 0, 0, false, fmt.Errorf("%w: unexpected field layout",
+			ErrBinaryTraceContext)
+	}
+	traceID = int64(binary.BigEndian.Uint64(data[10:18]))
+	spanID = int64(binary.BigEndian.Uint64(data[19:27]))
+	if traceID == 0 || spanID == 0 {
+		return 0, 0, false, fmt.Errorf("%w: zero trace or span id",
+			ErrBinaryTraceContext)
+	}
+	return traceID, spanID, data[28]&binaryTraceSampled != 0, nil
+}
@@ -0,0 +1,55 @@
+package monkit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBinaryTraceContextRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		traceID, spanID int64
+		sampled         bool
+	}{
+		{1, 2, true},
+		{-5, 1 << 62, false},
+		{NewId(), NewId(), true},
+	} {
+		data := EncodeBinaryTraceContext(test.traceID, test.spanID, test.sampled)
+		if len(data) != 29 {
+			t.Fatalf("unexpected length %d", len(data))
+		}
+		traceID, spanID, sampled, err := DecodeBinaryTraceContext(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if traceID != test.traceID || spanID != test.spanID || sampled != test.sampled {
+			t.Fatalf("got %d %d %v, expected %d %d %v", traceID, spanID, sampled,
+				test.traceID, test.spanID, test.sampled)
+		}
+	}
+}
+
+func TestBinaryTraceContextMalformed(t *testing.T) {
+	valid := EncodeBinaryTraceContext(1, 2, true)
+	modified := func(i int, b byte) []byte {
+		data := append([]byte(nil), valid...)
+		data[i] = b
+		return data
+	}
+
+	for name, data := range map[string][]byte{
+		"empty":       nil,
+		"short":       valid[:28],
+		"version":     modified(0, 1),
+		"trace field": modified(1, 5),
+		"span field":  modified(18, 5),
+		"opts field":  modified(27, 5),
+		"zero trace":  EncodeBinaryTraceContext(0, 2, true),
+		"zero span":   EncodeBinaryTraceContext(1, 0, true),
+	} {
+		_, _, _, err := DecodeBinaryTraceContext(data)
+		if !errors.Is(err, ErrBinaryTraceContext) {
+			t.Fatalf("%s: expected ErrBinaryTraceContext, got %v", name, err)
+		}
+	}
+}
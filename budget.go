@@ -0,0 +1,147 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/monotime"
+)
+
+// SetBudget sets how long a call of this Func is expected to take. Calls that
+// are still running once their budget is exhausted get an overrun=true
+// annotation on their Span and count towards the Func's overruns stat. The
+// work itself is not canceled. A budget of zero, the default, disables this.
+//
+// In-flight calls are checked by a single shared sweeper goroutine, which
+// only runs while calls with a budget are in flight.
+func (f *Func) SetBudget(d time.Duration) {
+	atomic.StoreInt64(&f.budget, int64(d))
+}
+
+// Budget returns the duration set by SetBudget.
+func (f *Func) Budget() time.Duration {
+	return time.Duration(atomic.LoadInt64(&f.budget))
+}
+
+// Overruns returns how many calls of this Func exceeded their budget.
+func (f *Func) Overruns() int64 { return atomic.LoadInt64(&f.overruns) }
+
+// Stats implements the StatSource interface. Funcs with a budget additionally
+// report their overruns, Funcs tracking their callers the calls per caller,
+// Funcs with percentile thresholds whether they are breached, and Funcs with
+// an apdex target their apdex score.
+func (f *Func) Stats(cb func(key SeriesKey, field string, val float64)) {
+	f.FuncStats.Stats(cb)
+	if f.Budget() > 0 {
+		cb(f.key, "overruns", float64(f.Overruns()))
+	}
+	f.callerStats(cb)
+	f.sloStats(cb)
+	f.apdexStats(cb)
+}
+
+func (s *Span) markOverrun() {
+	s.mtx.Lock()
+	if s.overrun {
+		s.mtx.Unlock()
+		return
+	}
+	s.overrun = true
+	s.addAnnotation("overrun", "true")
+	s.mtx.Unlock()
+	atomic.AddInt64(&s.f.overruns, 1)
+}
+
+// budgetSweeper tracks the in-flight Spans of Funcs with a budget and marks
+// the ones that run past their deadline.
+type budgetSweeper struct {
+	mtx     sync.Mutex
+	spans   map[*Span]time.Time
+	running bool
+	wake    chan struct{}
+}
+
+var budgets = budgetSweeper{
+	spans: map[*Span]time.Time{},
+	wake:  make(chan struct{}, 1),
+}
+
+func (b *budgetSweeper) add(s *Span, budget time.Duration) {
+	b.mtx.Lock()
+	b.spans[s] = s.start.Add(budget)
+	start := !b.running
+	b.running = true
+	b.mtx.Unlock()
+	if start {
+		go b.run()
+		return
+	}
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (b *budgetSweeper) remove(s *Span) {
+	b.mtx.Lock()
+	delete(b.spans, s)
+	b.mtx.Unlock()
+}
+
+func (b *budgetSweeper) run() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-b.wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
+
+		var overrun []*Span
+		var next time.Time
+		now := monotime.Now()
+		b.mtx.Lock()
+		for s, deadline := range b.spans {
+			if !now.Before(deadline) {
+				overrun = append(overrun, s)
+				delete(b.spans, s)
+			} else if next.IsZero() || deadline.Before(next) {
+				next = deadline
+			}
+		}
+		if len(b.spans) == 0 {
+			b.running = false
+		}
+		running := b.running
+		b.mtx.Unlock()
+
+		for _, s := range overrun {
+			s.markOverrun()
+		}
+		if !running {
+			return
+		}
+		timer.Reset(next.Sub(now))
+	}
+}
@@ -0,0 +1,81 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func hasOverrun(s *Span) bool {
+	for _, a := range s.Annotations() {
+		if a.Name == "overrun" && a.Value == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFuncBudget(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("budget")
+	f := mon.FuncNamed("slow")
+	f.SetBudget(10 * time.Millisecond)
+
+	// the sweeper marks the span while the call is still running.
+	ctx := context.Background()
+	finish := f.Task(&ctx)
+	span := SpanFromCtx(ctx)
+	deadline := time.Now().Add(10 * time.Second)
+	for !hasOverrun(span) {
+		if time.Now().After(deadline) {
+			t.Fatal("span was never marked as overrun")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	finish(nil)
+	if f.Overruns() != 1 {
+		t.Fatalf("expected 1 overrun, got %d", f.Overruns())
+	}
+
+	fast := mon.FuncNamed("fast")
+	fast.SetBudget(time.Hour)
+	ctx = context.Background()
+	finish = fast.Task(&ctx)
+	span = SpanFromCtx(ctx)
+	finish(nil)
+	if hasOverrun(span) || fast.Overruns() != 0 {
+		t.Fatal("unexpected overrun")
+	}
+
+	mon.FuncNamed("other")
+	stats := Collect(mon)
+	if stats["function,name=slow,scope=budget overruns"] != 1 ||
+		stats["function,name=fast,scope=budget overruns"] != 0 {
+		t.Fatalf("unexpected overrun stats %v", stats)
+	}
+	if _, exists := stats["function,name=other,scope=budget overruns"]; exists {
+		t.Fatal("expected no overrun stat without a budget")
+	}
+}
+
+func TestFuncBudgetDisabledWhileRunning(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("budget")
+	f := mon.FuncNamed("disabled")
+	f.SetBudget(20 * time.Millisecond)
+
+	ctx := context.Background()
+	finish := f.Task(&ctx)
+	f.SetBudget(0)
+	finish(nil)
+
+	// the finished span left the sweeper although the budget is gone.
+	budgets.mtx.Lock()
+	_, tracked := budgets.spans[SpanFromCtx(ctx)]
+	budgets.mtx.Unlock()
+	if tracked {
+		t.Fatal("finished span is still tracked")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if f.Overruns() != 0 || hasOverrun(SpanFromCtx(ctx)) {
+		t.Fatalf("unexpected overrun of a finished span")
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// bufferedValSize is how many observations a BufferedIntVal shard holds
+// before they are merged into the distribution.
+const bufferedValSize = 256
+
+// BufferedIntVal is like IntVal, but trades timeliness for throughput when
+// observing values at a very high rate from many goroutines. Observations are
+// collected in buffers local to the current P (as far as sync.Pool can tell)
+// and merged into the underlying distribution in batches, so most calls to
+// Observe take an uncontended lock. Stats, Quantile and Flush merge all
+// pending observations first, so what they report is complete.
+//
+// Constructed using NewBufferedIntVal, though its expected usage is like:
+//
+//	var mon = monkit.Package()
+//
+//	func MyFunc() {
+//	  ...
+//	  mon.BufferedIntVal("size").Observe(val)
+//	  ...
+//	}
+type BufferedIntVal struct {
+	val  *IntVal
+	pool sync.Pool
+
+	mtx    sync.Mutex
+	shards []*intValShard
+	next   int
+}
+
+type intValShard struct {
+	mtx  sync.Mutex
+	vals []int64
+}
+
+// NewBufferedIntVal creates a BufferedIntVal
+func NewBufferedIntVal(key SeriesKey) *BufferedIntVal {
+	return &BufferedIntVal{val: NewIntVal(key)}
+}
+
+// shard returns a shard for the current goroutine to buffer into. The pool
+// keeps shards close to the P that last used them. It is emptied by GCs, in
+// which case the existing shards are handed out again, so there are never
+// more shards than Ps.
+func (v *BufferedIntVal) shard() *intValShard {
+	if sh, ok := v.pool.Get().(*intValShard); ok {
+		return sh
+	}
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if len(v.shards) < runtime.GOMAXPROCS(0) {
+		sh := &intValShard{vals: make([]int64, 0, bufferedValSize)}
+		v.shards = append(v.shards, sh)
+		return sh
+	}
+	sh := v.shards[v.next%len(v.shards)]
+	v.next++
+	return sh
+}
+
+// Observe observes an integer value
+func (v *BufferedIntVal) Observe(val int64) {
+	sh := v.shard()
+	sh.mtx.Lock()
+	sh.vals = append(sh.vals, val)
+	if len(sh.vals) >= bufferedValSize {
+		v.merge(sh)
+	}
+	sh.mtx.Unlock()
+	v.pool.Put(sh)
+}
+
+// merge inserts the buffered values of sh and must be called with sh.mtx held.
+func (v *BufferedIntVal) merge(sh *intValShard) {
+	if len(sh.vals) == 0 {
+		return
+	}
+	v.val.mtx.Lock()
+	for _, val := range sh.vals {
+		v.val.dist.Insert(val)
+	}
+	v.val.mtx.Unlock()
+	v.val.updated.touch()
+	sh.vals = sh.vals[:0]
+}
+
+// Flush merges all buffered observations into the distribution.
+func (v *BufferedIntVal) Flush() {
+	v.mtx.Lock()
+	shards := append([]*intValShard(nil), v.shards...)
+	v.mtx.Unlock()
+	for _, sh := range shards {
+		sh.mtx.Lock()
+		v.merge(sh)
+		sh.mtx.Unlock()
+	}
+}
+
+// Stats implements the StatSource interface.
+func (v *BufferedIntVal) Stats(cb func(key SeriesKey, field string, val float64)) {
+	v.Flush()
+	v.val.Stats(cb)
+}
+
+// Snapshot returns a summary of the observed values, see DistSnapshot.
+func (v *BufferedIntVal) Snapshot() DistSnapshot {
+	v.Flush()
+	return v.val.Snapshot()
+}
+
+// Quantile returns an estimate of the requested quantile of observed values.
+// 0 <= quantile <= 1
+func (v *BufferedIntVal) Quantile(quantile float64) (rv int64) {
+	v.Flush()
+	return v.val.Quantile(quantile)
+}
+
+// LastUpdated implements the LastUpdatedSource interface. It is the time
+// observations were last merged.
+func (v *BufferedIntVal) LastUpdated() time.Time { return v.val.LastUpdated() }
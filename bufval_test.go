@@ -0,0 +1,67 @@
+package monkit
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBufferedIntVal(t *testing.T) {
+	v := NewBufferedIntVal(NewSeriesKey("size"))
+
+	const goroutines, perGoroutine = 8, 1000
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; i <= perGoroutine; i++ {
+				v.Observe(int64(i))
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := Collect(v)
+	if stats["size count"] != goroutines*perGoroutine {
+		t.Fatalf("expected count %d, got %v", goroutines*perGoroutine, stats["size count"])
+	}
+	if expected := float64(goroutines * perGoroutine * (perGoroutine + 1) / 2); stats["size sum"] != expected {
+		t.Fatalf("expected sum %v, got %v", expected, stats["size sum"])
+	}
+	if stats["size min"] != 1 || stats["size max"] != perGoroutine {
+		t.Fatalf("unexpected min/max in %v", stats)
+	}
+
+	// single observations stay buffered until a flush.
+	v.Observe(5000)
+	if v.val.dist.Count != goroutines*perGoroutine {
+		t.Fatalf("expected the observation to be buffered, got count %d", v.val.dist.Count)
+	}
+	v.Flush()
+	if v.val.dist.Count != goroutines*perGoroutine+1 || v.val.dist.High != 5000 {
+		t.Fatalf("expected the flush to merge, got count %d", v.val.dist.Count)
+	}
+}
+
+func BenchmarkIntValObserveParallel(b *testing.B) {
+	v := NewIntVal(NewSeriesKey("size"))
+	b.RunParallel(func(pb *testing.PB) {
+		var i int64
+		for pb.Next() {
+			v.Observe(i)
+			i++
+		}
+	})
+}
+
+func BenchmarkBufferedIntValObserveParallel(b *testing.B) {
+	v := NewBufferedIntVal(NewSeriesKey("size"))
+	b.RunParallel(func(pb *testing.PB) {
+		var i int64
+		for pb.Next() {
+			v.Observe(i)
+			i++
+		}
+	})
+	v.Flush()
+}
@@ -16,9 +16,115 @@ package monkit
 
 import (
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
+// callerCounts counts the calls of a Func per call site.
+type callerCounts struct {
+	mtx    sync.Mutex
+	counts map[string]int64
+	other  int64
+}
+
+// TrackCallers makes the Func count its calls per call site, reported as
+// the calls field of series tagged with caller=dir/file.go:line. Up to limit
+// distinct call sites are tracked, calls from any further ones are counted
+// as caller=other. Counting has to look up the call site on every call, and
+// every call site becomes a series, so this is meant for investigating which
+// callers dominate a Func rather than to be left on everywhere. A limit of
+// zero, the default, turns tracking off, and the counts are kept.
+func (f *Func) TrackCallers(limit int) {
+	atomic.StoreInt32(&f.callerLimit, int32(limit))
+}
+
+// monkitFuncPrefix prefixes the names of the functions of this package, as
+// reported by runtime.Frame.Function.
+const monkitFuncPrefix = "github.com/spacemonkeygo/monkit/v3."
+
+// recordCaller records the first caller outside of this package, so that
+// calls through ContinueTrace, Measure and the like are attributed to their
+// callers rather than to monkit. Tests of this package count as callers.
+func (f *Func) recordCaller() {
+	limit := atomic.LoadInt32(&f.callerLimit)
+	if limit <= 0 {
+		return
+	}
+	var pcs [16]uintptr
+	// skip runtime.Callers and recordCaller.
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	caller := "unknown"
+	for {
+		frame, more := frames.Next()
+		// method values like Scope.TaskNamed returns go through a wrapper.
+		internal := frame.File == "<autogenerated>" || strings.HasSuffix(frame.Function, "-fm") ||
+			(strings.HasPrefix(frame.Function, monkitFuncPrefix) && !strings.HasSuffix(frame.File, "_test.go"))
+		if !internal {
+			if frame.File != "" {
+				caller = shortFile(frame.File) + ":" + strconv.Itoa(frame.Line)
+			}
+			break
+		}
+		if !more {
+			break
+		}
+	}
+
+	c := &f.callers
+	c.mtx.Lock()
+	overflowed := false
+	if _, ok := c.counts[caller]; ok || len(c.counts) < int(limit) {
+		if c.counts == nil {
+			c.counts = map[string]int64{}
+		}
+		c.counts[caller]++
+	} else {
+		c.other++
+		overflowed = c.other == 1
+	}
+	c.mtx.Unlock()
+	if overflowed {
+		f.scope.r.logf("monkit: %s has more than %d call sites, counting calls from %s and further ones as caller=other",
+			f.FullName(), limit, caller)
+	}
+}
+
+func (f *Func) callerStats(cb func(key SeriesKey, field string, val float64)) {
+	c := &f.callers
+	c.mtx.Lock()
+	callers := make([]string, 0, len(c.counts))
+	for caller := range c.counts {
+		callers = append(callers, caller)
+	}
+	counts := make(map[string]int64, len(c.counts))
+	for caller, count := range c.counts {
+		counts[caller] = count
+	}
+	other := c.other
+	c.mtx.Unlock()
+
+	sort.Strings(callers)
+	for _, caller := range callers {
+		cb(f.key.WithTag("caller", caller), "calls", float64(counts[caller]))
+	}
+	if other > 0 {
+		cb(f.key.WithTag("caller", "other"), "calls", float64(other))
+	}
+}
+
+// shortFile returns the last directory and the name of a source file path.
+func shortFile(file string) string {
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			return file[j+1:]
+		}
+	}
+	return file
+}
+
 func callerPackage(frames int) string {
 	var pc [1]uintptr
 	if runtime.Callers(frames+2, pc[:]) != 1 {
@@ -33,20 +139,20 @@ func callerPackage(frames int) string {
 	return strings.Join(slash_pieces[:len(slash_pieces)-1], "/") + "/" + dot_pieces[0]
 }
 
-func callerFunc(frames int) string {
+func callerFunc(frames int) (funcname, fullName string) {
 	var pc [1]uintptr
 	if runtime.Callers(frames+3, pc[:]) != 1 {
-		return "unknown"
+		return "unknown", ""
 	}
 	frame, _ := runtime.CallersFrames(pc[:]).Next()
 	if frame.Function == "" {
-		return "unknown"
+		return "unknown", ""
 	}
 	funcname, ok := extractFuncName(frame.Function)
 	if !ok {
-		return "unknown"
+		return "unknown", ""
 	}
-	return funcname
+	return funcname, frame.Function
 }
 
 // extractFuncName splits fully qualified function name:
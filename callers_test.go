@@ -1,6 +1,10 @@
 package monkit
 
-import "testing"
+import (
+	"context"
+	"strings"
+	"testing"
+)
 
 func TestExtractFuncName(t *testing.T) {
 	for _, test := range []struct {
@@ -22,3 +26,73 @@ func TestExtractFuncName(t *testing.T) {
 		}
 	}
 }
+
+func TestTrackCallers(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("callers")
+	f := mon.FuncNamed("tracked")
+	f.TrackCallers(2)
+
+	a := func() {
+		ctx := context.Background()
+		f.Task(&ctx)(nil)
+	}
+	b := func() {
+		ctx := context.Background()
+		mon.TaskNamed("tracked")(&ctx)(nil)
+	}
+	c := func() {
+		ctx := context.Background()
+		f.Task(&ctx)(nil)
+	}
+	for i := 0; i < 3; i++ {
+		a()
+	}
+	for i := 0; i < 2; i++ {
+		b()
+	}
+	c()
+
+	counts := map[float64]string{}
+	for key, val := range Collect(mon) {
+		if !strings.HasSuffix(key, " calls") {
+			continue
+		}
+		if strings.Contains(key, "caller=other") {
+			counts[-val] = key
+			continue
+		}
+		if !strings.Contains(key, "callers_test.go:") {
+			t.Fatalf("unexpected caller in %q", key)
+		}
+		counts[val] = key
+	}
+	if len(counts) != 3 || counts[3] == "" || counts[2] == "" || counts[-1] == "" {
+		t.Fatalf("expected 3 and 2 calls from two call sites and 1 other, got %v", counts)
+	}
+}
+
+func TestTrackCallersIndirect(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("callers")
+	mon.FuncNamed("measured").TrackCallers(10)
+
+	_ = Measure(mon, "measured", func() error { return nil })
+
+	ctx := context.Background()
+	mon.FuncNamed("TestTrackCallersIndirect").TrackCallers(10)
+	mon.ContinueTrace(&ctx, 1, 2, false, nil)(nil)
+	mon.LinkTrace(&ctx, 1, 2, false, nil)(nil)
+
+	callers := 0
+	for key := range Collect(mon) {
+		if !strings.HasSuffix(key, " calls") || !strings.Contains(key, "caller=") {
+			continue
+		}
+		if !strings.Contains(key, "callers_test.go:") {
+			t.Fatalf("unexpected caller in %q", key)
+		}
+		callers++
+	}
+	if callers != 3 {
+		t.Fatalf("expected 3 call sites, got %d", callers)
+	}
+}
@@ -74,3 +74,96 @@ func loadSpanObserverTuple(addr **spanObserverTuple) (val *spanObserverTuple) {
 	bigHonkinMutex.Unlock()
 	return val
 }
+
+func loadObserverPool(addr **observerPool) (val *observerPool) {
+	bigHonkinMutex.Lock()
+	val = *addr
+	bigHonkinMutex.Unlock()
+	return val
+}
+
+func swapObserverPool(addr **observerPool, val *observerPool) (old *observerPool) {
+	bigHonkinMutex.Lock()
+	old = *addr
+	*addr = val
+	bigHonkinMutex.Unlock()
+	return old
+}
+
+func loadSlowSpanRef(addr **slowSpanRef) (val *slowSpanRef) {
+	bigHonkinMutex.Lock()
+	val = *addr
+	bigHonkinMutex.Unlock()
+	return val
+}
+
+func storeSlowSpanRef(addr **slowSpanRef, val *slowSpanRef) {
+	bigHonkinMutex.Lock()
+	*addr = val
+	bigHonkinMutex.Unlock()
+}
+
+func loadContextAnnotatorRef(addr **contextAnnotatorRef) (val *contextAnnotatorRef) {
+	bigHonkinMutex.Lock()
+	val = *addr
+	bigHonkinMutex.Unlock()
+	return val
+}
+
+func storeContextAnnotatorRef(addr **contextAnnotatorRef, val *contextAnnotatorRef) {
+	bigHonkinMutex.Lock()
+	*addr = val
+	bigHonkinMutex.Unlock()
+}
+
+func loadTraceIDFromContextRef(addr **traceIDFromContextRef) (val *traceIDFromContextRef) {
+	bigHonkinMutex.Lock()
+	val = *addr
+	bigHonkinMutex.Unlock()
+	return val
+}
+
+func storeTraceIDFromContextRef(addr **traceIDFromContextRef, val *traceIDFromContextRef) {
+	bigHonkinMutex.Lock()
+	*addr = val
+	bigHonkinMutex.Unlock()
+}
+
+func loadLoggerRef(addr **loggerRef) (val *loggerRef) {
+	bigHonkinMutex.Lock()
+	val = *addr
+	bigHonkinMutex.Unlock()
+	return val
+}
+
+func storeLoggerRef(addr **loggerRef, val *loggerRef) {
+	bigHonkinMutex.Lock()
+	*addr = val
+	bigHonkinMutex.Unlock()
+}
+
+func loadClockRef(addr **clockRef) (val *clockRef) {
+	bigHonkinMutex.Lock()
+	val = *addr
+	bigHonkinMutex.Unlock()
+	return val
+}
+
+func storeClockRef(addr **clockRef, val *clockRef) {
+	bigHonkinMutex.Lock()
+	*addr = val
+	bigHonkinMutex.Unlock()
+}
+
+func loadGlobalTagsRef(addr **globalTagsRef) (val *globalTagsRef) {
+	bigHonkinMutex.Lock()
+	val = *addr
+	bigHonkinMutex.Unlock()
+	return val
+}
+
+func storeGlobalTagsRef(addr **globalTagsRef, val *globalTagsRef) {
+	bigHonkinMutex.Lock()
+	*addr = val
+	bigHonkinMutex.Unlock()
+}
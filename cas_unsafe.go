@@ -69,3 +69,88 @@ func loadSpanObserverTuple(addr **spanObserverTuple) (val *spanObserverTuple) {
 	return (*spanObserverTuple)(atomic.LoadPointer(
 		(*unsafe.Pointer)(unsafe.Pointer(addr))))
 }
+
+//
+// *observerPool atomic functions
+//
+
+func loadObserverPool(addr **observerPool) (val *observerPool) {
+	return (*observerPool)(atomic.LoadPointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr))))
+}
+
+func swapObserverPool(addr **observerPool, val *observerPool) (old *observerPool) {
+	return (*observerPool)(atomic.SwapPointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr)),
+		unsafe.Pointer(val)))
+}
+
+//
+// *slowSpanRef atomic functions
+//
+
+func loadSlowSpanRef(addr **slowSpanRef) (val *slowSpanRef) {
+	return (*slowSpanRef)(atomic.LoadPointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr))))
+}
+
+func storeSlowSpanRef(addr **slowSpanRef, val *slowSpanRef) {
+	atomic.StorePointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr)),
+		unsafe.Pointer(val))
+}
+
+func loadContextAnnotatorRef(addr **contextAnnotatorRef) (val *contextAnnotatorRef) {
+	return (*contextAnnotatorRef)(atomic.LoadPointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr))))
+}
+
+func storeContextAnnotatorRef(addr **contextAnnotatorRef, val *contextAnnotatorRef) {
+	atomic.StorePointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr)),
+		unsafe.Pointer(val))
+}
+
+func loadTraceIDFromContextRef(addr **traceIDFromContextRef) (val *traceIDFromContextRef) {
+	return (*traceIDFromContextRef)(atomic.LoadPointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr))))
+}
+
+func storeTraceIDFromContextRef(addr **traceIDFromContextRef, val *traceIDFromContextRef) {
+	atomic.StorePointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr)),
+		unsafe.Pointer(val))
+}
+
+func loadLoggerRef(addr **loggerRef) (val *loggerRef) {
+	return (*loggerRef)(atomic.LoadPointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr))))
+}
+
+func storeLoggerRef(addr **loggerRef, val *loggerRef) {
+	atomic.StorePointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr)),
+		unsafe.Pointer(val))
+}
+
+func loadClockRef(addr **clockRef) (val *clockRef) {
+	return (*clockRef)(atomic.LoadPointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr))))
+}
+
+func storeClockRef(addr **clockRef, val *clockRef) {
+	atomic.StorePointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr)),
+		unsafe.Pointer(val))
+}
+
+func loadGlobalTagsRef(addr **globalTagsRef) (val *globalTagsRef) {
+	return (*globalTagsRef)(atomic.LoadPointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr))))
+}
+
+func storeGlobalTagsRef(addr **globalTagsRef, val *globalTagsRef) {
+	atomic.StorePointer(
+		(*unsafe.Pointer)(unsafe.Pointer(addr)),
+		unsafe.Pointer(val))
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package chi
+
+import (
+	"net/http"
+
+	gochi "github.com/go-chi/chi/v5"
+
+	monkithttp "github.com/spacemonkeygo/monkit/v3/http"
+)
+
+// Middleware records the route pattern chi matched, such as "/users/{id}",
+// and its URL parameters with monkithttp.SetRoute. It has to be installed on
+// the router with Use, within a monkithttp.TraceHandler. Requests that
+// matched no route are left alone.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer setRoute(r)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func setRoute(r *http.Request) {
+	rctx := gochi.RouteContext(r.Context())
+	if rctx == nil {
+		return
+	}
+	route := rctx.RoutePattern()
+	if route == "" {
+		return
+	}
+	var params map[string]string
+	if n := len(rctx.URLParams.Keys); n > 0 {
+		params = make(map[string]string, n)
+		for i, key := range rctx.URLParams.Keys {
+			params[key] = rctx.URLParams.Values[i]
+		}
+	}
+	monkithttp.SetRoute(r.Context(), route, params)
+}
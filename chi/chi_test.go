@@ -0,0 +1,77 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package chi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gochi "github.com/go-chi/chi/v5"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	monkithttp "github.com/spacemonkeygo/monkit/v3/http"
+)
+
+func TestMiddleware(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("chi")
+
+	var span *monkit.Span
+	capture := func(w http.ResponseWriter, r *http.Request) {
+		span = monkit.SpanFromCtx(r.Context())
+	}
+
+	router := gochi.NewRouter()
+	router.Use(Middleware)
+	router.Get("/users/{id}", capture)
+	router.Route("/orgs/{org}", func(r gochi.Router) {
+		r.Get("/teams/{team}", capture)
+	})
+	handler := monkithttp.TraceHandlerWithOptions(router, mon, monkithttp.RouteRootName(),
+		monkithttp.RouteParams(func(route string, params map[string]string) map[string]string {
+			return params
+		}))
+
+	for _, tc := range []struct{ path, route string }{
+		{"/users/42", "/users/{id}"},
+		// last, so the param check below sees its span.
+		{"/orgs/acme/teams/7", "/orgs/{org}/teams/{team}"},
+	} {
+		path, expected := tc.path, tc.route
+		span = nil
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		if span == nil {
+			t.Fatalf("%s: handler not called", path)
+		}
+		if name := span.Trace().RootName(); name != "GET "+expected {
+			t.Fatalf("%s: expected the route as name, got %q", path, name)
+		}
+		if route := annotation(span, "http.route"); route != expected {
+			t.Fatalf("%s: expected the route annotation, got %q", path, route)
+		}
+	}
+	if org := annotation(span, "http.route.param.org"); org != "acme" {
+		t.Fatalf("expected the org param, got %q", org)
+	}
+
+	// requests no route matched keep their default name.
+	var trace *monkit.Trace
+	router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		trace = monkit.SpanFromCtx(r.Context()).Trace()
+		http.NotFound(w, r)
+	})
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+	if trace == nil || trace.RootName() != "chi.traceHandler.ServeHTTP" {
+		t.Fatalf("expected the default name for unmatched requests, got %v", trace)
+	}
+}
+
+func annotation(s *monkit.Span, name string) string {
+	for _, a := range s.Annotations() {
+		if a.Name == name {
+			return a.Value
+		}
+	}
+	return ""
+}
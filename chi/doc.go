@@ -0,0 +1,22 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+/*
+Package chi records the route pattern a github.com/go-chi/chi router matched
+on the span of requests traced by monkit's http.TraceHandler:
+
+	router := gochi.NewRouter()
+	router.Use(monkitchi.Middleware)
+	router.Get("/users/{id}", getUser)
+	handler := monkithttp.TraceHandlerWithOptions(router, mon, monkithttp.RouteRootName())
+
+TraceHandler has to wrap the router, so that the span exists before routing,
+while Middleware has to be installed inside of it with Use, as the route is
+only known to chi once it routed the request. Middleware records the route
+after the handler returns, when chi has resolved the full pattern including
+mounted subrouters.
+
+It lives in its own module so that the core monkit module doesn't depend on
+chi.
+*/
+package chi // import "github.com/spacemonkeygo/monkit/v3/chi"
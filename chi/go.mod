@@ -0,0 +1,10 @@
+module github.com/spacemonkeygo/monkit/v3/chi
+
+go 1.19
+
+require (
+	github.com/go-chi/chi/v5 v5.0.12
+	github.com/spacemonkeygo/monkit/v3 v3.0.0
+)
+
+replace github.com/spacemonkeygo/monkit/v3 => ../
@@ -0,0 +1,44 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// SetChildCountAnnotations controls whether Spans are annotated with
+// span.child_count when they finish, holding the number of child Spans that
+// were started under them. A high count on a single Span is a quick way to
+// spot fan-out such as N+1 query patterns. Only Spans started while this is
+// enabled count their children, and it is off by default.
+func (r *Registry) SetChildCountAnnotations(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&r.childCountAnnotations, v)
+}
+
+func (r *Registry) countChildren() bool {
+	return atomic.LoadInt32(&r.childCountAnnotations) != 0
+}
+
+// annotateChildCount must be called with s.mtx held.
+func (s *Span) annotateChildCount() {
+	if s.countChildren {
+		s.addAnnotation("span.child_count", strconv.Itoa(s.childCount))
+	}
+}
@@ -0,0 +1,40 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChildCountAnnotations(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("children")
+
+	run := func() (parent, child *Span) {
+		ctx := context.Background()
+		finish := mon.TaskNamed("parent")(&ctx)
+		parent = SpanFromCtx(ctx)
+		for i := 0; i < 3; i++ {
+			cctx := ctx
+			mon.TaskNamed("child")(&cctx)(nil)
+			child = SpanFromCtx(cctx)
+		}
+		finish(nil)
+		return parent, child
+	}
+
+	parent, _ := run()
+	for _, a := range parent.Annotations() {
+		if a.Name == "span.child_count" {
+			t.Fatal("unexpected annotation while disabled:", a)
+		}
+	}
+
+	r.SetChildCountAnnotations(true)
+	parent, child := run()
+	if !hasAnnotation(parent, "span.child_count", "3") {
+		t.Fatal("expected span.child_count=3, got", parent.Annotations())
+	}
+	if !hasAnnotation(child, "span.child_count", "0") {
+		t.Fatal("expected span.child_count=0, got", child.Annotations())
+	}
+}
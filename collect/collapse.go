@@ -0,0 +1,76 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package collect
+
+import (
+	"strconv"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// CollapseRecursiveSpans collapses every chain of Spans of the same Func in
+// spans, where each is the parent of the next, such as the Spans of a
+// recursive function, into its outermost Span. The outermost Span gets a
+// recursion.depth annotation with the number of nested levels the chain had,
+// and the children of the collapsed Spans that belong to another Func are
+// linked to it instead, see FinishedSpan.ParentId. The Spans keep their
+// order, and spans itself is not modified.
+func CollapseRecursiveSpans(spans []*FinishedSpan) []*FinishedSpan {
+	byId := make(map[int64]*FinishedSpan, len(spans))
+	for _, s := range spans {
+		byId[s.Span.Id()] = s
+	}
+
+	// outermost maps every Span to the outermost Span of its chain, and depth
+	// to how deep in the chain it is, starting at 1.
+	outermost := make(map[int64]int64, len(spans))
+	depth := make(map[int64]int, len(spans))
+	var walk func(s *FinishedSpan) (int64, int)
+	walk = func(s *FinishedSpan) (int64, int) {
+		id := s.Span.Id()
+		if d, ok := depth[id]; ok {
+			return outermost[id], d
+		}
+		outer, d := id, 1
+		if parentId, ok := s.ParentId(); ok {
+			if parent := byId[parentId]; parent != nil && parent.Span.Func() == s.Span.Func() {
+				outer, d = walk(parent)
+				d++
+			}
+		}
+		outermost[id], depth[id] = outer, d
+		return outer, d
+	}
+	maxDepth := make(map[int64]int, len(spans))
+	for _, s := range spans {
+		outer, d := walk(s)
+		if d > maxDepth[outer] {
+			maxDepth[outer] = d
+		}
+	}
+
+	rv := make([]*FinishedSpan, 0, len(maxDepth))
+	for _, s := range spans {
+		id := s.Span.Id()
+		if outermost[id] != id {
+			continue
+		}
+		parentId, ok := s.ParentId()
+		reparent := ok && byId[parentId] != nil && outermost[parentId] != parentId
+		if !reparent && maxDepth[id] == 1 {
+			rv = append(rv, s)
+			continue
+		}
+		cp := *s
+		if reparent {
+			cp.parentId, cp.reparented = outermost[parentId], true
+		}
+		if maxDepth[id] > 1 {
+			cp.annotations = append(append([]monkit.Annotation(nil), s.annotations...),
+				monkit.Annotation{Name: "recursion.depth", Value: strconv.Itoa(maxDepth[id])})
+		}
+		rv = append(rv, &cp)
+	}
+	return rv
+}
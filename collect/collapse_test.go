@@ -0,0 +1,76 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package collect
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestCollapseRecursiveSpans(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("collapse")
+	leaf := func(ctx context.Context) {
+		defer mon.TaskNamed("leaf")(&ctx)(nil)
+	}
+	var walk func(ctx context.Context, n int)
+	walk = func(ctx context.Context, n int) {
+		defer mon.TaskNamed("walk")(&ctx)(nil)
+		if n > 1 {
+			walk(ctx, n-1)
+		} else {
+			leaf(ctx)
+		}
+	}
+
+	// root-TRACED -> walk -> walk -> walk -> walk -> leaf
+	ctx := context.Background()
+	finish := mon.TaskNamed("root")(&ctx)
+	spans := CollectSpans(ctx, func(ctx context.Context) { walk(ctx, 4) })
+	finish(nil)
+	if len(spans) != 6 {
+		t.Fatalf("expected 6 spans, got %d", len(spans))
+	}
+
+	collapsed := CollapseRecursiveSpans(spans)
+	byName := map[string]*FinishedSpan{}
+	for _, s := range collapsed {
+		byName[s.Span.Func().ShortName()] = s
+	}
+	if len(collapsed) != 3 || len(byName) != 3 {
+		t.Fatalf("unexpected spans %v", byName)
+	}
+	root, walkSpan, leafSpan := byName["root-TRACED"], byName["walk"], byName["leaf"]
+	if parentId, _ := walkSpan.ParentId(); parentId != root.Span.Id() {
+		t.Fatal("walk should stay a child of the root")
+	}
+	if parentId, _ := leafSpan.ParentId(); parentId != walkSpan.Span.Id() {
+		t.Fatal("leaf should be a child of the outermost walk")
+	}
+	if !hasAnnotation(walkSpan, "recursion.depth", "4") {
+		t.Fatalf("unexpected annotations %v", walkSpan.Annotations())
+	}
+	for _, s := range []*FinishedSpan{root, leafSpan} {
+		for _, a := range s.Annotations() {
+			if a.Name == "recursion.depth" {
+				t.Fatalf("%s unexpectedly annotated", s.Span.Func().ShortName())
+			}
+		}
+	}
+	for _, s := range spans {
+		if len(s.annotations) != 0 {
+			t.Fatal("input spans were modified")
+		}
+	}
+}
+
+func hasAnnotation(s *FinishedSpan, name, value string) bool {
+	for _, a := range s.Annotations() {
+		if a.Name == name && a.Value == value {
+			return true
+		}
+	}
+	return false
+}
@@ -30,6 +30,34 @@ type FinishedSpan struct {
 	Err      error
 	Panicked bool
 	Finish   time.Time
+
+	// set by TopNSpans and CollapseRecursiveSpans when the parent was dropped
+	parentId   int64
+	reparented bool
+
+	// added by CollapseRecursiveSpans
+	annotations []monkit.Annotation
+}
+
+// ParentId returns the id of the parent of the Span, like
+// monkit.Span.ParentId, unless TopNSpans or CollapseRecursiveSpans dropped the
+// parent, in which case it is the closest ancestor that was kept.
+func (s *FinishedSpan) ParentId() (int64, bool) {
+	if s.reparented {
+		return s.parentId, true
+	}
+	return s.Span.ParentId()
+}
+
+// Annotations returns the annotations of the Span, like
+// monkit.Span.Annotations, along with any added while transforming the
+// collected Spans, such as by CollapseRecursiveSpans.
+func (s *FinishedSpan) Annotations() []monkit.Annotation {
+	annotations := s.Span.Annotations()
+	if len(s.annotations) == 0 {
+		return annotations
+	}
+	return append(annotations, s.annotations...)
 }
 
 type spanParent struct {
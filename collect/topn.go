@@ -0,0 +1,66 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package collect
+
+import (
+	"sort"
+)
+
+// TopNSpans reduces spans to the roots, the Spans whose parent is not part of
+// spans, and the n Spans that took the longest, dropping the rest to save on
+// storage of traces that are always collected. Kept Spans whose parent was
+// dropped are linked to their closest kept ancestor instead, see
+// FinishedSpan.ParentId, so the result still forms a tree. The Spans keep
+// their order, and spans itself is not modified.
+func TopNSpans(spans []*FinishedSpan, n int) []*FinishedSpan {
+	byId := make(map[int64]*FinishedSpan, len(spans))
+	for _, s := range spans {
+		byId[s.Span.Id()] = s
+	}
+
+	keep := make(map[int64]bool, n+1)
+	var candidates []*FinishedSpan
+	for _, s := range spans {
+		if parentId, ok := s.ParentId(); !ok || byId[parentId] == nil {
+			keep[s.Span.Id()] = true
+		} else {
+			candidates = append(candidates, s)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return spanDuration(candidates[i]) > spanDuration(candidates[j])
+	})
+	if n < 0 {
+		n = 0
+	}
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+	for _, s := range candidates[:n] {
+		keep[s.Span.Id()] = true
+	}
+
+	rv := make([]*FinishedSpan, 0, len(keep))
+	for _, s := range spans {
+		if !keep[s.Span.Id()] {
+			continue
+		}
+		parentId, ok := s.ParentId()
+		if !ok || byId[parentId] == nil || keep[parentId] {
+			rv = append(rv, s)
+			continue
+		}
+		for !keep[parentId] {
+			parentId, _ = byId[parentId].ParentId()
+		}
+		cp := *s
+		cp.parentId, cp.reparented = parentId, true
+		rv = append(rv, &cp)
+	}
+	return rv
+}
+
+func spanDuration(s *FinishedSpan) int64 {
+	return int64(s.Finish.Sub(s.Span.Start()))
+}
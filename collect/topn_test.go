@@ -0,0 +1,82 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package collect
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestTopNSpans(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("topn")
+	task := func(ctx context.Context, name string, children ...func(context.Context)) {
+		defer mon.TaskNamed(name)(&ctx)(nil)
+		for _, child := range children {
+			child(ctx)
+		}
+	}
+	leaf := func(name string) func(context.Context) {
+		return func(ctx context.Context) { task(ctx, name) }
+	}
+
+	// CollectSpans adds a root-TRACED Span: root-TRACED -> a -> (b, c),
+	// root-TRACED -> d
+	ctx := context.Background()
+	finish := mon.TaskNamed("root")(&ctx)
+	spans := CollectSpans(ctx, func(ctx context.Context) {
+		task(ctx, "a", leaf("b"), leaf("c"))
+		task(ctx, "d")
+	})
+	finish(nil)
+	byName := map[string]*FinishedSpan{}
+	for _, s := range spans {
+		byName[s.Span.Func().ShortName()] = s
+	}
+	root := byName["root-TRACED"]
+	if root == nil || len(byName) != 5 {
+		t.Fatalf("unexpected spans %v", byName)
+	}
+	for name, d := range map[string]time.Duration{
+		"root-TRACED": 100 * time.Millisecond,
+		"a":           10 * time.Millisecond,
+		"b":           50 * time.Millisecond,
+		"c":           1 * time.Millisecond,
+		"d":           20 * time.Millisecond,
+	} {
+		byName[name].Finish = byName[name].Span.Start().Add(d)
+	}
+
+	top := TopNSpans(spans, 2)
+	var names []string
+	parents := map[string]int64{}
+	for _, s := range top {
+		name := s.Span.Func().ShortName()
+		names = append(names, name)
+		parents[name], _ = s.ParentId()
+	}
+	sort.Strings(names)
+	if len(names) != 3 || names[0] != "b" || names[1] != "d" || names[2] != "root-TRACED" {
+		t.Fatalf("expected the root, b and d, got %v", names)
+	}
+	if parents["b"] != root.Span.Id() || parents["d"] != root.Span.Id() {
+		t.Fatalf("expected b and d to be linked to the root, got %v", parents)
+	}
+	if parents["root-TRACED"] != monkit.SpanFromCtx(ctx).Id() {
+		t.Fatalf("expected the root to keep its parent, got %v", parents)
+	}
+	if parentId, _ := byName["b"].ParentId(); parentId != byName["a"].Span.Id() {
+		t.Fatal("expected the input spans to be left alone")
+	}
+
+	if top := TopNSpans(spans, 0); len(top) != 1 || top[0] != root {
+		t.Fatalf("expected only the root, got %v", top)
+	}
+	if top := TopNSpans(spans, 10); len(top) != 5 {
+		t.Fatalf("expected all spans, got %v", top)
+	}
+}
@@ -17,6 +17,9 @@ package monkit
 import (
 	"math"
 	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/monotime"
 )
 
 // Counter keeps track of running totals, along with the highest and lowest
@@ -31,10 +34,19 @@ import (
 //	  mon.Counter("beans").Inc(1)
 //	}
 type Counter struct {
+	updated        lastUpdated
 	mtx            sync.Mutex
 	val, low, high int64
 	nonempty       bool
 	key            SeriesKey
+
+	// rate output, see EmitRate
+	rate     bool
+	rateVal  int64
+	rateTime time.Time
+
+	// now is the time source for the rate, replaceable in tests.
+	now func() time.Time
 }
 
 // NewCounter constructs a counter
@@ -51,8 +63,12 @@ func (c *Counter) set(val int64) {
 		c.high = val
 	}
 	c.nonempty = true
+	c.updated.touch()
 }
 
+// LastUpdated implements the LastUpdatedSource interface.
+func (c *Counter) LastUpdated() time.Time { return c.updated.time() }
+
 // Set will immediately change the value of the counter to whatever val is. It
 // will appropriately update the high and low values, and return the former
 // value.
@@ -112,10 +128,44 @@ func (c *Counter) Reset() (val, low, high int64) {
 	return val, low, high
 }
 
+// EmitRate makes Stats additionally report a rate field, the per-second
+// change of the value since the previous Stats call. It is meant for Counters
+// that only ever go up. The first Stats call reports a rate of zero, as does
+// a call after the value went down, such as after a Reset, instead of a
+// negative spike.
+func (c *Counter) EmitRate(enabled bool) {
+	c.mtx.Lock()
+	c.rate = enabled
+	c.rateTime = time.Time{}
+	c.mtx.Unlock()
+}
+
+// nextRate returns the rate since it was last called and must be called with
+// mtx held.
+func (c *Counter) nextRate() (rate float64) {
+	now := c.now
+	if now == nil {
+		now = monotime.Now
+	}
+	t := now()
+	if !c.rateTime.IsZero() && c.val >= c.rateVal {
+		if elapsed := t.Sub(c.rateTime).Seconds(); elapsed > 0 {
+			rate = float64(c.val-c.rateVal) / elapsed
+		}
+	}
+	c.rateVal, c.rateTime = c.val, t
+	return rate
+}
+
 // Stats implements the StatSource interface
 func (c *Counter) Stats(cb func(key SeriesKey, field string, val float64)) {
 	c.mtx.Lock()
 	val, low, high, nonempty := c.val, c.low, c.high, c.nonempty
+	emitRate := c.rate
+	var rate float64
+	if emitRate {
+		rate = c.nextRate()
+	}
 	c.mtx.Unlock()
 	if nonempty {
 		cb(c.key, "high", float64(high))
@@ -124,5 +174,8 @@ func (c *Counter) Stats(cb func(key SeriesKey, field string, val float64)) {
 		cb(c.key, "high", math.NaN())
 		cb(c.key, "low", math.NaN())
 	}
+	if emitRate {
+		cb(c.key, "rate", rate)
+	}
 	cb(c.key, "value", float64(val))
 }
@@ -0,0 +1,51 @@
+package monkit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCounterRate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := NewCounter(NewSeriesKey("requests"))
+	c.now = func() time.Time { return now }
+	c.EmitRate(true)
+
+	scrape := func() float64 {
+		stats := Collect(c)
+		rate, ok := stats["requests rate"]
+		if !ok {
+			t.Fatalf("missing rate in %v", stats)
+		}
+		return rate
+	}
+
+	c.Inc(100)
+	if rate := scrape(); rate != 0 {
+		t.Fatalf("expected zero rate on first scrape, got %v", rate)
+	}
+
+	c.Inc(50)
+	now = now.Add(10 * time.Second)
+	if rate := scrape(); rate != 5 {
+		t.Fatalf("expected rate 5, got %v", rate)
+	}
+
+	c.Reset()
+	c.Inc(10)
+	now = now.Add(10 * time.Second)
+	if rate := scrape(); rate != 0 {
+		t.Fatalf("expected zero rate after reset, got %v", rate)
+	}
+
+	c.Inc(20)
+	now = now.Add(5 * time.Second)
+	if rate := scrape(); rate != 4 {
+		t.Fatalf("expected rate 4, got %v", rate)
+	}
+
+	c.EmitRate(false)
+	if _, ok := Collect(c)["requests rate"]; ok {
+		t.Fatal("unexpected rate after disabling it")
+	}
+}
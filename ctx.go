@@ -16,6 +16,8 @@ package monkit
 
 import (
 	"context"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -38,11 +40,28 @@ type Span struct {
 	args     []interface{}
 	context.Context
 
+	countChildren bool
+	trackAllocs   bool
+	allocStart    uint64
+	budget        time.Duration // the budget the Span is tracked with
+
+	// the context the observers returned for a Span of NewSpanAt
+	backfillCtx context.Context
+
 	// protected by mtx
 	done        bool
 	orphaned    bool
+	overrun     bool
+	kind        SpanKind
 	children    spanBag
 	annotations []Annotation
+	childCount  int
+	truncated   int
+	deadline    time.Time
+	attachments []Attachment
+
+	annotationPolicy    AnnotationPolicy
+	maxAnnotationLength int
 }
 
 // SpanFromCtx loads the current Span from the given context. This assumes
@@ -56,8 +75,29 @@ func SpanFromCtx(ctx context.Context) *Span {
 	return nil
 }
 
+// TraceFromCtx loads the Trace of the current Span from the given context.
+// It returns nil if the context has no Span.
+func TraceFromCtx(ctx context.Context) *Trace {
+	if s := SpanFromCtx(ctx); s != nil {
+		return s.Trace()
+	}
+	return nil
+}
+
 func newSpan(ctx context.Context, f *Func, args []interface{}, trace *Trace,
-	parentId *int64) (sctx context.Context, exit func(*error)) {
+	parentId *int64, annotations []Annotation) (sctx context.Context, exit func(*error)) {
+
+	f.recordCaller()
+
+	if trace == nil && parentId == nil && !f.traced() {
+		return f.untracedTask(ctx)
+	}
+
+	if len(f.annotations) > 0 {
+		// a copy, as contextAnnotations appends to it.
+		annotations = append(append([]Annotation(nil), f.annotations...), annotations...)
+	}
+	annotations = f.scope.r.contextAnnotations(ctx, annotations)
 
 	var s, parent *Span
 	if s, ok := ctx.(*Span); ok && s != nil {
@@ -72,8 +112,11 @@ func newSpan(ctx context.Context, f *Func, args []interface{}, trace *Trace,
 			trace = parent.trace
 		}
 	} else if trace == nil {
-		trace = NewTrace(NewId())
-		f.scope.r.observeTrace(trace)
+		if restored, ok := ctx.Value(restoredKey).(*restoredTrace); ok {
+			trace, parentId = restored.resume(f.scope.r)
+		} else {
+			trace = f.scope.r.newLocalTrace(ctx)
+		}
 	}
 
 	// if we're passed in an explicit parent id, then it's a remote trace,
@@ -93,21 +136,46 @@ func newSpan(ctx context.Context, f *Func, args []interface{}, trace *Trace,
 		parentId: parentId,
 		args:     args,
 		Context:  ctx,
+
+		annotationPolicy:    f.scope.r.AnnotationPolicy(),
+		maxAnnotationLength: f.scope.r.MaxAnnotationLength(),
+		countChildren:       f.scope.r.countChildren(),
 	}
 
+	// the initial annotations are subject to the annotation policy and
+	// length limit like any other. s isn't shared yet, so there is no need
+	// to hold s.mtx.
+	for _, a := range annotations {
+		s.addAnnotation(a.Name, a.Value)
+	}
+	if a, ok := f.scope.r.sampleRateAnnotation(trace); ok {
+		s.addAnnotation(a.Name, a.Value)
+	}
+
+	s.startAllocs()
 	trace.incrementSpans()
 
+	if s.budget = f.Budget(); s.budget > 0 {
+		budgets.add(s, s.budget)
+	}
+
 	if parent != nil {
 		f.start(parent.f)
 		parent.addChild(s)
 	} else {
 		f.start(nil)
 		f.scope.r.rootSpanStart(s)
+		trace.initRootName(f)
 	}
 
 	sctx = s
 	if observer != nil {
 		sctx = observer.Start(sctx, s)
+		trace.observeTransition(s, SpanTransition{Kind: SpanCreated, Time: s.start})
+		if parent != nil {
+			trace.observeTransition(parent, SpanTransition{
+				Kind: SpanChildAdded, Time: s.start, Child: s})
+		}
 	}
 
 	return sctx, func(errptr *error) {
@@ -120,36 +188,25 @@ func newSpan(ctx context.Context, f *Func, args []interface{}, trace *Trace,
 		if errptr != nil {
 			err = *errptr
 		}
-		s.f.end(err, panicked, finish.Sub(s.start))
-
-		var children []*Span
-		s.mtx.Lock()
-		s.done = true
-		orphaned := s.orphaned
-		s.children.Iterate(func(child *Span) {
-			children = append(children, child)
-		})
-		s.mtx.Unlock()
-		for _, child := range children {
-			child.orphan()
+		if !s.claimFinish() {
+			// the Span was already force-finished by the trace TTL sweeper.
+			if panicked {
+				panic(rec)
+			}
+			return
+		}
+		if panicked {
+			s.annotatePanic(rec)
 		}
 
-		if s.parent != nil {
-			s.parent.removeChild(s)
-			if orphaned {
-				s.f.scope.r.orphanEnd(s)
+		if s.budget > 0 {
+			budgets.remove(s)
+			if finish.Sub(s.start) > s.budget {
+				s.markOverrun()
 			}
-		} else {
-			s.f.scope.r.rootSpanEnd(s)
 		}
 
-		trace.decrementSpans()
-
-		// Re-fetch the observer, in case the value has changed since newSpan
-		// was called
-		if observer := trace.getObserver(); observer != nil {
-			observer.Finish(sctx, s, err, panicked, finish)
-		}
+		s.finished(sctx, err, panicked, finish)
 
 		if panicked {
 			panic(rec)
@@ -157,6 +214,64 @@ func newSpan(ctx context.Context, f *Func, args []interface{}, trace *Trace,
 	}
 }
 
+// claimFinish marks the Span as done, returning false if it already was.
+func (s *Span) claimFinish() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.done {
+		return false
+	}
+	s.done = true
+	return true
+}
+
+// finished does the bookkeeping for a Span that was just claimed by
+// claimFinish.
+func (s *Span) finished(ctx context.Context, err error, panicked bool, finish time.Time) {
+	s.annotateAllocs()
+	s.f.end(err, panicked, finish.Sub(s.start))
+
+	var children []*Span
+	s.mtx.Lock()
+	s.annotateChildCount()
+	s.annotateDeadline(finish)
+	s.annotateTruncated()
+	truncated := s.truncated
+	orphaned := s.orphaned
+	s.children.Iterate(func(child *Span) {
+		children = append(children, child)
+	})
+	s.mtx.Unlock()
+	if truncated > 0 {
+		s.f.scope.r.logf("monkit: span %s of trace %d had %d annotation values truncated to %d bytes",
+			s.f.FullName(), s.trace.Id(), truncated, s.maxAnnotationLength)
+	}
+	for _, child := range children {
+		child.orphan()
+	}
+
+	if s.parent != nil {
+		s.parent.removeChild(s)
+		if orphaned {
+			s.f.scope.r.orphanEnd(s)
+		}
+	} else {
+		s.f.scope.r.rootSpanEnd(s)
+	}
+
+	s.trace.decrementSpans()
+
+	// Re-fetch the observer, in case the value has changed since newSpan
+	// was called
+	if observer := s.trace.getObserver(); observer != nil {
+		s.trace.observeTransition(s, SpanTransition{
+			Kind: SpanFinished, Time: finish, Err: err, Panicked: panicked})
+		s.f.scope.r.observeFinish(observer, ctx, s, err, panicked, finish)
+	}
+
+	s.f.scope.r.checkSlowSpan(s, finish.Sub(s.start))
+}
+
 var taskSecret context.Context = &taskSecretT{}
 
 // Tasks are created (sometimes implicitly) from Funcs. A Task should be called
@@ -187,10 +302,11 @@ type Task func(ctx *context.Context, args ...interface{}) func(*error)
 //	  ...
 //	}
 //
-// Task allows you to include SeriesTags. WARNING: Each unique tag key/value
-// combination creates a unique Func and a unique series. SeriesTags should
-// only be used for low-cardinality values that you intentionally wish to
-// result in a unique series. Example:
+// Task allows you to include SeriesTags, which are also added as annotations to
+// every Span the Task starts. WARNING: Each unique tag key/value combination
+// creates a unique Func and a unique series. SeriesTags should only be used
+// for low-cardinality values that you intentionally wish to result in a
+// unique series. Example:
 //
 //	func MyFunc(ctx context.Context, arg1, arg2 string) (err error) {
 //	  defer mon.Task(monkit.NewSeriesTag("key1", "val1"))(&ctx)(&err)
@@ -213,9 +329,10 @@ func (s *Scope) Task(tags ...SeriesTag) Task {
 			return nil
 		}
 		initOnce.Do(func() {
-			f = s.FuncNamed(callerFunc(3), tags...)
+			name, fullName := callerFunc(3)
+			f = s.FuncNamed(name, s.r.funcPackageTags(fullName, tags)...)
 		})
-		s, exit := newSpan(*ctx, f, args, nil, nil)
+		s, exit := newSpan(*ctx, f, args, nil, nil, nil)
 		if ctx != &unparented {
 			*ctx = s
 		}
@@ -241,7 +358,35 @@ func (f *Func) Task(ctx *context.Context, args ...interface{}) func(*error) {
 	if ctx == &taskSecret && taskArgs(f, args) {
 		return nil
 	}
-	s, exit := newSpan(*ctx, f, args, nil, nil)
+	s, exit := newSpan(*ctx, f, args, nil, nil, nil)
+	if ctx != &unparented {
+		*ctx = s
+	}
+	return exit
+}
+
+// TaskEnqueued is like Func.Task, for work that waited in a queue since
+// enqueuedAt before it started, such as a job of a worker pool. The Span
+// records the time between enqueuedAt and its start as the queue.wait_ms
+// annotation, so that the time spent queued can be told apart from the
+// Span's own duration.
+//
+//	func (p *Pool) worker(job *Job) {
+//	  ctx := job.ctx
+//	  defer mon.Func().TaskEnqueued(&ctx, job.enqueuedAt)(&job.err)
+//	  ...
+//	}
+func (f *Func) TaskEnqueued(ctx *context.Context, enqueuedAt time.Time,
+	args ...interface{}) func(*error) {
+	ctx = cleanCtx(ctx)
+	wait := monotime.Now().Sub(enqueuedAt)
+	if wait < 0 {
+		wait = 0
+	}
+	s, exit := newSpan(*ctx, f, args, nil, nil, []Annotation{{
+		Name:  "queue.wait_ms",
+		Value: strconv.FormatFloat(float64(wait)/float64(time.Millisecond), 'f', -1, 64),
+	}})
 	if ctx != &unparented {
 		*ctx = s
 	}
@@ -257,7 +402,7 @@ func (f *Func) RemoteTrace(ctx *context.Context, parentId int64, trace *Trace,
 	if trace != nil {
 		f.scope.r.observeTrace(trace)
 	}
-	s, exit := newSpan(*ctx, f, args, trace, &parentId)
+	s, exit := newSpan(*ctx, f, args, trace, &parentId, nil)
 	if ctx != &unparented {
 		*ctx = s
 	}
@@ -271,15 +416,72 @@ func (f *Func) ResetTrace(ctx *context.Context,
 	if ctx == &taskSecret && taskArgs(f, args) {
 		return nil
 	}
-	trace := NewTrace(NewId())
-	f.scope.r.observeTrace(trace)
-	s, exit := newSpan(*ctx, f, args, trace, nil)
+	trace := f.scope.r.newLocalTrace(*ctx)
+	s, exit := newSpan(*ctx, f, args, trace, nil, nil)
 	if ctx != &unparented {
 		*ctx = s
 	}
 	return exit
 }
 
+// ContinueTrace is like Func.RemoteTrace, but for continuing a trace that was
+// propagated by something other than HTTP headers, such as message queue
+// metadata. It creates a Span for a Func named after the calling function on
+// a Trace with the given traceID, whose parent is the remote span
+// parentSpanID. If sampled is true, the Trace is marked as sampled the same
+// way the http package's TraceHandler does. Any annotations are added to the
+// new Span.
+func (s *Scope) ContinueTrace(ctx *context.Context, traceID, parentSpanID int64,
+	sampled bool, annotations map[string]string) func(*error) {
+	return s.startRemote(ctx, NewTrace(traceID), &parentSpanID, sampled, annotations)
+}
+
+// LinkTrace is like Scope.ContinueTrace, but it starts a new Trace instead of
+// continuing the one with the given traceID, and links the remote span
+// parentSpanID to it with Trace.SetParentTrace. The link is set before the
+// Span starts, so span observers such as exporters already see it.
+func (s *Scope) LinkTrace(ctx *context.Context, traceID, parentSpanID int64,
+	sampled bool, annotations map[string]string) func(*error) {
+	trace := NewTrace(NewId())
+	trace.SetParentTrace(traceID, parentSpanID)
+	return s.startRemote(ctx, trace, nil, sampled, annotations)
+}
+
+// startRemote implements Scope.ContinueTrace and Scope.LinkTrace, which have
+// to call it directly for the Func to be named after their caller.
+func (s *Scope) startRemote(ctx *context.Context, trace *Trace, parentSpanID *int64,
+	sampled bool, annotations map[string]string) func(*error) {
+	ctx = cleanCtx(ctx)
+	name, fullName := callerFunc(1)
+	f := s.FuncNamed(name, s.r.funcPackageTags(fullName, nil)...)
+
+	if sampled {
+		trace.Set(sampledKey, true)
+	}
+	names := make([]string, 0, len(annotations))
+	for name := range annotations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var spanAnnotations []Annotation
+	for _, name := range names {
+		spanAnnotations = append(spanAnnotations,
+			Annotation{Name: name, Value: annotations[name]})
+	}
+
+	s.r.observeTrace(trace)
+	sctx, exit := newSpan(*ctx, f, nil, trace, parentSpanID, spanAnnotations)
+
+	if cb, exists := trace.Get(sampledCBKey).(func(*Trace)); exists {
+		cb(trace)
+	}
+
+	if ctx != &unparented {
+		*ctx = sctx
+	}
+	return exit
+}
+
 var unparented = context.Background()
 
 func cleanCtx(ctx *context.Context) *context.Context {
@@ -304,7 +506,8 @@ func cleanCtx(ctx *context.Context) *context.Context {
 
 // SpanCtxObserver is the interface plugins must implement if they want to observe
 // all spans on a given trace as they happen, or add to contexts as they
-// pass through mon.Task()(&ctx)(&err) calls.
+// pass through mon.Task()(&ctx)(&err) calls. It is called under the same
+// conditions as a SpanObserver, so it may start Spans itself.
 type SpanCtxObserver interface {
 	// Start is called when a Span starts. Start should return the context
 	// this span should use going forward. ctx is the context it is currently
@@ -359,7 +562,7 @@ type resetContext struct {
 }
 
 func (r resetContext) Value(key interface{}) interface{} {
-	if key == spanKey {
+	if key == spanKey || key == restoredKey {
 		return nil
 	}
 	return r.Context.Value(key)
@@ -2,6 +2,7 @@ package monkit
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -84,3 +85,125 @@ func BenchmarkTaskNested(b *testing.B) {
 		}()
 	}
 }
+
+func TestContinueTrace(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("test")
+	ctx := context.Background()
+
+	func() {
+		defer mon.ContinueTrace(&ctx, 1234, 5678, true, map[string]string{"k": "v"})(nil)
+
+		s := SpanFromCtx(ctx)
+		if s == nil {
+			t.Fatal("no span in context")
+		}
+		if s.Trace().Id() != 1234 {
+			t.Fatalf("expected trace id 1234, got %d", s.Trace().Id())
+		}
+		if parent, ok := s.ParentId(); !ok || parent != 5678 {
+			t.Fatalf("expected parent id 5678, got %d %v", parent, ok)
+		}
+		if sampled, _ := s.Trace().Get(sampledKey).(bool); !sampled {
+			t.Fatal("expected trace to be sampled")
+		}
+		if s.Func().ShortName() != "TestContinueTrace.func1" {
+			t.Fatalf("unexpected func name %q", s.Func().ShortName())
+		}
+		ann := s.Annotations()
+		if len(ann) != 1 || ann[0] != (Annotation{Name: "k", Value: "v"}) {
+			t.Fatalf("unexpected annotations %v", ann)
+		}
+	}()
+}
+
+func TestLinkTrace(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+
+	obs := &linkObserver{}
+	defer r.ObserveTraces(func(t *Trace) { t.ObserveSpans(obs) })()
+
+	ctx := context.Background()
+	func() {
+		defer mon.LinkTrace(&ctx, 1234, 5678, true, nil)(nil)
+
+		s := SpanFromCtx(ctx)
+		if s.Trace().Id() == 1234 {
+			t.Fatal("expected a new trace")
+		}
+		if _, ok := s.ParentId(); ok {
+			t.Fatal("expected a root span")
+		}
+		if sampled, _ := s.Trace().Get(sampledKey).(bool); !sampled {
+			t.Fatal("expected trace to be sampled")
+		}
+		if s.Func().ShortName() != "TestLinkTrace.func2" {
+			t.Fatalf("unexpected func name %q", s.Func().ShortName())
+		}
+	}()
+	if len(obs.links) != 1 || obs.links[0] != (TraceLink{TraceId: 1234, SpanId: 5678}) {
+		t.Fatalf("expected the link when the span started, got %v", obs.links)
+	}
+}
+
+type linkObserver struct{ links []TraceLink }
+
+func (o *linkObserver) Start(s *Span) {
+	if link, ok := s.Trace().ParentTrace(); ok {
+		o.links = append(o.links, link)
+	}
+}
+func (o *linkObserver) Finish(s *Span, err error, panicked bool, finish time.Time) {}
+
+func TestTraceFromCtx(t *testing.T) {
+	if tr := TraceFromCtx(context.Background()); tr != nil {
+		t.Fatalf("expected nil trace, got %v", tr)
+	}
+
+	mon := Package()
+	ctx := context.Background()
+	trace := NewTrace(NewId())
+	defer mon.Func().RemoteTrace(&ctx, 0, trace)(nil)
+
+	if tr := TraceFromCtx(ctx); tr != trace {
+		t.Fatalf("expected trace %v, got %v", trace, tr)
+	}
+
+	func() {
+		defer mon.Task()(&ctx)(nil)
+		if tr := TraceFromCtx(ctx); tr != trace {
+			t.Fatalf("child span: expected trace %v, got %v", trace, tr)
+		}
+	}()
+}
+
+func TestTaskEnqueued(t *testing.T) {
+	f := NewRegistry().ScopeNamed("queue").FuncNamed("job")
+
+	wait := func(enqueuedAt time.Time) float64 {
+		ctx := context.Background()
+		defer f.TaskEnqueued(&ctx, enqueuedAt)(nil)
+		for _, a := range SpanFromCtx(ctx).Annotations() {
+			if a.Name == "queue.wait_ms" {
+				ms, err := strconv.ParseFloat(a.Value, 64)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return ms
+			}
+		}
+		t.Fatal("missing queue.wait_ms annotation")
+		return 0
+	}
+
+	if ms := wait(time.Now().Add(-50 * time.Millisecond)); ms < 50 || ms > 5000 {
+		t.Fatalf("unexpected wait %vms", ms)
+	}
+	// clocks of other machines may run ahead.
+	if ms := wait(time.Now().Add(time.Hour)); ms != 0 {
+		t.Fatalf("expected no wait for an enqueue time in the future, got %vms", ms)
+	}
+	if f.Success() != 2 {
+		t.Fatalf("expected the spans to count as calls, got %d", f.Success())
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"sort"
+)
+
+// ContextAnnotator returns annotations to add to a new Span, derived from the
+// context the Span is started with, such as a request id stored in it by some
+// middleware. It is called for every traced Span, so it should be cheap, and
+// it may return nil if it has nothing to add.
+type ContextAnnotator func(ctx context.Context) map[string]string
+
+type contextAnnotatorRef struct {
+	annotators []ContextAnnotator
+}
+
+// AddContextAnnotator registers a ContextAnnotator which is called whenever a
+// Span is started on this Registry. Annotators run in the order they were
+// added, and annotations of the same Span are added in name order. The
+// returned cancel method removes the annotator.
+func (r *Registry) AddContextAnnotator(annotator ContextAnnotator) (cancel func()) {
+	r.watcherMtx.Lock()
+	defer r.watcherMtx.Unlock()
+
+	cbId := r.watcherCounter
+	r.watcherCounter += 1
+	r.annotators[cbId] = annotator
+	r.updateContextAnnotators()
+
+	return func() {
+		r.watcherMtx.Lock()
+		defer r.watcherMtx.Unlock()
+		delete(r.annotators, cbId)
+		r.updateContextAnnotators()
+	}
+}
+
+// FlagAnnotationPrefix is the prefix AddFlagAnnotator names the annotations
+// of feature flags with.
+const FlagAnnotationPrefix = "flag."
+
+// AddFlagAnnotator registers flags like a ContextAnnotator, for the feature
+// flags active for ctx, such as the variants of an A/B test. Each flag is
+// added to new Spans as a flag.<name> annotation with the variant as its
+// value, so that traces can be grouped by variant. The returned cancel method
+// removes the annotator.
+func (r *Registry) AddFlagAnnotator(flags func(ctx context.Context) map[string]string) (cancel func()) {
+	return r.AddContextAnnotator(func(ctx context.Context) map[string]string {
+		active := flags(ctx)
+		if len(active) == 0 {
+			return nil
+		}
+		vals := make(map[string]string, len(active))
+		for name, variant := range active {
+			vals[FlagAnnotationPrefix+name] = variant
+		}
+		return vals
+	})
+}
+
+func (r *Registry) updateContextAnnotators() {
+	if len(r.annotators) == 0 {
+		storeContextAnnotatorRef(&r.contextAnnotators, nil)
+		return
+	}
+	ids := make([]int64, 0, len(r.annotators))
+	for id := range r.annotators {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	annotators := make([]ContextAnnotator, 0, len(ids))
+	for _, id := range ids {
+		annotators = append(annotators, r.annotators[id])
+	}
+	storeContextAnnotatorRef(&r.contextAnnotators, &contextAnnotatorRef{annotators: annotators})
+}
+
+// contextAnnotations appends the annotations of all registered
+// ContextAnnotators for ctx to annotations.
+func (r *Registry) contextAnnotations(ctx context.Context, annotations []Annotation) []Annotation {
+	ref := loadContextAnnotatorRef(&r.contextAnnotators)
+	if ref == nil {
+		return annotations
+	}
+	for _, annotator := range ref.annotators {
+		vals := annotator(ctx)
+		if len(vals) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(vals))
+		for name := range vals {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			annotations = append(annotations, Annotation{Name: name, Value: vals[name]})
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,88 @@
+package monkit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type requestIDKey struct{}
+
+func TestContextAnnotator(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("annotators")
+
+	cancel := r.AddContextAnnotator(func(ctx context.Context) map[string]string {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return map[string]string{"request.id": id}
+		}
+		return nil
+	})
+	r.AddContextAnnotator(func(ctx context.Context) map[string]string {
+		return map[string]string{"zone": "b", "region": "a"}
+	})
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	finish := mon.FuncNamed("handler").Task(&ctx)
+	expected := []Annotation{
+		{Name: "request.id", Value: "req-1"},
+		{Name: "region", Value: "a"},
+		{Name: "zone", Value: "b"},
+	}
+	if got := SpanFromCtx(ctx).Annotations(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	// child spans see the same context values.
+	child := ctx
+	mon.FuncNamed("child").Task(&child)(nil)
+	if got := SpanFromCtx(child).Annotations(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	finish(nil)
+
+	cancel()
+	ctx = context.WithValue(context.Background(), requestIDKey{}, "req-2")
+	mon.FuncNamed("handler").Task(&ctx)(nil)
+	expected = expected[1:]
+	if got := SpanFromCtx(ctx).Annotations(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+type flagsKey struct{}
+
+func TestFlagAnnotator(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("flags")
+
+	cancel := r.AddFlagAnnotator(func(ctx context.Context) map[string]string {
+		flags, _ := ctx.Value(flagsKey{}).(map[string]string)
+		return flags
+	})
+
+	ctx := context.WithValue(context.Background(), flagsKey{},
+		map[string]string{"new-checkout": "variant-b", "dark-mode": "on"})
+	mon.FuncNamed("handler").Task(&ctx)(nil)
+	expected := []Annotation{
+		{Name: "flag.dark-mode", Value: "on"},
+		{Name: "flag.new-checkout", Value: "variant-b"},
+	}
+	if got := SpanFromCtx(ctx).Annotations(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	// no active flags, no annotations.
+	ctx = context.Background()
+	mon.FuncNamed("handler").Task(&ctx)(nil)
+	if got := SpanFromCtx(ctx).Annotations(); len(got) != 0 {
+		t.Fatalf("expected no annotations, got %v", got)
+	}
+
+	cancel()
+	ctx = context.WithValue(context.Background(), flagsKey{}, map[string]string{"dark-mode": "on"})
+	mon.FuncNamed("handler").Task(&ctx)(nil)
+	if got := SpanFromCtx(ctx).Annotations(); len(got) != 0 {
+		t.Fatalf("expected no annotations after cancel, got %v", got)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"time"
+)
+
+// SetDeadline sets a deadline for the Span, so that it gets a
+// deadline.exceeded=true annotation if it finishes after t. It works like a
+// Func budget (see Func.SetBudget) for a single Span, such as one of a Func
+// used for many kinds of work, but is only checked once the Span finishes. A
+// zero t removes the deadline. The deadline is unrelated to the one of the
+// Span's context, as returned by its Deadline method.
+func (s *Span) SetDeadline(t time.Time) {
+	s.mtx.Lock()
+	s.deadline = t
+	s.mtx.Unlock()
+}
+
+// annotateDeadline should be called with s.mtx held, when the Span finishes.
+func (s *Span) annotateDeadline(finish time.Time) {
+	if !s.deadline.IsZero() && finish.After(s.deadline) {
+		s.addAnnotation("deadline.exceeded", "true")
+	}
+}
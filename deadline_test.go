@@ -0,0 +1,46 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSpanDeadline(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("deadline")
+
+	run := func(deadline time.Time, work time.Duration) *Span {
+		ctx := context.Background()
+		defer mon.TaskNamed("work")(&ctx)(nil)
+		s := SpanFromCtx(ctx)
+		s.SetDeadline(deadline)
+		time.Sleep(work)
+		return s
+	}
+
+	onTime := run(time.Now().Add(time.Hour), 0)
+	if hasAnnotation(onTime, "deadline.exceeded", "true") {
+		t.Fatal("unexpected deadline.exceeded for a span finishing in time")
+	}
+
+	overrun := run(time.Now().Add(time.Millisecond), 10*time.Millisecond)
+	if !hasAnnotation(overrun, "deadline.exceeded", "true") {
+		t.Fatalf("expected deadline.exceeded, got %v", overrun.Annotations())
+	}
+
+	// a zero deadline removes it.
+	removed := func() *Span {
+		ctx := context.Background()
+		defer mon.TaskNamed("work")(&ctx)(nil)
+		s := SpanFromCtx(ctx)
+		s.SetDeadline(time.Now().Add(-time.Hour))
+		s.SetDeadline(time.Time{})
+		return s
+	}()
+	if hasAnnotation(removed, "deadline.exceeded", "true") {
+		t.Fatal("unexpected deadline.exceeded after removing the deadline")
+	}
+	if _, ok := removed.Deadline(); ok {
+		t.Fatal("expected the context deadline to be unaffected")
+	}
+}
@@ -59,3 +59,31 @@ func (d *IntDist) toFloat64(v int64) float64 {
 func (d *FloatDist) toFloat64(v float64) float64 {
 	return v
 }
+
+// ObserveNanos is like Insert, but takes a duration as an integer number of
+// nanoseconds.
+func (d *DurationDist) ObserveNanos(nanos int64) {
+	d.Insert(time.Duration(nanos))
+}
+
+// DistSnapshot is a summary of a distribution, see IntDist.Snapshot. Values
+// are in the same units Stats reports them in, so durations are in seconds.
+type DistSnapshot struct {
+	// Count is the number of observed values, all other fields are zero if
+	// it is.
+	Count int64
+
+	Sum, Min, Max, Recent float64
+
+	// Average is the average of all observed values, ReservoirAverage the
+	// one of the values in Reservoir.
+	Average, ReservoirAverage float64
+
+	// RMin to RMax are quantiles estimated from the reservoir, they are
+	// reported by Stats as rmin, r10, r50 and so on.
+	RMin, R10, R50, R90, R99, RMax float64
+
+	// Reservoir is the sample of observed values quantiles are estimated
+	// from, in ascending order.
+	Reservoir []float64
+}
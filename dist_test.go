@@ -0,0 +1,193 @@
+package monkit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDistSeed(t *testing.T) {
+	a := NewDurationDist(NewSeriesKey("a"))
+	b := NewDurationDist(NewSeriesKey("b"))
+	a.Seed(42)
+	b.Seed(42)
+	for i := 0; i < 10*ReservoirSize; i++ {
+		val := time.Duration(i*7919%1000) * time.Millisecond
+		a.Insert(val)
+		b.Insert(val)
+	}
+	if a.reservoir != b.reservoir {
+		t.Fatal("identically seeded dists have different reservoirs")
+	}
+	for _, q := range []float64{0, .1, .5, .9, .99, 1} {
+		if a.Query(q) != b.Query(q) {
+			t.Fatalf("quantile %v differs: %v != %v", q, a.Query(q), b.Query(q))
+		}
+	}
+
+	c := NewDurationDist(NewSeriesKey("c"))
+	c.Seed(43)
+	for i := 0; i < 10*ReservoirSize; i++ {
+		c.Insert(time.Duration(i*7919%1000) * time.Millisecond)
+	}
+	if a.reservoir == c.reservoir {
+		t.Fatal("differently seeded dists have identical reservoirs")
+	}
+}
+
+func TestDurationDistObserveNanos(t *testing.T) {
+	a := NewDurationDist(NewSeriesKey("d"))
+	b := NewDurationDist(NewSeriesKey("d"))
+	a.Seed(1)
+	b.Seed(1)
+	for i := 0; i < 10*ReservoirSize; i++ {
+		val := time.Duration(i*7919%1000) * time.Microsecond
+		a.Insert(val)
+		b.ObserveNanos(val.Nanoseconds())
+	}
+	as, bs := Collect(a), Collect(b)
+	if len(as) == 0 || len(as) != len(bs) {
+		t.Fatalf("stats differ: %v != %v", as, bs)
+	}
+	for k, v := range as {
+		if bs[k] != v {
+			t.Fatalf("%s differs: %v != %v", k, v, bs[k])
+		}
+	}
+
+	v := NewDurationVal(NewSeriesKey("v"))
+	v.ObserveNanos(1500)
+	if got := v.Quantile(1); got != 1500*time.Nanosecond {
+		t.Fatalf("expected 1.5µs, got %v", got)
+	}
+}
+
+func TestDistSnapshot(t *testing.T) {
+	check := func(t *testing.T, name string, snapshot DistSnapshot, stats map[string]float64) {
+		t.Helper()
+		for field, val := range map[string]float64{
+			"count":  float64(snapshot.Count),
+			"sum":    snapshot.Sum,
+			"min":    snapshot.Min,
+			"max":    snapshot.Max,
+			"recent": snapshot.Recent,
+			"ravg":   snapshot.ReservoirAverage,
+			"rmin":   snapshot.RMin,
+			"r10":    snapshot.R10,
+			"r50":    snapshot.R50,
+			"r90":    snapshot.R90,
+			"r99":    snapshot.R99,
+			"rmax":   snapshot.RMax,
+		} {
+			if stats[name+" "+field] != val {
+				t.Fatalf("%s: snapshot has %v, stats %v", field, val, stats[name+" "+field])
+			}
+		}
+		if len(snapshot.Reservoir) != ReservoirSize {
+			t.Fatalf("expected a full reservoir, got %d values", len(snapshot.Reservoir))
+		}
+		for i := 1; i < len(snapshot.Reservoir); i++ {
+			if snapshot.Reservoir[i-1] > snapshot.Reservoir[i] {
+				t.Fatalf("reservoir is not sorted: %v", snapshot.Reservoir)
+			}
+		}
+	}
+
+	iv := NewIntVal(NewSeriesKey("i"))
+	fv := NewFloatVal(NewSeriesKey("f"))
+	dv := NewDurationVal(NewSeriesKey("d"))
+	for i := 0; i < 10*ReservoirSize; i++ {
+		v := int64(i * 7919 % 1000)
+		iv.Observe(v)
+		fv.Observe(float64(v) / 10)
+		dv.Observe(time.Duration(v) * time.Millisecond)
+	}
+	check(t, "i", iv.Snapshot(), Collect(iv))
+	check(t, "f", fv.Snapshot(), Collect(fv))
+	check(t, "d", dv.Snapshot(), Collect(dv))
+
+	if s := iv.Snapshot(); s.Average != s.Sum/float64(s.Count) {
+		t.Fatalf("unexpected average %v", s.Average)
+	}
+	if s := NewIntDist(NewSeriesKey("empty")).Snapshot(); s.Count != 0 || s.Reservoir != nil {
+		t.Fatalf("unexpected empty snapshot %+v", s)
+	}
+}
+
+func TestDistOnNewMax(t *testing.T) {
+	d := NewDurationDist(NewSeriesKey("latency"))
+	var maxima []time.Duration
+	d.OnNewMax(func(v time.Duration) {
+		if d.High != v {
+			t.Fatalf("callback before the value was recorded")
+		}
+		maxima = append(maxima, v)
+	})
+	for _, v := range []time.Duration{5, 3, 5, 8, 1, 9, 9, 2} {
+		d.Insert(v)
+	}
+	if fmt.Sprint(maxima) != "[5ns 8ns 9ns]" {
+		t.Fatalf("unexpected maxima %v", maxima)
+	}
+
+	// copies don't call the callbacks.
+	d.Copy().Insert(10)
+	if len(maxima) != 3 {
+		t.Fatalf("unexpected maxima %v", maxima)
+	}
+
+	d.Reset()
+	d.Insert(1)
+	if maxima[len(maxima)-1] != 1 {
+		t.Fatalf("expected the first value after a reset to be a new max, got %v", maxima)
+	}
+}
+
+func TestDistOnThreshold(t *testing.T) {
+	d := NewIntDist(NewSeriesKey("queue"))
+	var crossed []int64
+	d.OnThreshold(10, func(v int64) { crossed = append(crossed, v) })
+	for _, v := range []int64{12, 15, 3, 10, 11, 20, 4, 30} {
+		d.Insert(v)
+	}
+	if fmt.Sprint(crossed) != "[12 11 30]" {
+		t.Fatalf("unexpected crossings %v", crossed)
+	}
+}
+
+func TestValWatchers(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("watch")
+
+	var maxima []int64
+	size := mon.IntVal("size")
+	size.OnNewMax(func(v int64) { maxima = append(maxima, v) })
+	var crossed []float64
+	ratio := mon.FloatVal("ratio")
+	ratio.OnThreshold(0.5, func(v float64) { crossed = append(crossed, v) })
+	var slow []time.Duration
+	took := mon.DurationVal("took")
+	took.OnThreshold(time.Second, func(v time.Duration) { slow = append(slow, v) })
+	var timed int
+	mon.Timer("timer").OnNewMax(func(time.Duration) { timed++ })
+
+	for _, v := range []int64{3, 1, 7} {
+		size.Observe(v)
+	}
+	for _, v := range []float64{0.9, 0.1, 0.6} {
+		ratio.Observe(v)
+	}
+	took.Observe(2 * time.Second)
+	took.Observe(time.Millisecond)
+	mon.Timer("timer").Start().Stop()
+
+	if fmt.Sprint(maxima) != "[3 7]" || fmt.Sprint(crossed) != "[0.9 0.6]" ||
+		fmt.Sprint(slow) != "[2s]" || timed != 1 {
+		t.Fatalf("unexpected callbacks %v %v %v %d", maxima, crossed, slow, timed)
+	}
+
+	// reading the stats doesn't call them.
+	Collect(mon)
+	if len(maxima) != 2 {
+		t.Fatalf("unexpected maxima %v", maxima)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+// distSource is implemented by the StatSources that keep a reservoir
+// distribution.
+type distSource interface {
+	distKey() SeriesKey
+	Snapshot() DistSnapshot
+}
+
+func (v *IntVal) distKey() SeriesKey         { return v.dist.key }
+func (v *FloatVal) distKey() SeriesKey       { return v.dist.key }
+func (v *DurationVal) distKey() SeriesKey    { return v.dist.key }
+func (v *BufferedIntVal) distKey() SeriesKey { return v.val.dist.key }
+func (t *Timer) distKey() SeriesKey          { return t.times.key }
+
+// Snapshot returns a summary of the timed durations, in seconds, see
+// DistSnapshot.
+func (t *Timer) Snapshot() DistSnapshot {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.times.Snapshot()
+}
+
+// Distributions calls cb with a snapshot of every reservoir distribution of
+// the Scope, that is of its IntVals, FloatVals, DurationVals, BufferedIntVals
+// and Timers, along with the key Stats reports it under. It is meant for
+// exporters that need more than the fields of Stats, such as to compute
+// histogram buckets from the reservoir. Chained StatSources are not
+// included.
+func (s *Scope) Distributions(cb func(key SeriesKey, snap DistSnapshot)) {
+	for _, namedSource := range s.allNamedSources() {
+		if dist, ok := namedSource.source.(distSource); ok {
+			cb(dist.distKey().WithTag("scope", s.name), dist.Snapshot())
+		}
+	}
+}
+
+// Distributions calls cb with a snapshot of every reservoir distribution of
+// every Scope. See Scope.Distributions. Like with Stats, the distributions of
+// imported Registries are included, and the keys get the global tags and
+// import prefixes. The transformers see every distribution as its count
+// field, so a distribution is skipped if they drop that field.
+func (r *Registry) Distributions(cb func(key SeriesKey, snap DistSnapshot)) {
+	var snap DistSnapshot
+	r.walk(func(key SeriesKey, field string, val float64) {
+		if field == "count" {
+			cb(key, snap)
+		}
+	}, nil, func(s *Scope, cb func(key SeriesKey, field string, val float64)) {
+		s.Distributions(func(key SeriesKey, dist DistSnapshot) {
+			snap = dist
+			cb(key, "count", float64(dist.Count))
+		})
+	})
+}
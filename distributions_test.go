@@ -0,0 +1,32 @@
+package monkit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDistributions(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("dists")
+	mon.IntVal("size", NewSeriesTag("kind", "a")).Observe(3)
+	mon.FloatVal("ratio").Observe(.5)
+	mon.DurationVal("wait").Observe(2 * time.Second)
+	mon.Counter("requests").Inc(1)
+
+	got := map[string]DistSnapshot{}
+	r.Distributions(func(key SeriesKey, snap DistSnapshot) {
+		got[key.String()] = snap
+	})
+	if len(got) != 3 {
+		t.Fatalf("expected the three distributions, got %v", got)
+	}
+	if s := got["size,kind=a,scope=dists"]; s.Count != 1 || s.Sum != 3 || len(s.Reservoir) != 1 {
+		t.Fatalf("unexpected size snapshot %+v", s)
+	}
+	if s := got["wait,scope=dists"]; s.Sum != 2 {
+		t.Fatalf("expected the duration in seconds, got %+v", s)
+	}
+	if _, ok := got["ratio,scope=dists"]; !ok {
+		t.Fatalf("missing ratio in %v", got)
+	}
+}
@@ -23,7 +23,8 @@ var (
 )
 
 // Register attaches all of this package's environment data to the given
-// registry. It will be attached to a top-level scope called 'env'.
+// registry, along with the age of the registry's oldest open span. It will be
+// attached to a top-level scope called 'env'.
 func Register(registry *monkit.Registry) {
 	if registry == nil {
 		registry = monkit.Default
@@ -32,4 +33,5 @@ func Register(registry *monkit.Registry) {
 	for _, source := range registrations {
 		pkg.Chain(source)
 	}
+	pkg.Chain(registry.OpenSpanStats())
 }
@@ -84,6 +84,8 @@ func getErrorName(err error) string {
 		return "Canceled"
 	case context.DeadlineExceeded:
 		return "Timeout"
+	case ErrSpanExpired:
+		return "Span Expired"
 	}
 	if isErrnoError(err) {
 		return "Errno"
@@ -0,0 +1,332 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"math"
+	"sync"
+)
+
+const (
+	// ExpHistogramMaxScale is the highest scale of an ExpHistogram, the one
+	// with the finest buckets.
+	ExpHistogramMaxScale = 20
+	// ExpHistogramMinScale is the lowest scale of an ExpHistogram.
+	ExpHistogramMinScale = -10
+
+	// expHistogramMaxBuckets is how many buckets each side of an ExpHistogram
+	// may use before it lowers its scale. It matches the OpenTelemetry SDK
+	// default.
+	expHistogramMaxBuckets = 160
+)
+
+// ExpHistogram is a histogram with exponentially growing buckets, compatible
+// with the OpenTelemetry exponential histogram. At scale s, bucket i holds
+// values in (base^i, base^(i+1)], where base = 2^(2^-s), so every bucket is
+// wider than the previous one by the same ratio and the relative error is
+// the same across the whole range, including the tail.
+//
+// Whenever a side of the histogram would need more than 160 buckets, the
+// scale is lowered, merging neighboring buckets pairwise, so memory use stays
+// bounded. ExpHistogram implements StatSource and is safe for concurrent use.
+// Construct it with NewExpHistogram or the Scope.ExpHistogram accessor.
+type ExpHistogram struct {
+	mtx sync.Mutex
+
+	key       SeriesKey
+	scale     int32
+	count     uint64
+	sum       float64
+	min, max  float64
+	zeroCount uint64
+	positive  expBuckets
+	negative  expBuckets
+}
+
+// expBuckets holds the counts of consecutive buckets starting at offset.
+type expBuckets struct {
+	offset int32
+	counts []uint64
+}
+
+// NewExpHistogram creates an ExpHistogram starting out at the given scale,
+// which is clamped to [ExpHistogramMinScale, ExpHistogramMaxScale]. The
+// scale may be lowered automatically as values come in.
+func NewExpHistogram(key SeriesKey, scale int32) *ExpHistogram {
+	if scale > ExpHistogramMaxScale {
+		scale = ExpHistogramMaxScale
+	}
+	if scale < ExpHistogramMinScale {
+		scale = ExpHistogramMinScale
+	}
+	return &ExpHistogram{key: key, scale: scale}
+}
+
+// expBucketIndex returns the index of the bucket holding v > 0 at the given
+// scale, following the OpenTelemetry mapping functions.
+func expBucketIndex(v float64, scale int32) int32 {
+	frac, exp := math.Frexp(v)
+	if scale <= 0 {
+		// v = frac * 2^exp with frac in [0.5, 1), so exact powers of two,
+		// which are the inclusive upper bounds of their bucket, have a frac
+		// of 0.5 and belong to the bucket below.
+		index := exp - 1
+		if frac == 0.5 {
+			index--
+		}
+		return int32(index >> uint(-scale))
+	}
+	if frac == 0.5 {
+		return int32((exp-1)<<uint(scale)) - 1
+	}
+	return int32(math.Ceil(math.Log2(v)*math.Ldexp(1, int(scale)))) - 1
+}
+
+// expBucketLowerBound returns the exclusive lower bound of a bucket.
+func expBucketLowerBound(index, scale int32) float64 {
+	return math.Exp2(math.Ldexp(float64(index), -int(scale)))
+}
+
+// Observe adds a value to the histogram.
+func (h *ExpHistogram) Observe(v float64) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if h.count == 0 || v < h.min {
+		h.min = v
+	}
+	if h.count == 0 || v > h.max {
+		h.max = v
+	}
+	h.count++
+	h.sum += v
+
+	switch {
+	case v == 0:
+		h.zeroCount++
+	case v > 0:
+		h.insert(&h.positive, v, 1)
+	default:
+		h.insert(&h.negative, -v, 1)
+	}
+}
+
+func (h *ExpHistogram) insert(b *expBuckets, v float64, n uint64) {
+	index := expBucketIndex(v, h.scale)
+	for !b.fits(index) {
+		h.downscale(1)
+		index = expBucketIndex(v, h.scale)
+	}
+	b.add(index, n)
+}
+
+func (b *expBuckets) fits(index int32) bool {
+	if len(b.counts) == 0 {
+		return true
+	}
+	low, high := b.offset, b.offset+int32(len(b.counts))-1
+	if index < low {
+		low = index
+	}
+	if index > high {
+		high = index
+	}
+	return high-low+1 <= expHistogramMaxBuckets
+}
+
+func (b *expBuckets) add(index int32, n uint64) {
+	if len(b.counts) == 0 {
+		b.offset = index
+		b.counts = []uint64{n}
+		return
+	}
+	if index < b.offset {
+		counts := make([]uint64, int(b.offset-index)+len(b.counts))
+		copy(counts[b.offset-index:], b.counts)
+		b.counts, b.offset = counts, index
+	}
+	if last := b.offset + int32(len(b.counts)) - 1; index > last {
+		b.counts = append(b.counts, make([]uint64, index-last)...)
+	}
+	b.counts[index-b.offset] += n
+}
+
+// downscale lowers the scale by the given amount, merging 2^by neighboring
+// buckets into one.
+func (h *ExpHistogram) downscale(by int32) {
+	if by <= 0 {
+		return
+	}
+	h.scale -= by
+	h.positive.downscale(by)
+	h.negative.downscale(by)
+}
+
+func (b *expBuckets) downscale(by int32) {
+	if len(b.counts) == 0 {
+		return
+	}
+	old := *b
+	*b = expBuckets{}
+	for i, count := range old.counts {
+		if count > 0 {
+			b.add((old.offset+int32(i))>>uint(by), count)
+		}
+	}
+}
+
+// Merge adds all values observed by other to h. If the histograms have
+// different scales, the result uses the lower one.
+func (h *ExpHistogram) Merge(other *ExpHistogram) {
+	o := other.Snapshot()
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if o.Count == 0 {
+		return
+	}
+	if h.count == 0 || o.Min < h.min {
+		h.min = o.Min
+	}
+	if h.count == 0 || o.Max > h.max {
+		h.max = o.Max
+	}
+	h.count += o.Count
+	h.sum += o.Sum
+	h.zeroCount += o.ZeroCount
+
+	if o.Scale < h.scale {
+		h.downscale(h.scale - o.Scale)
+	}
+	for _, side := range []struct {
+		into *expBuckets
+		from ExpHistogramBuckets
+	}{{&h.positive, o.Positive}, {&h.negative, o.Negative}} {
+		shift := o.Scale - h.scale
+		for i, count := range side.from.BucketCounts {
+			if count == 0 {
+				continue
+			}
+			index := (side.from.Offset + int32(i)) >> uint(shift)
+			for !side.into.fits(index) {
+				h.downscale(1)
+				shift++
+				index = (side.from.Offset + int32(i)) >> uint(shift)
+			}
+			side.into.add(index, count)
+		}
+	}
+}
+
+// ExpHistogramBuckets is one side of an ExpHistogramSnapshot.
+type ExpHistogramBuckets struct {
+	Offset       int32    `json:"offset"`
+	BucketCounts []uint64 `json:"bucketCounts"`
+}
+
+// ExpHistogramSnapshot is a copy of the state of an ExpHistogram. Its fields,
+// and their JSON encoding, are those of an OTLP ExponentialHistogramDataPoint,
+// so exporters can map it directly.
+type ExpHistogramSnapshot struct {
+	Scale     int32               `json:"scale"`
+	Count     uint64              `json:"count"`
+	Sum       float64             `json:"sum"`
+	Min       float64             `json:"min"`
+	Max       float64             `json:"max"`
+	ZeroCount uint64              `json:"zeroCount"`
+	Positive  ExpHistogramBuckets `json:"positive"`
+	Negative  ExpHistogramBuckets `json:"negative"`
+}
+
+// Snapshot returns a copy of the current state of the histogram.
+func (h *ExpHistogram) Snapshot() ExpHistogramSnapshot {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return ExpHistogramSnapshot{
+		Scale:     h.scale,
+		Count:     h.count,
+		Sum:       h.sum,
+		Min:       h.min,
+		Max:       h.max,
+		ZeroCount: h.zeroCount,
+		Positive: ExpHistogramBuckets{
+			Offset:       h.positive.offset,
+			BucketCounts: append([]uint64(nil), h.positive.counts...),
+		},
+		Negative: ExpHistogramBuckets{
+			Offset:       h.negative.offset,
+			BucketCounts: append([]uint64(nil), h.negative.counts...),
+		},
+	}
+}
+
+// Quantile returns an estimate of the value at the given quantile, where
+// 0 <= quantile <= 1, using the geometric midpoint of the bucket it falls in.
+func (s ExpHistogramSnapshot) Quantile(quantile float64) float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	if quantile <= 0 {
+		return s.Min
+	}
+	if quantile >= 1 {
+		return s.Max
+	}
+	rank := uint64(quantile * float64(s.Count-1))
+	var seen uint64
+	for i := len(s.Negative.BucketCounts) - 1; i >= 0; i-- {
+		seen += s.Negative.BucketCounts[i]
+		if seen > rank {
+			return -s.midpoint(s.Negative.Offset + int32(i))
+		}
+	}
+	seen += s.ZeroCount
+	if seen > rank {
+		return 0
+	}
+	for i, count := range s.Positive.BucketCounts {
+		seen += count
+		if seen > rank {
+			return s.midpoint(s.Positive.Offset + int32(i))
+		}
+	}
+	return s.Max
+}
+
+func (s ExpHistogramSnapshot) midpoint(index int32) float64 {
+	low := expBucketLowerBound(index, s.Scale)
+	high := expBucketLowerBound(index+1, s.Scale)
+	mid := math.Sqrt(low * high)
+	return math.Min(math.Max(mid, s.Min), s.Max)
+}
+
+// Stats implements the StatSource interface.
+func (h *ExpHistogram) Stats(cb func(key SeriesKey, field string, val float64)) {
+	s := h.Snapshot()
+	cb(h.key, "count", float64(s.Count))
+	if s.Count == 0 {
+		return
+	}
+	cb(h.key, "sum", s.Sum)
+	cb(h.key, "min", s.Min)
+	cb(h.key, "max", s.Max)
+	cb(h.key, "scale", float64(s.Scale))
+	cb(h.key, "p50", s.Quantile(.5))
+	cb(h.key, "p90", s.Quantile(.9))
+	cb(h.key, "p99", s.Quantile(.99))
+}
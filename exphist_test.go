@@ -0,0 +1,94 @@
+package monkit
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestExpBucketIndex(t *testing.T) {
+	for _, test := range []struct {
+		v     float64
+		scale int32
+		index int32
+	}{
+		// scale 0 buckets are (2^i, 2^(i+1)].
+		{1, 0, -1},
+		{1.5, 0, 0},
+		{2, 0, 0},
+		{2.1, 0, 1},
+		{4, 0, 1},
+		{0.75, 0, -1},
+		{0.5, 0, -2},
+		// negative scales merge 2^-scale scale 0 buckets.
+		{4, -1, 0},
+		{5, -1, 1},
+		{16, -2, 0},
+		{17, -2, 1},
+		// scale 1 buckets grow by sqrt(2).
+		{2, 1, 1},
+		{1.4, 1, 0},
+		{1.5, 1, 1},
+		{3, 1, 3},
+		{math.MaxFloat64, 0, 1023},
+		{math.SmallestNonzeroFloat64, 0, -1075},
+	} {
+		if index := expBucketIndex(test.v, test.scale); index != test.index {
+			t.Errorf("expBucketIndex(%v, %d) = %d, expected %d", test.v, test.scale, index, test.index)
+		}
+		if test.v == 1.5 || test.v == 3 {
+			low := expBucketLowerBound(test.index, test.scale)
+			high := expBucketLowerBound(test.index+1, test.scale)
+			if !(low < test.v && test.v <= high) {
+				t.Errorf("%v not in bucket (%v, %v]", test.v, low, high)
+			}
+		}
+	}
+}
+
+func TestExpHistogram(t *testing.T) {
+	h := NewExpHistogram(NewSeriesKey("h"), 0)
+	for _, v := range []float64{0, 1, 2, 3, 4, -3} {
+		h.Observe(v)
+	}
+	s := h.Snapshot()
+	expected := ExpHistogramSnapshot{
+		Scale: 0, Count: 6, Sum: 7, Min: -3, Max: 4, ZeroCount: 1,
+		Positive: ExpHistogramBuckets{Offset: -1, BucketCounts: []uint64{1, 1, 2}},
+		Negative: ExpHistogramBuckets{Offset: 1, BucketCounts: []uint64{1}},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Fatalf("unexpected snapshot %+v", s)
+	}
+
+	// values spanning more than 160 buckets lower the scale.
+	wide := NewExpHistogram(NewSeriesKey("wide"), 3)
+	wide.Observe(1)
+	wide.Observe(1 << 30)
+	s = wide.Snapshot()
+	if s.Scale >= 3 || len(s.Positive.BucketCounts) > expHistogramMaxBuckets {
+		t.Fatalf("expected a lower scale, got %d with %d buckets", s.Scale, len(s.Positive.BucketCounts))
+	}
+	if q := s.Quantile(1); q != 1<<30 {
+		t.Fatalf("unexpected max quantile %v", q)
+	}
+}
+
+func TestExpHistogramMerge(t *testing.T) {
+	a := NewExpHistogram(NewSeriesKey("a"), 1)
+	b := NewExpHistogram(NewSeriesKey("b"), 0)
+	all := NewExpHistogram(NewSeriesKey("all"), 0)
+	for _, v := range []float64{1, 1.5, 3, 100} {
+		a.Observe(v)
+		all.Observe(v)
+	}
+	for _, v := range []float64{0, 2, 7, -1} {
+		b.Observe(v)
+		all.Observe(v)
+	}
+
+	a.Merge(b)
+	if merged, expected := a.Snapshot(), all.Snapshot(); !reflect.DeepEqual(merged, expected) {
+		t.Fatalf("merged %+v, expected %+v", merged, expected)
+	}
+}
@@ -45,6 +45,7 @@ type FloatDist struct {
 	reservoir [ReservoirSize]float32
 	rng       xorshift128
 	sorted    bool
+	watchers  *floatDistWatchers
 }
 
 func initFloatDist(v *FloatDist, key SeriesKey) {
@@ -61,6 +62,10 @@ func NewFloatDist(key SeriesKey) (d *FloatDist) {
 
 // Insert adds a value to the distribution, updating appropriate values.
 func (d *FloatDist) Insert(val float64) {
+	if w := d.watchers; w != nil {
+		// the arguments are evaluated now, before val is recorded.
+		defer w.inserted(val, d.Count == 0, d.High, d.Recent)
+	}
 	if d.Count != 0 {
 		if val < d.Low {
 			d.Low = val
@@ -96,6 +101,58 @@ func (d *FloatDist) Insert(val float64) {
 	}
 }
 
+// OnNewMax registers cb to be called with every inserted value that is a new
+// maximum, that is higher than High was, including the first value since
+// construction or the last reset. cb is called by Insert once the value is
+// recorded, so it is on the critical path of whatever inserts values and runs
+// under any lock protecting the distribution: it has to be quick and must not
+// use the distribution. Hand anything slow off to another goroutine. Like the
+// rest of the distribution, OnNewMax is not threadsafe.
+func (d *FloatDist) OnNewMax(cb func(float64)) {
+	w := d.ensureWatchers()
+	w.newMax = append(w.newMax, cb)
+}
+
+// OnThreshold registers cb to be called with every inserted value above
+// limit that is the first value since construction or the last reset, or
+// follows a value that was not above limit, so that it is called each time
+// the values cross limit upwards. cb is called like the ones of OnNewMax.
+func (d *FloatDist) OnThreshold(limit float64, cb func(float64)) {
+	w := d.ensureWatchers()
+	w.thresholds = append(w.thresholds, floatDistThreshold{limit: limit, cb: cb})
+}
+
+// floatDistWatchers are the callbacks of a FloatDist.
+type floatDistWatchers struct {
+	newMax     []func(float64)
+	thresholds []floatDistThreshold
+}
+
+type floatDistThreshold struct {
+	limit float64
+	cb    func(float64)
+}
+
+func (d *FloatDist) ensureWatchers() *floatDistWatchers {
+	if d.watchers == nil {
+		d.watchers = &floatDistWatchers{}
+	}
+	return d.watchers
+}
+
+func (w *floatDistWatchers) inserted(val float64, first bool, high, recent float64) {
+	if first || val > high {
+		for _, cb := range w.newMax {
+			cb(val)
+		}
+	}
+	for _, t := range w.thresholds {
+		if val > t.limit && (first || recent <= t.limit) {
+			t.cb(val)
+		}
+	}
+}
+
 // FullAverage calculates and returns the average of all inserted values.
 func (d *FloatDist) FullAverage() float64 {
 	if d.Count > 0 {
@@ -153,10 +210,12 @@ func (d *FloatDist) Query(quantile float64) float64 {
 	return float64(prior + diff*(float64(reservoir[idx+1])-prior))
 }
 
-// Copy returns a full copy of the entire distribution.
+// Copy returns a full copy of the entire distribution, without the callbacks
+// of OnNewMax and OnThreshold.
 func (d *FloatDist) Copy() *FloatDist {
 	cp := *d
 	cp.rng = newXORShift128()
+	cp.watchers = nil
 	return &cp
 }
 
@@ -165,6 +224,47 @@ func (d *FloatDist) Reset() {
 	// resetting count will reset the quantile reservoir
 }
 
+// Seed seeds the random number generator that picks which values stay in the
+// quantile reservoir once it is full. Identically seeded distributions that
+// observe the same values end up with identical reservoirs and quantiles.
+// Seed is primarily meant for tests, the generator is randomly seeded
+// otherwise.
+func (d *FloatDist) Seed(seed int64) {
+	d.rng.Seed(seed)
+}
+
+// Snapshot returns a summary of the distribution, with all values converted
+// to float64 the way Stats reports them. Like the rest of the distribution,
+// it is not threadsafe; the Val types provide a locked Snapshot.
+func (d *FloatDist) Snapshot() DistSnapshot {
+	s := DistSnapshot{Count: d.Count}
+	if d.Count == 0 {
+		return s
+	}
+	s.Sum = d.toFloat64(d.Sum)
+	s.Min = d.toFloat64(d.Low)
+	s.Max = d.toFloat64(d.High)
+	s.Recent = d.toFloat64(d.Recent)
+	s.Average = s.Sum / float64(d.Count)
+	s.RMin = d.toFloat64(d.Query(0))
+	s.ReservoirAverage = d.toFloat64(d.ReservoirAverage())
+	s.R10 = d.toFloat64(d.Query(.1))
+	s.R50 = d.toFloat64(d.Query(.5))
+	s.R90 = d.toFloat64(d.Query(.9))
+	s.R99 = d.toFloat64(d.Query(.99))
+	s.RMax = d.toFloat64(d.Query(1))
+
+	rlen := int64(ReservoirSize)
+	if rlen > d.Count {
+		rlen = d.Count
+	}
+	s.Reservoir = make([]float64, 0, rlen)
+	for _, v := range d.reservoir[:rlen] {
+		s.Reservoir = append(s.Reservoir, d.toFloat64(float64(v)))
+	}
+	return s
+}
+
 func (d *FloatDist) Stats(cb func(key SeriesKey, field string, val float64)) {
 	count := d.Count
 	cb(d.key, "count", float64(count))
@@ -0,0 +1,83 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"sync"
+)
+
+// Flusher is implemented by exporters and pushers that buffer data before
+// sending it elsewhere. See Registry.RegisterFlusher.
+type Flusher interface {
+	// Flush sends everything buffered so far, giving up once ctx is done.
+	Flush(ctx context.Context) error
+}
+
+// RegisterFlusher adds f to the Flushers Registry.Flush calls, until the
+// returned cancel method is called. Exporters usually register themselves
+// when they are attached to a Registry.
+func (r *Registry) RegisterFlusher(f Flusher) (cancel func()) {
+	r.watcherMtx.Lock()
+	defer r.watcherMtx.Unlock()
+
+	id := r.watcherCounter
+	r.watcherCounter += 1
+	r.flushers[id] = f
+
+	return func() {
+		r.watcherMtx.Lock()
+		defer r.watcherMtx.Unlock()
+		delete(r.flushers, id)
+	}
+}
+
+// Flush calls Flush on all registered Flushers concurrently and waits for them
+// to finish, or for ctx to be done, whichever comes first. It is meant to be
+// called once on shutdown so that buffered data isn't lost. Flush returns the
+// first error any Flusher returned, or ctx's error if it gave up waiting.
+func (r *Registry) Flush(ctx context.Context) error {
+	r.watcherMtx.Lock()
+	flushers := make([]Flusher, 0, len(r.flushers))
+	for _, f := range r.flushers {
+		flushers = append(flushers, f)
+	}
+	r.watcherMtx.Unlock()
+
+	var errOnce sync.Once
+	var firstErr error
+	var wg sync.WaitGroup
+	for _, f := range flushers {
+		wg.Add(1)
+		go func(f Flusher) {
+			defer wg.Done()
+			if err := f.Flush(ctx); err != nil {
+				errOnce.Do(func() { firstErr = err })
+			}
+		}(f)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return firstErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,54 @@
+package monkit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type mockFlusher struct {
+	delay   time.Duration
+	err     error
+	flushed int32
+}
+
+func (m *mockFlusher) Flush(ctx context.Context) error {
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	atomic.StoreInt32(&m.flushed, 1)
+	return m.err
+}
+
+func TestRegistryFlush(t *testing.T) {
+	r := NewRegistry()
+	a := &mockFlusher{delay: 10 * time.Millisecond}
+	b := &mockFlusher{delay: 20 * time.Millisecond}
+	r.RegisterFlusher(a)
+	cancel := r.RegisterFlusher(b)
+
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&a.flushed) != 1 || atomic.LoadInt32(&b.flushed) != 1 {
+		t.Fatal("expected both flushers to finish before Flush returned")
+	}
+
+	cancel()
+	boom := errors.New("boom")
+	r.RegisterFlusher(&mockFlusher{err: boom})
+	if err := r.Flush(context.Background()); err != boom {
+		t.Fatalf("expected boom, got %v", err)
+	}
+
+	r.RegisterFlusher(&mockFlusher{delay: time.Hour})
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelCtx()
+	if err := r.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be honored, got %v", err)
+	}
+}
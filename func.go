@@ -16,6 +16,7 @@ package monkit
 
 import (
 	"fmt"
+	"sort"
 )
 
 // Func represents a FuncStats bound to a particular function id, scope, and
@@ -30,13 +31,22 @@ import (
 //	  ...
 //	}
 type Func struct {
-	// sync/atomic things
+	// sync/atomic things. The 64-bit fields come first, so they are 8-byte
+	// aligned on 32-bit platforms whatever the size of FuncStats.
+	budget   int64
+	overruns int64
 	FuncStats
+	verbosity   int32
+	callerLimit int32
 
 	// constructor things
-	id    int64
-	scope *Scope
-	key   SeriesKey
+	id          int64
+	scope       *Scope
+	key         SeriesKey
+	annotations []Annotation
+
+	callers callerCounts
+	slos    sloThresholds
 }
 
 func newFunc(s *Scope, key SeriesKey) (f *Func) {
@@ -45,10 +55,25 @@ func newFunc(s *Scope, key SeriesKey) (f *Func) {
 		scope: s,
 		key:   key,
 	}
+	tags := key.Tags.All()
+	for _, name := range sortedKeys(tags) {
+		if name != "name" {
+			f.annotations = append(f.annotations, Annotation{Name: name, Value: tags[name]})
+		}
+	}
 	initFuncStats(&f.FuncStats, key)
 	return f
 }
 
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // ShortName returns the name of the function within the package
 func (f *Func) ShortName() string { return f.key.Tags.Get("name") }
 
@@ -57,6 +82,22 @@ func (f *Func) FullName() string {
 	return fmt.Sprintf("%s.%s", f.scope.name, f.key.Tags.Get("name"))
 }
 
+// Tags returns the SeriesTags the Func was created with, which are added as
+// annotations to every Span the Func starts.
+func (f *Func) Tags() []SeriesTag {
+	tags := make([]SeriesTag, 0, len(f.annotations))
+	for _, a := range f.annotations {
+		tags = append(tags, NewSeriesTag(a.Name, a.Value))
+	}
+	return tags
+}
+
+// WithTags returns the Func of the same name and Scope as f, with tags in
+// addition to f's own tags. Tags with the same key replace f's.
+func (f *Func) WithTags(tags ...SeriesTag) *Func {
+	return f.scope.FuncNamed(f.ShortName(), append(f.Tags(), tags...)...)
+}
+
 // Id returns a unique integer referencing this function
 func (f *Func) Id() int64 { return f.id }
 
@@ -15,7 +15,14 @@
 
 package monkit
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestFuncName(t *testing.T) {
 	f := Default.Package().Func()
@@ -26,3 +33,236 @@ func TestFuncName(t *testing.T) {
 		t.Fatal("invalid full name:", f.FullName())
 	}
 }
+
+func TestTaskNamedAnnotations(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("test")
+	task := mon.TaskNamed("handler", NewSeriesTag("component", "auth"), NewSeriesTag("kind", "rpc"))
+
+	if task.Func().ShortName() != "handler" {
+		t.Fatal("invalid task func:", task.Func().ShortName())
+	}
+
+	for i := 0; i < 2; i++ {
+		ctx := context.Background()
+		func() {
+			defer task(&ctx)(nil)
+			s := SpanFromCtx(ctx)
+			if s.Func().ShortName() != "handler" {
+				t.Fatal("invalid span name:", s.Func().ShortName())
+			}
+			s.Annotate("extra", "1")
+			ann := s.Annotations()
+			expected := []Annotation{{"component", "auth"}, {"kind", "rpc"}, {"extra", "1"}}
+			if !reflect.DeepEqual(ann, expected) {
+				t.Fatalf("unexpected annotations %v", ann)
+			}
+		}()
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("test")
+	f := mon.FuncNamed("measured")
+
+	if err := Measure(mon, "measured", func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	boom := errors.New("boom")
+	if err := Measure(mon, "measured", func() error { return boom }); err != boom {
+		t.Fatalf("expected the closure's error, got %v", err)
+	}
+	func() {
+		defer func() {
+			if rec := recover(); rec != "kaboom" {
+				t.Fatalf("expected the panic to propagate, got %v", rec)
+			}
+		}()
+		_ = Measure(mon, "measured", func() error { panic("kaboom") })
+	}()
+
+	if f.Success() != 1 || f.Panics() != 1 || len(f.Errors()) != 1 {
+		t.Fatalf("unexpected stats: %d successes, %d panics, %v errors",
+			f.Success(), f.Panics(), f.Errors())
+	}
+	if f.FailureTimes().Count != 2 {
+		t.Fatalf("expected 2 recorded failure durations, got %d", f.FailureTimes().Count)
+	}
+}
+
+func TestFuncTags(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("test")
+	f := mon.Func(NewSeriesTag("component", "auth"))
+	if f.ShortName() != "TestFuncTags" {
+		t.Fatal("invalid short name:", f.ShortName())
+	}
+	tagged := f.WithTags(NewSeriesTag("kind", "rpc"))
+	if tagged == f || tagged.ShortName() != "TestFuncTags" || f.WithTags() != f {
+		t.Fatal("unexpected WithTags result")
+	}
+
+	ctx := context.Background()
+	finish := tagged.Task(&ctx)
+	ann := SpanFromCtx(ctx).Annotations()
+	finish(nil)
+	expected := []Annotation{{"component", "auth"}, {"kind", "rpc"}}
+	if !reflect.DeepEqual(ann, expected) {
+		t.Fatalf("unexpected annotations %v", ann)
+	}
+
+	stats := Collect(mon)
+	if stats["function,component=auth,kind=rpc,name=TestFuncTags,scope=test successes"] != 1 {
+		t.Fatalf("missing tagged stat series in %v", stats)
+	}
+}
+
+func TestScopeFuncs(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("test")
+	mon.FuncNamed("a")
+	mon.FuncNamed("b")
+	mon.FuncNamed("b", NewSeriesTag("kind", "x"))
+	mon.Counter("not-a-func")
+
+	names := map[string]int{}
+	mon.Funcs(func(f *Func) {
+		names[f.ShortName()]++
+		// registering from within the callback must not deadlock.
+		mon.FuncNamed("c")
+	})
+	if len(names) != 2 || names["a"] != 1 || names["b"] != 2 {
+		t.Fatalf("unexpected funcs %v", names)
+	}
+
+	count := 0
+	mon.Funcs(func(f *Func) { count++ })
+	if count != 4 {
+		t.Fatalf("expected 4 funcs, got %d", count)
+	}
+}
+
+func TestRemoveFunc(t *testing.T) {
+	r := NewRegistry()
+	s := r.ScopeNamed("test")
+	ctx := context.Background()
+	s.FuncNamed("dynamic", NewSeriesTag("user", "a")).Task(&ctx)(nil)
+
+	if !s.RemoveFunc("dynamic", NewSeriesTag("user", "a")) {
+		t.Fatal("expected func to be removed")
+	}
+	if s.RemoveFunc("dynamic", NewSeriesTag("user", "a")) {
+		t.Fatal("expected second removal to fail")
+	}
+	for key := range Collect(s) {
+		if strings.HasPrefix(key, "function") {
+			t.Fatal("removed func still reported:", key)
+		}
+	}
+}
+
+func TestEvictIdleFuncs(t *testing.T) {
+	r := NewRegistry()
+	s := r.ScopeNamed("test")
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+	run := func(f *Func) func(*error) {
+		ctx := context.Background()
+		return f.Task(&ctx)
+	}
+
+	idle := s.FuncNamed("idle")
+	idle.now = clock
+	busy := s.FuncNamed("busy")
+	busy.now = clock
+	run(idle)(nil)
+	finish := run(busy)
+
+	now = now.Add(time.Minute)
+	recent := s.FuncNamed("recent")
+	recent.now = clock
+	run(recent)(nil)
+
+	if n := s.EvictIdleFuncs(30 * time.Second); n != 1 {
+		t.Fatalf("expected 1 evicted func, got %d", n)
+	}
+	stats := Collect(s)
+	if _, ok := stats["function,name=idle,scope=test total"]; ok {
+		t.Fatal("evicted func still reported")
+	}
+	for _, name := range []string{"busy", "recent"} {
+		if _, ok := stats["function,name="+name+",scope=test total"]; !ok {
+			t.Fatalf("func %q missing from stats: %v", name, stats)
+		}
+	}
+
+	finish(nil)
+	now = now.Add(time.Minute)
+	if n := s.EvictIdleFuncs(30 * time.Second); n != 2 {
+		t.Fatalf("expected 2 evicted funcs, got %d", n)
+	}
+}
+
+func TestRemoveFuncParents(t *testing.T) {
+	r := NewRegistry()
+	s := r.ScopeNamed("test")
+	parent := s.FuncNamed("parent")
+	other := s.FuncNamed("other")
+	child := r.ScopeNamed("children").FuncNamed("child")
+
+	for _, p := range []*Func{parent, other} {
+		ctx := context.Background()
+		func() {
+			defer p.Task(&ctx)(nil)
+			child.Task(&ctx)(nil)
+		}()
+	}
+
+	parents := func() map[*Func]bool {
+		rv := map[*Func]bool{}
+		child.Parents(func(f *Func) { rv[f] = true })
+		return rv
+	}
+	if got := parents(); len(got) != 2 || !got[parent] || !got[other] {
+		t.Fatalf("unexpected parents %v", got)
+	}
+	s.RemoveFunc("parent")
+	if got := parents(); len(got) != 1 || !got[other] {
+		t.Fatalf("expected the removed func to be forgotten, got %v", got)
+	}
+	if n := s.EvictIdleFuncs(0); n != 1 {
+		t.Fatalf("expected 1 evicted func, got %d", n)
+	}
+	if got := parents(); len(got) != 0 {
+		t.Fatalf("expected the evicted func to be forgotten, got %v", got)
+	}
+}
+
+func TestFuncTimesByOutcome(t *testing.T) {
+	r := NewRegistry()
+	s := r.ScopeNamed("test")
+	f := s.FuncNamed("outcomes")
+	observe := func(err error, panicked bool, duration time.Duration) {
+		f.start(nil)
+		f.end(err, panicked, duration)
+	}
+	for i := 0; i < 5; i++ {
+		observe(nil, false, time.Millisecond)
+	}
+	for i := 0; i < 2; i++ {
+		observe(errors.New("timeout"), false, time.Second)
+	}
+	observe(nil, true, 2*time.Second)
+
+	if st := f.SuccessTimes(); st.Count != 5 || st.High != time.Millisecond {
+		t.Fatalf("unexpected success times: count=%d max=%v", st.Count, st.High)
+	}
+	if ft := f.FailureTimes(); ft.Count != 3 || ft.Low != time.Second || ft.High != 2*time.Second {
+		t.Fatalf("unexpected failure times: count=%d min=%v max=%v", ft.Count, ft.Low, ft.High)
+	}
+
+	stats := Collect(s)
+	if got := stats["function_times,kind=success,name=outcomes,scope=test max"]; got != time.Millisecond.Seconds() {
+		t.Fatalf("unexpected success max %v in %v", got, stats)
+	}
+	if got := stats["function_times,kind=failure,name=outcomes,scope=test min"]; got != time.Second.Seconds() {
+		t.Fatalf("unexpected failure min %v in %v", got, stats)
+	}
+}
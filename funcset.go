@@ -56,6 +56,14 @@ func (s *funcSet) Add(f *Func) {
 	s.Mutex.Unlock()
 }
 
+// Remove removes f from the set.
+func (s *funcSet) Remove(f *Func) {
+	compareAndSwapFunc(&s.first, f, nil)
+	s.Mutex.Lock()
+	delete(s.rest, f)
+	s.Mutex.Unlock()
+}
+
 // Iterate loops over all unique elements of the set.
 func (s *funcSet) Iterate(cb func(f *Func)) {
 	s.Mutex.Lock()
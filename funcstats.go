@@ -31,6 +31,12 @@ import (
 //	  f := mon.Func()
 //	  ...
 //	}
+//
+// Durations are recorded in separate distributions per outcome, reported as
+// function_times with a kind=success and a kind=failure tag, the latter
+// including both errors and panics. This shows whether failures are fast,
+// like rejected requests, or slow, like timeouts, which a single
+// distribution would blur. See SuccessTimes and FailureTimes.
 type FuncStats struct {
 	// sync/atomic things
 	current         int64
@@ -42,12 +48,20 @@ type FuncStats struct {
 	panics       int64
 	successTimes DurationDist
 	failureTimes DurationDist
+	recent       rollingWindow
+	apdex        apdexCounts // see Func.SetApdex
+	lastUsed     time.Time
 	key          SeriesKey
+
+	// now is the time source for recent, replaceable in tests.
+	now func() time.Time
 }
 
 func initFuncStats(f *FuncStats, key SeriesKey) {
 	f.key = key
 	f.errors = map[string]int64{}
+	f.now = monotime.Now
+	f.lastUsed = f.now()
 
 	key.Measurement += "_times"
 	initDurationDist(&f.successTimes, key.WithTag("kind", "success"))
@@ -70,6 +84,7 @@ func (f *FuncStats) Reset() {
 	f.panics = 0
 	f.successTimes.Reset()
 	f.failureTimes.Reset()
+	f.recent = rollingWindow{}
 	f.parentsAndMutex.Unlock()
 }
 
@@ -87,7 +102,11 @@ func (f *FuncStats) start(parent *Func) {
 
 func (f *FuncStats) end(err error, panicked bool, duration time.Duration) {
 	atomic.AddInt64(&f.current, -1)
+	f.apdex.observe(err, panicked, duration)
 	f.parentsAndMutex.Lock()
+	now := f.now()
+	f.lastUsed = now
+	f.recent.observe(now, panicked || err != nil)
 	if panicked {
 		f.panics += 1
 		f.failureTimes.Insert(duration)
@@ -108,6 +127,15 @@ func (f *FuncStats) end(err error, panicked bool, duration time.Duration) {
 // being observed.
 func (f *FuncStats) Current() int64 { return atomic.LoadInt64(&f.current) }
 
+// LastUsed returns when the function last completed, or when the FuncStats
+// was created if it has never completed. Reset does not change it.
+func (f *FuncStats) LastUsed() (rv time.Time) {
+	f.parentsAndMutex.Lock()
+	rv = f.lastUsed
+	f.parentsAndMutex.Unlock()
+	return rv
+}
+
 // Highwater returns the highest value Current() would ever return.
 func (f *FuncStats) Highwater() int64 { return atomic.LoadInt64(&f.highwater) }
 
@@ -140,6 +168,25 @@ func (f *FuncStats) Errors() (rv map[string]int64) {
 	return rv
 }
 
+// ErrorRate returns the fraction of the calls that finished within roughly
+// the last minute that failed, or 0 if there were none. It is reported as
+// error_rate_1m.
+func (f *FuncStats) ErrorRate() (rv float64) {
+	f.parentsAndMutex.Lock()
+	rv = f.recent.errorRate(f.now())
+	f.parentsAndMutex.Unlock()
+	return rv
+}
+
+// RecentCalls returns how many calls finished within roughly the last minute,
+// the calls ErrorRate is computed from.
+func (f *FuncStats) RecentCalls() int64 {
+	f.parentsAndMutex.Lock()
+	successes, failures := f.recent.counts(f.now())
+	f.parentsAndMutex.Unlock()
+	return successes + failures
+}
+
 func (f *FuncStats) parents(cb func(f *Func)) {
 	f.parentsAndMutex.Iterate(cb)
 }
@@ -157,6 +204,7 @@ func (f *FuncStats) Stats(cb func(key SeriesKey, field string, val float64)) {
 	}
 	st := f.successTimes.Copy()
 	ft := f.failureTimes.Copy()
+	errorRate := f.recent.errorRate(f.now())
 	f.parentsAndMutex.Unlock()
 
 	cb(f.key, "successes", float64(st.Count))
@@ -169,6 +217,7 @@ func (f *FuncStats) Stats(cb func(key SeriesKey, field string, val float64)) {
 	cb(f.key, "panics", float64(panics))
 	cb(f.key, "failures", float64(e_count+panics))
 	cb(f.key, "total", float64(st.Count+e_count+panics))
+	cb(f.key, "error_rate_1m", errorRate)
 
 	st.Stats(cb)
 	ft.Stats(cb)
@@ -0,0 +1,63 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+type globalTagsRef struct {
+	tags []SeriesTag
+}
+
+// SetGlobalTags makes the Registry add the given tags, such as the host,
+// instance or version of the process, to every series reported by Stats,
+// and so by everything presenting the Registry's stats, and to the keys
+// ReadSeries matches. A series that has a tag with the same key already
+// keeps its own value. Calling SetGlobalTags replaces the tags of an
+// earlier call, and calling it without tags removes them.
+func (r *Registry) SetGlobalTags(tags ...SeriesTag) {
+	var ref *globalTagsRef
+	if len(tags) > 0 {
+		ref = &globalTagsRef{tags: append([]SeriesTag(nil), tags...)}
+	}
+	storeGlobalTagsRef(&r.globalTags, ref)
+}
+
+// GlobalTags returns the tags set by SetGlobalTags.
+func (r *Registry) GlobalTags() []SeriesTag {
+	ref := loadGlobalTagsRef(&r.globalTags)
+	if ref == nil {
+		return nil
+	}
+	return append([]SeriesTag(nil), ref.tags...)
+}
+
+func (r *registryInternal) withGlobalTags(
+	cb func(key SeriesKey, field string, val float64)) func(
+	key SeriesKey, field string, val float64) {
+	ref := loadGlobalTagsRef(&r.globalTags)
+	if ref == nil {
+		return cb
+	}
+	return func(key SeriesKey, field string, val float64) {
+		var missing []SeriesTag
+		for _, tag := range ref.tags {
+			if _, ok := key.Tags.All()[tag.Key]; !ok {
+				missing = append(missing, tag)
+			}
+		}
+		if len(missing) > 0 {
+			key = key.WithTags(missing...)
+		}
+		cb(key, field, val)
+	}
+}
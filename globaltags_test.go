@@ -0,0 +1,84 @@
+package monkit
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGlobalTags(t *testing.T) {
+	r := NewRegistry()
+	r.ScopeNamed("app").Counter("requests").Inc(1)
+	r.ScopeNamed("app").Counter("queries", NewSeriesTag("host", "db")).Inc(2)
+	r.SetGlobalTags(NewSeriesTag("host", "web"), NewSeriesTag("version", "1.2"))
+
+	stats := Collect(r)
+	for key := range stats {
+		if !strings.Contains(key, ",version=1.2 ") {
+			t.Fatalf("expected the global tags on %q", key)
+		}
+	}
+	if stats["requests,host=web,scope=app,version=1.2 value"] != 1 {
+		t.Fatalf("expected the global tags, got %v", stats)
+	}
+	// the series' own tag wins the collision.
+	if stats["queries,host=db,scope=app,version=1.2 value"] != 2 {
+		t.Fatalf("expected the series' own tag, got %v", stats)
+	}
+	if v, ok := r.ReadSeries(NewSeriesKey("requests").WithTags(
+		NewSeriesTag("host", "web"), NewSeriesTag("version", "1.2"),
+		NewSeriesTag("scope", "app"))); !ok || v != 1 {
+		t.Fatalf("expected to read the tagged series, got %v %v", v, ok)
+	}
+	if tags := r.GlobalTags(); len(tags) != 2 {
+		t.Fatalf("unexpected global tags %v", tags)
+	}
+
+	r.SetGlobalTags()
+	if stats := Collect(r); stats["requests,scope=app value"] != 1 {
+		t.Fatalf("expected the untagged series after clearing, got %v", stats)
+	}
+	if tags := r.GlobalTags(); tags != nil {
+		t.Fatalf("unexpected global tags %v", tags)
+	}
+}
+
+func TestGlobalTagsTimestampedAndDistributions(t *testing.T) {
+	r, lib := NewRegistry(), NewRegistry()
+	r.ScopeNamed("app").IntVal("size").Observe(3)
+	lib.ScopeNamed("lib").IntVal("rows").Observe(5)
+	r.Import(lib, "lib.")
+	r.SetGlobalTags(NewSeriesTag("host", "web"))
+
+	timestamped := map[string]bool{}
+	r.StatsTimestamped(func(key SeriesKey, field string, val float64, updated time.Time) {
+		timestamped[key.WithField(field)] = true
+	})
+	if !timestamped["size,host=web,scope=app count"] || !timestamped["lib.rows,host=web,scope=lib count"] {
+		t.Fatalf("expected the global tags and imports, got %v", timestamped)
+	}
+
+	dists := map[string]int64{}
+	r.Distributions(func(key SeriesKey, snap DistSnapshot) {
+		dists[key.String()] = snap.Count
+	})
+	if len(dists) != 2 || dists["size,host=web,scope=app"] != 1 || dists["lib.rows,host=web,scope=lib"] != 1 {
+		t.Fatalf("expected the global tags and imports, got %v", dists)
+	}
+
+	// transformers apply as well.
+	dropLib := CallbackTransformerFunc(func(cb func(SeriesKey, string, float64)) func(SeriesKey, string, float64) {
+		return func(key SeriesKey, field string, val float64) {
+			if key.Tags.Get("scope") != "lib" {
+				cb(key, field, val)
+			}
+		}
+	})
+	dists = map[string]int64{}
+	r.WithTransformers(dropLib).Distributions(func(key SeriesKey, snap DistSnapshot) {
+		dists[key.String()] = snap.Count
+	})
+	if len(dists) != 1 || dists["size,host=web,scope=app"] != 1 {
+		t.Fatalf("expected the transformer to apply, got %v", dists)
+	}
+}
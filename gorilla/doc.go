@@ -0,0 +1,21 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+/*
+Package gorilla records the route template a github.com/gorilla/mux router
+matched on the span of requests traced by monkit's http.TraceHandler:
+
+	router := mux.NewRouter()
+	router.Use(monkitgorilla.Middleware)
+	router.HandleFunc("/users/{id}", getUser)
+	handler := monkithttp.TraceHandlerWithOptions(router, mon, monkithttp.RouteRootName())
+
+TraceHandler has to wrap the router, so that the span exists before routing,
+while Middleware has to be installed inside of it with Use, as gorilla/mux
+only runs those middlewares once a route matched. Installed on the outermost
+router it also records the full template of routes of subrouters.
+
+It lives in its own module so that the core monkit module doesn't depend on
+gorilla/mux.
+*/
+package gorilla // import "github.com/spacemonkeygo/monkit/v3/gorilla"
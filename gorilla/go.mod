@@ -0,0 +1,10 @@
+module github.com/spacemonkeygo/monkit/v3/gorilla
+
+go 1.19
+
+require (
+	github.com/gorilla/mux v1.8.0
+	github.com/spacemonkeygo/monkit/v3 v3.0.0
+)
+
+replace github.com/spacemonkeygo/monkit/v3 => ../
@@ -0,0 +1,26 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gorilla
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	monkithttp "github.com/spacemonkeygo/monkit/v3/http"
+)
+
+// Middleware records the path template of the route gorilla/mux matched,
+// such as "/users/{id}", and its variables with monkithttp.SetRoute. It has
+// to be installed on the router with Use, within a monkithttp.TraceHandler.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route := mux.CurrentRoute(r); route != nil {
+			if template, err := route.GetPathTemplate(); err == nil {
+				monkithttp.SetRoute(r.Context(), template, mux.Vars(r))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"sync"
+)
+
+// Group is a collection of goroutines started with Go or Group.Go, each of
+// which runs in its own child Span of the context the Group was started from.
+type Group struct {
+	ctx   context.Context
+	scope *Scope
+
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// Go starts fn in a new goroutine, in a new Span of the Func called name on
+// scope, and returns a Group that more goroutines sharing the same parent
+// context can be added to. The Span is a child of the Span in ctx, if any, so
+// every goroutine is a distinct part of the same trace instead of sharing a
+// Span with its siblings. A typical fan out looks like
+//
+//	g := monkit.Go(ctx, mon, "fetch-users", fetchUsers)
+//	g.Go("fetch-groups", fetchGroups)
+//	if err := g.Wait(); err != nil {
+//	  ...
+//	}
+func Go(ctx context.Context, scope *Scope, name string,
+	fn func(ctx context.Context) error) *Group {
+	g := &Group{ctx: ctx, scope: scope}
+	g.Go(name, fn)
+	return g
+}
+
+// Go starts another goroutine in the Group, like the package level Go.
+func (g *Group) Go(name string, fn func(ctx context.Context) error) {
+	task := g.scope.TaskNamed(name)
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		ctx := g.ctx
+		err := func() (err error) {
+			defer task(&ctx)(&err)
+			return fn(ctx)
+		}()
+		if err != nil {
+			g.errOnce.Do(func() { g.err = err })
+		}
+	}()
+}
+
+// Wait blocks until all goroutines in the Group have returned, and returns the
+// first non-nil error any of them returned.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
@@ -0,0 +1,49 @@
+package monkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("group")
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+	parent := SpanFromCtx(ctx)
+
+	var mu sync.Mutex
+	spans := map[*Span]bool{}
+	record := func(ctx context.Context) error {
+		s := SpanFromCtx(ctx)
+		mu.Lock()
+		spans[s] = true
+		mu.Unlock()
+		if s.parent != parent || s.Trace() != parent.Trace() {
+			return errors.New("span not a child of the parent span")
+		}
+		return nil
+	}
+
+	g := Go(ctx, mon, "worker", record)
+	for i := 0; i < 4; i++ {
+		g.Go("worker", record)
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 5 || spans[parent] {
+		t.Fatalf("expected 5 distinct child spans, got %d", len(spans))
+	}
+	if mon.FuncNamed("worker").Success() != 5 {
+		t.Fatal("expected every goroutine to be counted")
+	}
+
+	boom := errors.New("boom")
+	g = Go(ctx, mon, "failing", func(ctx context.Context) error { return boom })
+	g.Go("worker", record)
+	if err := g.Wait(); err != boom {
+		t.Fatalf("expected boom, got %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package http
+
+import (
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/propagation"
+)
+
+// BaggageFromSpan builds a W3C `baggage` header value from the span
+// annotations with the given names, in the order the names are given, for
+// propagating them downstream. If an annotation was set more than once, the
+// latest value is used. Names the span doesn't have are skipped. See
+// propagation.FormatBaggage for how the value is encoded. The result can be
+// read back with TraceInfoFromHeader or a TraceHandler allowing the same keys.
+func BaggageFromSpan(s *monkit.Span, keys ...string) string {
+	if s == nil || len(keys) == 0 {
+		return ""
+	}
+	values := map[string]string{}
+	for _, a := range s.Annotations() {
+		values[a.Name] = a.Value
+	}
+	return propagation.FormatBaggage(values, keys...)
+}
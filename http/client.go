@@ -12,15 +12,20 @@ import (
 )
 
 // TraceRequest will perform an HTTP request, creating a new Span for the HTTP
-// request and sending the Span in the HTTP request headers.
+// request and sending the Span in the HTTP request headers, along with the
+// baggage TraceHandler received, see BaggageFromCtx, and the baggage of the
+// Trace, see monkit.Baggage, which wins for keys in both.
 // Compare to http.Client.Do.
 func TraceRequest(ctx context.Context, scope *monkit.Scope, cl Client, req *http.Request) (
 	resp *http.Response, err error) {
 	defer scope.TaskNamed(req.Method)(&ctx)(&err)
 
 	s := monkit.SpanFromCtx(ctx)
+	s.SetKind(monkit.SpanKindClient)
 	s.Annotate("http.uri", req.URL.String())
-	TraceInfoFromSpan(s).SetHeader(req.Header)
+	info := TraceInfoFromSpan(s)
+	info.Baggage = outboundBaggage(ctx)
+	info.SetHeader(req.Header)
 	resp, err = cl.Do(req)
 	if err != nil {
 		return resp, err
@@ -28,3 +33,16 @@ func TraceRequest(ctx context.Context, scope *monkit.Scope, cl Client, req *http
 	s.Annotate("http.responsecode", fmt.Sprint(resp.StatusCode))
 	return resp, nil
 }
+
+func outboundBaggage(ctx context.Context) map[string]string {
+	received, traced := BaggageFromCtx(ctx), monkit.Baggage(ctx)
+	if len(traced) == 0 {
+		return received
+	}
+	for k, v := range received {
+		if _, ok := traced[k]; !ok {
+			traced[k] = v
+		}
+	}
+	return traced
+}
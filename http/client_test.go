@@ -39,7 +39,7 @@ func TestPropagation(t *testing.T) {
 
 	s := monkit.SpanFromCtx(ctx)
 
-	expected := fmt.Sprintf("%d/hello/true (http.uri=/)", s.Id())
+	expected := fmt.Sprintf("%d/hello/true (http.host=%s,http.method=GET,http.scheme=http,http.uri=/)", s.Id(), strings.TrimPrefix(addr, "http://"))
 
 	if string(body) != expected {
 		t.Fatalf("%s!=%s", string(body), expected)
@@ -69,7 +69,7 @@ func TestBaggage(t *testing.T) {
 
 	s := monkit.SpanFromCtx(ctx)
 
-	expected := fmt.Sprintf("%d/hello/true (http.uri=/,k=v)", s.Id())
+	expected := fmt.Sprintf("%d/hello/true (http.host=%s,http.method=GET,http.scheme=http,http.uri=/,k=v)", s.Id(), strings.TrimPrefix(addr, "http://"))
 
 	if string(body) != expected {
 		t.Fatalf("%q!=%q", string(body), expected)
@@ -90,7 +90,7 @@ func TestForcedSample(t *testing.T) {
 		return http.DefaultClient.Do(request)
 	})
 
-	expected := "0/hello/true (http.uri=/)"
+	expected := fmt.Sprintf("0/hello/true (http.host=%s,http.method=GET,http.scheme=http,http.uri=/)", strings.TrimPrefix(addr, "http://"))
 
 	if string(body) != expected {
 		t.Fatalf("%q!=%q", string(body), expected)
@@ -180,3 +180,29 @@ func startHTTPServer(t *testing.T) (addr string, def func()) {
 		_ = listener.Close()
 	}
 }
+
+type clientFunc func(*http.Request) (*http.Response, error)
+
+func (f clientFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestTraceRequestTraceBaggage(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("client")
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+	monkit.SpanFromCtx(ctx).Trace().Set(present.SampledKey, true)
+	monkit.SpanFromCtx(ctx).Trace().SetBaggage("tenant", "a")
+	ctx = context.WithValue(ctx, baggageKey, map[string]string{"tenant": "b", "k": "v"})
+
+	var sent string
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	_, err := TraceRequest(ctx, mon, clientFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req.Header.Get(baggageHeader)
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != "k=v,tenant=a" {
+		t.Fatalf("unexpected baggage header %q", sent)
+	}
+}
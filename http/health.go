@@ -0,0 +1,76 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// HealthRule limits the error rate of the Funcs matching Func, which is
+// either a full Func name such as "example.com/pkg.Handle", or a prefix
+// ending in "*" such as "example.com/pkg.*".
+type HealthRule struct {
+	Func string
+	// MaxErrorRate is the highest recent error rate, see
+	// monkit.FuncStats.ErrorRate, at which the Funcs still count as healthy.
+	MaxErrorRate float64
+	// MinCalls is how many recent calls, see monkit.FuncStats.RecentCalls, a
+	// Func needs for its error rate to count, so that a single failed call
+	// of an otherwise idle Func doesn't make it unhealthy. Funcs with fewer
+	// calls always count as healthy.
+	MinCalls int64
+}
+
+func (rule HealthRule) matches(f *monkit.Func) bool {
+	name := f.FullName()
+	if strings.HasSuffix(rule.Func, "*") {
+		return strings.HasPrefix(name, rule.Func[:len(rule.Func)-1])
+	}
+	return name == rule.Func
+}
+
+// HealthHandler returns an http.Handler for health checks such as /healthz.
+// It responds with 200 OK if no Func of r matching a rule has a higher
+// recent error rate than the rule allows, and with 503 Service Unavailable
+// listing the offending Funcs otherwise. Funcs with fewer recent calls than
+// the MinCalls of a rule are skipped by it.
+func HealthHandler(r *monkit.Registry, rules []HealthRule) http.Handler {
+	rules = append([]HealthRule(nil), rules...)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var failing []string
+		r.Funcs(func(f *monkit.Func) {
+			for _, rule := range rules {
+				if !rule.matches(f) || f.RecentCalls() < rule.MinCalls {
+					continue
+				}
+				if rate := f.ErrorRate(); rate > rule.MaxErrorRate {
+					failing = append(failing, fmt.Sprintf("%s: error rate %.3f > %.3f",
+						f.FullName(), rate, rule.MaxErrorRate))
+					return
+				}
+			}
+		})
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if len(failing) == 0 {
+			_, _ = fmt.Fprintln(w, "ok")
+			return
+		}
+		sort.Strings(failing)
+		var body bytes.Buffer
+		for _, line := range failing {
+			body.WriteString(line)
+			body.WriteByte('\n')
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write(body.Bytes())
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestHealthHandler(t *testing.T) {
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("example.com/svc")
+	call := func(name string, fail bool) {
+		ctx := context.Background()
+		var err error
+		if fail {
+			err = errors.New("failed")
+		}
+		mon.TaskNamed(name)(&ctx)(&err)
+	}
+
+	handler := HealthHandler(r, []HealthRule{
+		{Func: "example.com/svc.Get*", MaxErrorRate: 0.5},
+		{Func: "example.com/svc.Put", MaxErrorRate: 0.1},
+	})
+	check := func() (int, string) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	call("GetUser", false)
+	call("GetUser", true)
+	call("Put", false)
+	// not covered by any rule.
+	call("Delete", true)
+	if code, body := check(); code != http.StatusOK {
+		t.Fatalf("expected healthy, got %d: %s", code, body)
+	}
+
+	call("Put", true)
+	code, body := check()
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("expected unhealthy, got %d: %s", code, body)
+	}
+	if !strings.Contains(body, "example.com/svc.Put: error rate 0.500 > 0.100") ||
+		strings.Contains(body, "GetUser") {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestHealthHandlerMinCalls(t *testing.T) {
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("example.com/svc")
+	handler := HealthHandler(r, []HealthRule{
+		{Func: "example.com/svc.*", MaxErrorRate: 0.1, MinCalls: 3},
+	})
+	check := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+		return rec.Code
+	}
+
+	// a single failure of an idle Func is not enough traffic to judge it.
+	ctx := context.Background()
+	err := errors.New("failed")
+	mon.TaskNamed("Idle")(&ctx)(&err)
+	if code := check(); code != http.StatusOK {
+		t.Fatalf("expected healthy, got %d", code)
+	}
+
+	mon.TaskNamed("Idle")(&ctx)(nil)
+	mon.TaskNamed("Idle")(&ctx)(nil)
+	if code := check(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected unhealthy, got %d", code)
+	}
+}
@@ -27,6 +27,11 @@ var _ http.ResponseWriter = &responseWriterObserver{}
 
 // Wrap wraps original writer + provides func to retrieve statusCode, implements http.Flusher if original writer also did it.
 func Wrap(w http.ResponseWriter) (http.ResponseWriter, func() int) {
+	wrapped, observer := wrap(w)
+	return wrapped, observer.StatusCode
+}
+
+func wrap(w http.ResponseWriter) (http.ResponseWriter, *responseWriterObserver) {
 	observer := &responseWriterObserver{
 		w: w,
 	}
@@ -38,9 +43,9 @@ func Wrap(w http.ResponseWriter) (http.ResponseWriter, func() int) {
 		}{
 			ResponseWriter: observer,
 			Flusher:        flusher,
-		}, observer.StatusCode
+		}, observer
 	}
-	return observer, observer.StatusCode
+	return observer, observer
 }
 
 type responseWriterObserver struct {
@@ -64,6 +69,9 @@ func (w *responseWriterObserver) Header() http.Header {
 	return w.w.Header()
 }
 
+// written returns whether the response header was sent already.
+func (w *responseWriterObserver) written() bool { return w.sc != 0 }
+
 func (w *responseWriterObserver) StatusCode() int {
 	if w.sc == 0 {
 		return 200
@@ -0,0 +1,104 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package http
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/present"
+)
+
+// Index returns an http.Handler serving the endpoints of present.HTTP for the
+// given Registry, with an HTML index linking to all of them at its root, much
+// like net/http/pprof's Index. It is meant to be mounted below a prefix that
+// is stripped from the request, for example:
+//
+//	mux.Handle("/monkit/", http.StripPrefix("/monkit", monkithttp.Index(monkit.Default)))
+//
+// The links on the index page are relative, so the page has to be requested
+// with a trailing slash, like /monkit/.
+func Index(r *monkit.Registry) http.Handler {
+	return index{registry: r, present: present.HTTP(r)}
+}
+
+type index struct {
+	registry *monkit.Registry
+	present  http.Handler
+}
+
+type indexEntry struct {
+	Name    string
+	Formats []string
+	Count   int
+	Desc    string
+}
+
+func (i index) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "" && r.URL.Path != "/" {
+		i.present.ServeHTTP(w, r)
+		return
+	}
+
+	var funcs, spans, scopes int
+	i.registry.Funcs(func(*monkit.Func) { funcs++ })
+	i.registry.AllSpans(func(*monkit.Span) { spans++ })
+	i.registry.Scopes(func(*monkit.Scope) { scopes++ })
+
+	entries := []indexEntry{
+		{Name: "ps", Formats: []string{"text", "json", "dot"}, Count: spans,
+			Desc: "Currently running spans, grouped by trace."},
+		{Name: "funcs", Formats: []string{"text", "json", "dot", "markdown"}, Count: funcs,
+			Desc: "All observed functions and how they call each other. " +
+				"The markdown format accepts ?sort=calls, errors, p50 or p99 and ?n= to only list the top functions."},
+		{Name: "stats", Formats: []string{"text", "json", "grouped", "sorted"}, Count: scopes,
+			Desc: "Statistics about all observed functions, scopes and values. " +
+				"The text and json formats accept ?keys=dot, ?keys=underscore or ?keys=camel to change how series keys are written."},
+	}
+
+	var buf bytes.Buffer
+	if err := indexTemplate.Execute(&buf, entries); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>monkit</title>
+<style>
+.entries td { padding-right: 1em; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>monkit</h1>
+<table class="entries">
+<thead><tr><td>Count</td><td>Endpoint</td><td>Formats</td></tr></thead>
+<tbody>
+{{- range . }}{{ $name := .Name }}
+<tr><td>{{ .Count }}</td><td><a href="{{ .Name }}">{{ .Name }}</a></td><td>
+{{- range $i, $f := .Formats }}{{ if $i }} | {{ end }}<a href="{{ $name }}/{{ $f }}">{{ $f }}</a>{{ end -}}
+</td></tr>
+{{- end }}
+</tbody>
+</table>
+<dl>
+{{- range . }}
+<dt><a href="{{ .Name }}">{{ .Name }}</a>:</dt><dd>{{ .Desc }}</dd>
+{{- end }}
+<dt>trace/json, trace/svg, trace/chrome, trace/attachments:</dt><dd>Trace the next span matching the
+<code>?regex=</code> or <code>?trace_id=</code> query parameters, for example
+<a href="trace/svg?regex=.">trace/svg?regex=.</a>. Use
+<code>&amp;min_duration=</code> to only capture traces running at least that
+long.</dd>
+</dl>
+</body>
+</html>
+`))
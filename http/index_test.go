@@ -0,0 +1,47 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestIndex(t *testing.T) {
+	r := monkit.NewRegistry()
+	r.ScopeNamed("index").Counter("requests").Inc(1)
+
+	mux := http.NewServeMux()
+	mux.Handle("/monkit/", http.StripPrefix("/monkit", Index(r)))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/monkit/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	body := rec.Body.String()
+	for _, link := range []string{
+		`href="ps"`, `href="ps/json"`, `href="ps/dot"`,
+		`href="funcs"`, `href="funcs/json"`, `href="funcs/dot"`,
+		`href="stats"`, `href="stats/text"`, `href="stats/json"`, `href="stats/grouped"`,
+		`href="trace/svg?regex=."`,
+	} {
+		if !strings.Contains(body, link) {
+			t.Errorf("index is missing %s", link)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/monkit/stats/text", nil))
+	if !strings.Contains(rec.Body.String(), "requests,scope=index value=1") {
+		t.Fatalf("unexpected stats %q", rec.Body.String())
+	}
+}
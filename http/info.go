@@ -15,12 +15,11 @@
 package http
 
 import (
-	"fmt"
-	"strconv"
-	"strings"
+	"context"
 
 	"github.com/spacemonkeygo/monkit/v3"
 	"github.com/spacemonkeygo/monkit/v3/present"
+	"github.com/spacemonkeygo/monkit/v3/propagation"
 )
 
 const (
@@ -50,6 +49,10 @@ type TraceInfo struct {
 	ParentId *int64
 	Sampled  bool
 	Baggage  map[string]string
+
+	// Flags are the W3C trace-flags. SetHeader keeps the sampled bit in sync
+	// with Sampled.
+	Flags byte
 }
 
 // HeaderGetter is an interface that http.Header matches for RequestFromHeader
@@ -63,63 +66,14 @@ type HeaderSetter interface {
 }
 
 // TraceInfoFromHeader will create a TraceInfo object given a http.Header or
-// anything that matches the HeaderGetter interface.
+// anything that matches the HeaderGetter interface. Only the baggage keys in
+// allowedBaggage are kept. An allowed key ending in "*" is a prefix pattern,
+// so "tenant-*" allows "tenant-id" and "*" allows every key. The headers are
+// parsed by propagation.W3C.
 func TraceInfoFromHeader(header HeaderGetter, allowedBaggage ...string) (rv TraceInfo) {
-	traceParent := header.Get(traceParentHeader)
-	traceState := header.Get(traceStateHeader)
-	baggage := header.Get(baggageHeader)
-	bm := map[string]string{}
-	if baggage != "" {
-		for _, kv := range strings.Split(baggage, ",") {
-			if key, value, ok := strings.Cut(kv, "="); ok {
-				for _, b := range allowedBaggage {
-					if key == b {
-						bm[key] = value
-						break
-					}
-				}
-			}
-		}
-	}
-
-	if traceParent != "" {
-		parts := strings.Split(traceParent, "-")
-		if len(parts) != 4 {
-			return rv
-		}
-		version, err := hexToUint64(parts[0])
-		if err != nil || version != 0 {
-			return rv
-		}
-		traceID, err := hexToUint64(parts[1])
-		if err != nil {
-			return rv
-		}
-		parentID, err := hexToUint64(parts[2])
-		if err != nil {
-			return rv
-		}
-		flags, err := hexToUint64(parts[3])
-		if err != nil {
-			return rv
-		}
-
-		return TraceInfo{
-			TraceId:  &traceID,
-			ParentId: &parentID,
-			Sampled:  (byte(flags) & traceSampled) == traceSampled,
-			Baggage:  bm,
-		}
-	}
-
-	// trace parent is not set, but tracing can be turned on by a traceState
-	if strings.Contains(traceState, "sampled=true") {
-		return TraceInfo{
-			Sampled: true,
-			Baggage: bm,
-		}
-	}
-	return rv
+	remote, _ := propagation.RemoteFromCtx(propagation.W3C{AllowedBaggage: allowedBaggage}.
+		Extract(context.Background(), header))
+	return TraceInfo(remote)
 }
 
 func ref(v int64) *int64 {
@@ -139,6 +93,7 @@ func TraceInfoFromSpan(s *monkit.Span) TraceInfo {
 		TraceId:  ref(trace.Id()),
 		ParentId: ref(s.Id()),
 		Sampled:  sampled,
+		Flags:    trace.Flags(),
 	}
 	if parentID, hasParent := s.ParentId(); hasParent {
 		req.ParentId = ref(parentID)
@@ -149,27 +104,18 @@ func TraceInfoFromSpan(s *monkit.Span) TraceInfo {
 // SetHeader will take a TraceInfo and fill out an http.Header, or anything that
 // matches the HeaderSetter interface.
 func (r TraceInfo) SetHeader(header HeaderSetter) {
-	sampled := byte(0)
+	flags := r.Flags &^ traceSampled
 	if r.Sampled {
-		sampled = traceSampled
+		flags |= traceSampled
 	}
 	if r.TraceId != nil && r.ParentId != nil {
-		header.Set(traceParentHeader, fmt.Sprintf("00-%016x-%08x-%x", *r.TraceId, *r.ParentId, int(sampled)))
+		header.Set(traceParentHeader, propagation.FormatTraceParent(
+			monkit.IDFromInt64(*r.TraceId), *r.ParentId, flags))
 	} else if r.Sampled {
 		header.Set(traceStateHeader, orphanSampling)
 	}
 
-	var baggage []string
 	if r.Baggage != nil {
-		for k, v := range r.Baggage {
-			baggage = append(baggage, fmt.Sprintf("%s=%s", k, v))
-		}
-		header.Set(baggageHeader, strings.Join(baggage, ","))
+		header.Set(baggageHeader, propagation.FormatBaggage(r.Baggage))
 	}
 }
-
-// hexToUint64 reads a signed int64 that has been formatted as a hex uint64
-func hexToUint64(s string) (int64, error) {
-	v, err := strconv.ParseUint(s, 16, 64)
-	return int64(v), err
-}
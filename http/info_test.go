@@ -4,8 +4,11 @@
 package http
 
 import (
+	"context"
 	"net/http"
 	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
 )
 
 func TestSetHeader(t *testing.T) {
@@ -28,7 +31,7 @@ func TestSetHeader(t *testing.T) {
 				ParentId: ref(2),
 				Sampled:  false,
 			},
-			expectedParent: "00-0000000000000001-00000002-0",
+			expectedParent: "00-00000000000000000000000000000001-0000000000000002-00",
 			expectedState:  "",
 		},
 		{
@@ -43,7 +46,22 @@ func TestSetHeader(t *testing.T) {
 				ParentId: ref(16),
 				Sampled:  true,
 			},
-			expectedParent: "00-0000000000000001-00000010-1",
+			expectedParent: "00-00000000000000000000000000000001-0000000000000010-01",
+			expectedState:  "",
+		},
+		{
+			name: "ids above 2^63",
+			info: TraceInfo{
+				TraceId:  ref(-1),
+				ParentId: ref(-2),
+				Sampled:  true,
+			},
+			expectedInfo: TraceInfo{
+				TraceId:  ref(-1),
+				ParentId: ref(-2),
+				Sampled:  true,
+			},
+			expectedParent: "00-0000000000000000ffffffffffffffff-fffffffffffffffe-01",
 			expectedState:  "",
 		},
 		{
@@ -79,7 +97,7 @@ func TestSetHeader(t *testing.T) {
 					"k": "v1",
 				},
 			},
-			expectedParent: "00-0000000000000001-00000010-1",
+			expectedParent: "00-00000000000000000000000000000001-0000000000000010-01",
 			expectedState:  "",
 		},
 		{
@@ -143,3 +161,27 @@ func checkEq(t *testing.T, v1 *int64, v2 *int64) {
 		t.Fatalf("%d!=%d", v1, v2)
 	}
 }
+
+func TestBaggageFromSpan(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("baggage")
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+	s := monkit.SpanFromCtx(ctx)
+	s.Annotate("tenant", "old")
+	s.Annotate("tenant", "acme corp")
+	s.Annotate("region", "eu,west")
+	s.Annotate("private", "secret")
+
+	baggage := BaggageFromSpan(s, "tenant", "region", "missing")
+	if baggage != "tenant=acme%20corp,region=eu%2Cwest" {
+		t.Fatalf("unexpected baggage %q", baggage)
+	}
+
+	header := http.Header{}
+	header.Set(traceStateHeader, orphanSampling)
+	header.Set(baggageHeader, baggage)
+	info := TraceInfoFromHeader(header, "tenant", "region")
+	if len(info.Baggage) != 2 || info.Baggage["tenant"] != "acme corp" || info.Baggage["region"] != "eu,west" {
+		t.Fatalf("unexpected baggage after round trip: %v", info.Baggage)
+	}
+}
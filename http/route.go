@@ -0,0 +1,81 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// RouteParamsFunc receives the route pattern a request matched, such as
+// "/users/{id}", and the values of its path parameters, and returns the
+// parameters to record as annotations, with values redacted as needed. It
+// may drop parameters, rewrite their values (see HashRouteParam), or return
+// nil to record none. The params map must not be modified.
+type RouteParamsFunc func(route string, params map[string]string) map[string]string
+
+// RouteParams makes SetRoute record the route parameters fn returns as
+// http.route.param.<name> annotations. Without it only the route itself is
+// recorded, since parameters often contain identifiers of users.
+func RouteParams(fn RouteParamsFunc) TraceHandlerOption {
+	return func(t *traceHandler) { t.routeParams = fn }
+}
+
+// RouteRootName makes SetRoute name the Trace of the request after its
+// method and route, such as "GET /users/{id}", so that traces can be grouped
+// by endpoint without one name per distinct URL like with MethodAndPath. It
+// takes precedence over RootName once the route is known. See the chi and
+// gorilla modules for adapters that call SetRoute for those routers.
+func RouteRootName() TraceHandlerOption {
+	return func(t *traceHandler) { t.routeRootName = true }
+}
+
+type routeState struct {
+	span     *monkit.Span
+	params   RouteParamsFunc
+	method   string
+	rootName bool
+}
+
+// SetRoute records the route a request handled by TraceHandler matched on
+// the request's span, as the http.route annotation, along with the
+// parameters the RouteParams option allows. Routers run inside TraceHandler,
+// so it is meant to be called by them or by a handler once the route is
+// known, with the request's context. It does nothing for requests that
+// weren't traced by TraceHandler.
+func SetRoute(ctx context.Context, route string, params map[string]string) {
+	state, _ := ctx.Value(routeKey).(*routeState)
+	if state == nil {
+		return
+	}
+	state.span.Annotate("http.route", route)
+	if state.rootName {
+		state.span.Trace().SetRootName(state.method + " " + route)
+	}
+	if state.params == nil {
+		return
+	}
+	annotated := state.params(route, params)
+	names := make([]string, 0, len(annotated))
+	for name := range annotated {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		state.span.Annotate("http.route.param."+name, annotated[name])
+	}
+}
+
+// HashRouteParam replaces a parameter value with a short hash of it, so
+// that requests for the same value can be correlated without recording the
+// value itself. The hash is unsalted, so values from a small set, like
+// sequential ids, can be recovered by hashing candidates; drop those instead.
+func HashRouteParam(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:8])
+}
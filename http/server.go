@@ -4,69 +4,476 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"runtime/debug"
+	"sort"
+	"strings"
 
 	"github.com/spacemonkeygo/monkit/v3"
-	"github.com/spacemonkeygo/monkit/v3/present"
+	"github.com/spacemonkeygo/monkit/v3/propagation"
 )
 
 // TraceHandler wraps a HTTPHandler and import trace information from header.
+// Panics of the wrapped handler are recovered and answered with a 500, and
+// the span is finished as failed with panic annotations. See Repanic.
 func TraceHandler(c http.Handler, scope *monkit.Scope, allowedBaggage ...string) http.Handler {
-	return traceHandler{
-		handler:        c,
-		scope:          scope,
-		allowedBaggage: allowedBaggage,
+	return TraceHandlerWithOptions(c, scope, AllowedBaggage(allowedBaggage...))
+}
+
+// TraceHandlerAllowAllBaggage is like TraceHandler, but imports every key of
+// the `baggage` HTTP header instead of only allowed ones. See AllowAllBaggage
+// for why that is rarely a good idea.
+func TraceHandlerAllowAllBaggage(c http.Handler, scope *monkit.Scope) http.Handler {
+	return TraceHandlerWithOptions(c, scope, AllowAllBaggage())
+}
+
+// TraceHandlerWithRootName is like TraceHandler, but names the Trace of every
+// request with rootName (see monkit.Trace.RootName), so that traces can be
+// grouped by entry point. MethodAndPath is a good choice for rootName.
+func TraceHandlerWithRootName(c http.Handler, scope *monkit.Scope,
+	rootName func(*http.Request) string, allowedBaggage ...string) http.Handler {
+	return TraceHandlerWithOptions(c, scope,
+		AllowedBaggage(allowedBaggage...), RootName(rootName))
+}
+
+// TraceHandlerWithAnnotations is like TraceHandler, but adds the given static
+// annotations, such as service=api, to the span of every request.
+func TraceHandlerWithAnnotations(c http.Handler, scope *monkit.Scope,
+	annotations map[string]string, allowedBaggage ...string) http.Handler {
+	return TraceHandlerWithOptions(c, scope,
+		AllowedBaggage(allowedBaggage...), Annotations(annotations))
+}
+
+// TraceHandlerWithHeaders is like TraceHandler, but records the given
+// request headers, such as User-Agent or X-Request-Id, as span annotations.
+// See RecordHeaders.
+func TraceHandlerWithHeaders(c http.Handler, scope *monkit.Scope,
+	headers ...string) http.Handler {
+	return TraceHandlerWithOptions(c, scope, RecordHeaders(headers...))
+}
+
+// TraceHandlerWithOptions is like TraceHandler, configured with opts.
+func TraceHandlerWithOptions(c http.Handler, scope *monkit.Scope,
+	opts ...TraceHandlerOption) http.Handler {
+	t := traceHandler{
+		handler: c,
+		scope:   scope,
+		keys:    DefaultAnnotationKeys,
+	}
+	for _, opt := range opts {
+		opt(&t)
+	}
+	return t
+}
+
+// TraceHandlerOption configures a handler created by TraceHandlerWithOptions.
+type TraceHandlerOption func(*traceHandler)
+
+// AllowedBaggage imports the given keys of the `baggage: k=v` HTTP header as
+// span annotations and makes them available through BaggageFromCtx and, to
+// every span of the request's trace, through monkit.Baggage. Keys ending in
+// "*" are prefix patterns, see TraceInfoFromHeader. An allow-list
+// kept in a slice can be passed as AllowedBaggage(list...), and the option can
+// be given more than once.
+func AllowedBaggage(keys ...string) TraceHandlerOption {
+	return func(t *traceHandler) {
+		t.allowedBaggage = append(t.allowedBaggage, keys...)
+	}
+}
+
+// AllowAllBaggage imports every key of the `baggage` HTTP header, like
+// AllowedBaggage("*").
+//
+// The baggage header is supplied by the client, so for anything facing
+// untrusted clients this lets them attach arbitrary annotations to every span,
+// which end up in all trace exporters and can grow without bound, as well as
+// inject values that downstream code reading BaggageFromCtx may trust. Only
+// use it behind a trusted proxy or between internal services.
+func AllowAllBaggage() TraceHandlerOption {
+	return AllowedBaggage("*")
+}
+
+// SampledBaggageAnnotations only adds the allowed baggage as span
+// annotations when the request's trace is sampled, saving their memory on
+// the usually much more common unsampled requests. The baggage is still
+// available through BaggageFromCtx and forwarded by TraceRequest either way.
+func SampledBaggageAnnotations() TraceHandlerOption {
+	return func(t *traceHandler) { t.sampledBaggageOnly = true }
+}
+
+// Propagator makes TraceHandler extract the trace context of requests with p
+// instead of from the W3C traceparent, tracestate and baggage headers, for
+// instance with a propagation.Header for a custom header. AllowedBaggage
+// doesn't apply to p, which decides on its own what baggage to extract. The
+// extracted context is kept in the request's context, see
+// propagation.RemoteFromCtx.
+func Propagator(p propagation.TextMapPropagator) TraceHandlerOption {
+	return func(t *traceHandler) { t.propagator = p }
+}
+
+// TraceQueryParam makes TraceHandler read the trace context from the URL
+// query parameter name, such as "traceparent", in the format of the W3C
+// traceparent header, for requests without trace context in their headers.
+// This is meant for webhooks and links from systems that can't set headers;
+// the headers take precedence. Like the headers, the parameter is supplied by
+// the client, see LinkUpstream for not trusting it.
+func TraceQueryParam(name string) TraceHandlerOption {
+	return func(t *traceHandler) { t.queryParam = name }
+}
+
+// OnFinish calls cb with the span of every request once it finished, with all
+// its annotations, including the response code, in place. Unlike a
+// monkit.SpanObserver, it only sees the spans of this handler, and nothing
+// has to be registered process-wide. cb runs on the request's goroutine
+// before ServeHTTP returns.
+func OnFinish(cb func(*monkit.Span)) TraceHandlerOption {
+	return func(t *traceHandler) { t.onFinish = cb }
+}
+
+// RootName names the Trace of every request with rootName. See
+// TraceHandlerWithRootName.
+func RootName(rootName func(*http.Request) string) TraceHandlerOption {
+	return func(t *traceHandler) { t.rootName = rootName }
+}
+
+// Annotations adds the given static annotations to the span of every request,
+// in addition to the ones the handler adds itself.
+func Annotations(annotations map[string]string) TraceHandlerOption {
+	return func(t *traceHandler) {
+		keys := make([]string, 0, len(annotations))
+		for k := range annotations {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			t.annotations = append(t.annotations, monkit.Annotation{Name: k, Value: annotations[k]})
+		}
+	}
+}
+
+// RecordHeaders records the given request headers as span annotations named
+// after AnnotationKeys.HeaderPrefix and the lowercased header name, such as
+// http.header.user-agent, joining multiple values with ", ". Header names are
+// matched case-insensitively, but unlike AllowedBaggage there are no
+// patterns, as headers such as Authorization or Cookie carry secrets. Headers
+// not in the list and headers missing from a request are not recorded. The
+// option can be given more than once.
+func RecordHeaders(headers ...string) TraceHandlerOption {
+	return func(t *traceHandler) {
+		for _, h := range headers {
+			t.headers = append(t.headers, http.CanonicalHeaderKey(h))
+		}
+	}
+}
+
+// SkipPaths passes requests for the given URL paths straight to the wrapped
+// handler, without starting a span, which is useful for health checks and
+// metrics endpoints. Paths ending in a slash match every path below them,
+// like with http.ServeMux, other paths have to match exactly.
+func SkipPaths(paths ...string) TraceHandlerOption {
+	return func(t *traceHandler) {
+		t.skipPaths = append(t.skipPaths, paths...)
 	}
 }
 
+// LinkUpstream starts a new Trace for every request instead of continuing
+// the one of the caller, and links the caller's Span to it, see
+// monkit.Scope.LinkTrace. This keeps the causality across services, for
+// instance at the edge of a system where incoming trace ids are not trusted.
+// Sampling decisions of the caller still apply.
+func LinkUpstream() TraceHandlerOption {
+	return func(t *traceHandler) { t.linkUpstream = true }
+}
+
+// Repanic makes TraceHandler panic again after recovering and recording a
+// panic of the wrapped handler, so that it reaches net/http or other
+// middleware. The span then also counts as panicked rather than failed.
+func Repanic() TraceHandlerOption {
+	return func(t *traceHandler) { t.repanic = true }
+}
+
+// PanicStack makes TraceHandler record the stack of a recovered panic as a
+// panic.stack annotation.
+func PanicStack() TraceHandlerOption {
+	return func(t *traceHandler) { t.panicStack = true }
+}
+
+// AnnotationKeys are the annotation names TraceHandler records the request
+// and response with. Empty names fall back to DefaultAnnotationKeys.
+type AnnotationKeys struct {
+	URI          string
+	Method       string
+	Host         string
+	Scheme       string
+	ResponseCode string
+	// HeaderPrefix is prepended to the lowercased names of the headers
+	// recorded with RecordHeaders.
+	HeaderPrefix string
+}
+
+var (
+	// DefaultAnnotationKeys are the annotation names TraceHandler uses unless
+	// configured otherwise.
+	DefaultAnnotationKeys = AnnotationKeys{
+		URI:          "http.uri",
+		Method:       "http.method",
+		Host:         "http.host",
+		Scheme:       "http.scheme",
+		ResponseCode: "http.responsecode",
+		HeaderPrefix: "http.header.",
+	}
+
+	// OTelAnnotationKeys follow the OpenTelemetry HTTP semantic conventions.
+	// The request URI, which includes the query, is recorded as http.target.
+	OTelAnnotationKeys = AnnotationKeys{
+		URI:          "http.target",
+		Method:       "http.request.method",
+		Host:         "server.address",
+		Scheme:       "url.scheme",
+		ResponseCode: "http.response.status_code",
+		HeaderPrefix: "http.request.header.",
+	}
+)
+
+// WithAnnotationKeys changes the annotation names TraceHandler records the
+// request and response with, for example to OTelAnnotationKeys.
+func WithAnnotationKeys(keys AnnotationKeys) TraceHandlerOption {
+	return func(t *traceHandler) {
+		t.keys = keys.withDefaults()
+	}
+}
+
+// URIAnnotationKey changes only the annotation name of the request URI, such
+// as to http.target or http.url.
+func URIAnnotationKey(key string) TraceHandlerOption {
+	return func(t *traceHandler) {
+		t.keys.URI = key
+		t.keys = t.keys.withDefaults()
+	}
+}
+
+func (k AnnotationKeys) withDefaults() AnnotationKeys {
+	fallback := func(name *string, def string) {
+		if *name == "" {
+			*name = def
+		}
+	}
+	fallback(&k.URI, DefaultAnnotationKeys.URI)
+	fallback(&k.Method, DefaultAnnotationKeys.Method)
+	fallback(&k.Host, DefaultAnnotationKeys.Host)
+	fallback(&k.Scheme, DefaultAnnotationKeys.Scheme)
+	fallback(&k.ResponseCode, DefaultAnnotationKeys.ResponseCode)
+	fallback(&k.HeaderPrefix, DefaultAnnotationKeys.HeaderPrefix)
+	return k
+}
+
+// MethodAndPath names a request after its method and URL path, such as
+// "GET /users". See TraceHandlerWithRootName.
+func MethodAndPath(r *http.Request) string {
+	return r.Method + " " + r.URL.Path
+}
+
 type traceHandler struct {
-	handler http.Handler
-	scope   *monkit.Scope
+	handler    http.Handler
+	scope      *monkit.Scope
+	rootName   func(*http.Request) string
+	skipPaths  []string
+	headers    []string
+	queryParam string
+
+	linkUpstream       bool
+	repanic            bool
+	panicStack         bool
+	sampledBaggageOnly bool
+	routeRootName      bool
+
+	annotations []monkit.Annotation
+	keys        AnnotationKeys
+	routeParams RouteParamsFunc
+	propagator  propagation.TextMapPropagator
+	onFinish    func(*monkit.Span)
 
 	// allowedBaggage defines the allowed `baggage: k=v` HTTP headers which are imported as scan annotations.
 	allowedBaggage []string
 }
 
+func (t traceHandler) skipped(path string) bool {
+	for _, skip := range t.skipPaths {
+		if path == skip || (strings.HasSuffix(skip, "/") && strings.HasPrefix(path, skip)) {
+			return true
+		}
+	}
+	return false
+}
+
+type ctxKey int
+
+const (
+	baggageKey ctxKey = iota
+	routeKey
+)
+
+// BaggageFromCtx returns the allowed baggage TraceHandler parsed from the
+// request headers, or nil if there was none. The map must not be modified.
+func BaggageFromCtx(ctx context.Context) map[string]string {
+	baggage, _ := ctx.Value(baggageKey).(map[string]string)
+	return baggage
+}
+
 // ServeHTTP implements http.Handler with span propagation.
 func (t traceHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if t.skipped(request.URL.Path) {
+		t.handler.ServeHTTP(writer, request)
+		return
+	}
 
-	info := TraceInfoFromHeader(request.Header, t.allowedBaggage...)
+	var info TraceInfo
+	var remote propagation.Remote
+	var hasRemote bool
+	if t.propagator != nil {
+		remote, hasRemote = propagation.RemoteFromCtx(
+			t.propagator.Extract(context.Background(), request.Header))
+		info = TraceInfo(remote)
+	} else {
+		info = TraceInfoFromHeader(request.Header, t.allowedBaggage...)
+	}
+	if info.TraceId == nil && t.queryParam != "" {
+		if value := request.URL.Query().Get(t.queryParam); value != "" {
+			header := http.Header{}
+			header.Set(traceParentHeader, value)
+			baggage := info.Baggage
+			info = TraceInfoFromHeader(header)
+			info.Baggage = baggage
+			if t.propagator != nil && info.TraceId != nil {
+				remote, hasRemote = propagation.Remote(info), true
+			}
+		}
+	}
 
 	traceId := monkit.NewId()
 	if info.TraceId != nil {
 		traceId = *info.TraceId
 	}
 
-	trace := monkit.NewTrace(traceId)
-	ctx := request.Context()
-
 	parent := int64(0)
 	if info.ParentId != nil {
 		parent = *info.ParentId
 	}
 
-	if info.Sampled {
-		trace.Set(present.SampledKey, true)
-	}
-	defer t.scope.Func().RemoteTrace(&ctx, parent, trace)(nil)
+	linked := t.linkUpstream && info.TraceId != nil && info.ParentId != nil
 
-	if cb, exists := trace.Get(present.SampledCBKey).(func(*monkit.Trace)); exists {
-		cb(trace)
+	var err error
+	ctx := request.Context()
+	baggageAnnotations := info.Baggage
+	if t.sampledBaggageOnly && !info.Sampled {
+		baggageAnnotations = nil
+	}
+	var s *monkit.Span
+	if t.onFinish != nil {
+		// deferred first, so it runs once the span finished.
+		defer func() { t.onFinish(s) }()
+	}
+	if linked {
+		defer t.scope.LinkTrace(&ctx, traceId, parent, info.Sampled, baggageAnnotations)(&err)
+	} else {
+		defer t.scope.ContinueTrace(&ctx, traceId, parent, info.Sampled, baggageAnnotations)(&err)
 	}
 
-	s := monkit.SpanFromCtx(ctx)
+	s = monkit.SpanFromCtx(ctx)
+	// only adds flags, so a Trace sampled while its span started, for
+	// instance by an ObserveTraces callback, stays sampled.
+	flags := s.Trace().Flags() | info.Flags
+	if info.Sampled {
+		flags |= monkit.TraceFlagSampled
+	}
+	s.Trace().SetFlags(flags)
 	for k, v := range info.Baggage {
-		s.Annotate(k, v)
+		s.Trace().SetBaggage(k, v)
+	}
+	s.SetKind(monkit.SpanKindServer)
+	if t.rootName != nil {
+		s.Trace().SetRootName(t.rootName(request))
+	}
+	for _, a := range t.annotations {
+		s.Annotate(a.Name, a.Value)
+	}
+	s.Annotate(t.keys.URI, request.RequestURI)
+	s.Annotate(t.keys.Method, request.Method)
+	s.Annotate(t.keys.Host, request.Host)
+	s.Annotate(t.keys.Scheme, requestScheme(request))
+	for _, h := range t.headers {
+		if values := request.Header.Values(h); len(values) > 0 {
+			s.Annotate(t.keys.HeaderPrefix+strings.ToLower(h), strings.Join(values, ", "))
+		}
 	}
-	s.Annotate("http.uri", request.RequestURI)
 
-	wrapped, statusCode := Wrap(writer)
-	if info.ParentId == nil && info.Sampled {
-		writer.Header().Set(traceIDHeader, fmt.Sprintf("%x", s.Trace().Id()))
-		writer.Header().Set(childIDHeader, fmt.Sprintf("%x", s.Id()))
+	wrapped, observer := wrap(writer)
+	statusCode := observer.StatusCode
+	if (info.ParentId == nil || linked) && info.Sampled {
+		writer.Header().Set(traceIDHeader, monkit.FormatTraceID(s.Trace().Id(), monkit.IDFormatHex))
+		writer.Header().Set(childIDHeader, monkit.FormatTraceID(s.Id(), monkit.IDFormatHex))
+	}
+	ctx = s
+	if hasRemote {
+		ctx = propagation.WithRemote(ctx, remote)
+	}
+	if len(info.Baggage) > 0 {
+		ctx = context.WithValue(ctx, baggageKey, info.Baggage)
 	}
-	t.handler.ServeHTTP(wrapped, request.WithContext(s))
+	ctx = context.WithValue(ctx, routeKey, &routeState{
+		span:     s,
+		params:   t.routeParams,
+		method:   request.Method,
+		rootName: t.routeRootName,
+	})
+	rec, stack := t.serve(wrapped, request.WithContext(ctx))
+	if rec != nil {
+		if !observer.written() {
+			http.Error(wrapped, http.StatusText(http.StatusInternalServerError),
+				http.StatusInternalServerError)
+		}
+		s.Annotate("panic", "true")
+		if t.repanic {
+			s.Annotate(t.keys.ResponseCode, fmt.Sprint(statusCode()))
+			panic(rec)
+		}
+		s.Annotate("panic.value", fmt.Sprint(rec))
+		if stack != "" {
+			s.Annotate("panic.stack", stack)
+		}
+		err = errHandlerPanic
+	}
+
+	s.Annotate(t.keys.ResponseCode, fmt.Sprint(statusCode()))
+}
 
-	s.Annotate("http.responsecode", fmt.Sprint(statusCode()))
+var errHandlerPanic = errors.New("http: handler panicked")
+
+// serve calls the wrapped handler, recovering a panic. http.ErrAbortHandler
+// is not recovered, as it is meant to abort the response silently.
+func (t traceHandler) serve(writer http.ResponseWriter, request *http.Request) (rec interface{}, stack string) {
+	defer func() {
+		rec = recover()
+		if rec == http.ErrAbortHandler {
+			panic(rec)
+		}
+		if rec != nil && t.panicStack {
+			stack = string(debug.Stack())
+		}
+	}()
+	t.handler.ServeHTTP(writer, request)
+	return nil, ""
+}
+
+func requestScheme(r *http.Request) string {
+	if r.URL.Scheme != "" {
+		return r.URL.Scheme
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
 }
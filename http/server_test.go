@@ -4,19 +4,24 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/present"
+	"github.com/spacemonkeygo/monkit/v3/propagation"
 )
 
 type TraceResponse struct {
 	TraceID     string            `json:"trace_id"`
 	SpanID      string            `json:"span_id"`
 	Annotations map[string]string `json:"annotations"`
+	Baggage     map[string]string `json:"baggage"`
 }
 
 func TestTraceHandlerIntegration(t *testing.T) {
@@ -36,9 +41,10 @@ func TestTraceHandlerIntegration(t *testing.T) {
 		}
 
 		response := TraceResponse{
-			TraceID:     fmt.Sprintf("%016x", span.Trace().Id()),
-			SpanID:      fmt.Sprintf("%016x", span.Id()),
+			TraceID:     monkit.FormatTraceID(span.Trace().Id(), monkit.IDFormatHex),
+			SpanID:      monkit.FormatTraceID(span.Id(), monkit.IDFormatHex),
 			Annotations: annotations,
+			Baggage:     BaggageFromCtx(r.Context()),
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -75,6 +81,18 @@ func TestTraceHandlerIntegration(t *testing.T) {
 			t.Errorf("Expected http.uri annotation to be '/test', got '%s'", traceResp.Annotations["http.uri"])
 		}
 
+		if traceResp.Annotations["http.method"] != "GET" {
+			t.Errorf("Expected http.method annotation to be 'GET', got '%s'", traceResp.Annotations["http.method"])
+		}
+
+		if traceResp.Annotations["http.host"] != req.URL.Host {
+			t.Errorf("Expected http.host annotation to be '%s', got '%s'", req.URL.Host, traceResp.Annotations["http.host"])
+		}
+
+		if traceResp.Annotations["http.scheme"] != "http" {
+			t.Errorf("Expected http.scheme annotation to be 'http', got '%s'", traceResp.Annotations["http.scheme"])
+		}
+
 		if traceResp.Annotations["foo"] != "bar" {
 			t.Errorf("Annotation is missing")
 		}
@@ -82,6 +100,10 @@ func TestTraceHandlerIntegration(t *testing.T) {
 		if traceResp.Annotations["forbidden"] != "" {
 			t.Errorf("Annotation should be missing")
 		}
+
+		if traceResp.Baggage["foo"] != "bar" || len(traceResp.Baggage) != 1 {
+			t.Errorf("Unexpected baggage in context: %v", traceResp.Baggage)
+		}
 	})
 
 	t.Run("orphan trace", func(t *testing.T) {
@@ -107,6 +129,18 @@ func TestTraceHandlerIntegration(t *testing.T) {
 			t.Errorf("Expected http.uri annotation to be '/test', got '%s'", traceResp.Annotations["http.uri"])
 		}
 
+		if traceResp.Annotations["http.method"] != "GET" {
+			t.Errorf("Expected http.method annotation to be 'GET', got '%s'", traceResp.Annotations["http.method"])
+		}
+
+		if traceResp.Annotations["http.host"] != req.URL.Host {
+			t.Errorf("Expected http.host annotation to be '%s', got '%s'", req.URL.Host, traceResp.Annotations["http.host"])
+		}
+
+		if traceResp.Annotations["http.scheme"] != "http" {
+			t.Errorf("Expected http.scheme annotation to be 'http', got '%s'", traceResp.Annotations["http.scheme"])
+		}
+
 		if traceResp.Annotations["foo"] != "bar" {
 			t.Errorf("Annotation is missing")
 		}
@@ -209,10 +243,10 @@ func TestTraceHandlerContextPropagation(t *testing.T) {
 		}
 
 		response := map[string]string{
-			"parent_trace_id": fmt.Sprintf("%016x", span.Trace().Id()),
-			"parent_span_id":  fmt.Sprintf("%016x", span.Id()),
-			"child_trace_id":  fmt.Sprintf("%016x", childSpan.Trace().Id()),
-			"child_span_id":   fmt.Sprintf("%016x", childSpan.Id()),
+			"parent_trace_id": monkit.FormatTraceID(span.Trace().Id(), monkit.IDFormatHex),
+			"parent_span_id":  monkit.FormatTraceID(span.Id(), monkit.IDFormatHex),
+			"child_trace_id":  monkit.FormatTraceID(childSpan.Trace().Id(), monkit.IDFormatHex),
+			"child_span_id":   monkit.FormatTraceID(childSpan.Id(), monkit.IDFormatHex),
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -261,3 +295,646 @@ func TestTraceHandlerContextPropagation(t *testing.T) {
 		t.Errorf("Expected trace ID 0000000000000001, got %s", result["parent_trace_id"])
 	}
 }
+
+func TestTraceHandlerWithRootName(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("roots")
+
+	var defaultName, customName string
+	handler := func(name *string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*name = monkit.SpanFromCtx(r.Context()).Trace().RootName()
+		})
+	}
+
+	for _, h := range []http.Handler{
+		TraceHandler(handler(&defaultName), scope),
+		TraceHandlerWithRootName(handler(&customName), scope, MethodAndPath),
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/test?x=1", nil))
+	}
+
+	if defaultName != "roots.traceHandler.ServeHTTP" {
+		t.Errorf("unexpected default root name %q", defaultName)
+	}
+	if customName != "GET /test" {
+		t.Errorf("unexpected root name %q", customName)
+	}
+}
+
+func TestTraceHandlerSkipPaths(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("skips")
+
+	var span *monkit.Span
+	handler := TraceHandlerWithOptions(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span = monkit.SpanFromCtx(r.Context())
+	}), scope, SkipPaths("/healthz", "/debug/"))
+
+	for path, traced := range map[string]bool{
+		"/healthz":       false,
+		"/healthz/extra": true,
+		"/debug/pprof":   false,
+		"/debug":         true,
+		"/test":          true,
+	} {
+		span = nil
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		if (span != nil) != traced {
+			t.Errorf("%s: expected traced=%v, got span %v", path, traced, span)
+		}
+	}
+}
+
+func TestTraceHandlerWithAnnotations(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("static")
+
+	var annotations map[string]string
+	handler := TraceHandlerWithAnnotations(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		annotations = map[string]string{}
+		for _, a := range monkit.SpanFromCtx(r.Context()).Annotations() {
+			annotations[a.Name] = a.Value
+		}
+	}), scope, map[string]string{"service": "api", "team": "core"})
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+
+	if annotations["service"] != "api" || annotations["team"] != "core" {
+		t.Errorf("missing static annotations: %v", annotations)
+	}
+	if annotations["http.uri"] != "/test" {
+		t.Errorf("missing http.uri annotation: %v", annotations)
+	}
+}
+
+func TestTraceHandlerBaggageForms(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("baggage")
+
+	var baggage map[string]string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		baggage = BaggageFromCtx(r.Context())
+	})
+
+	allowed := []string{"foo", "tenant-*"}
+	for _, tc := range []struct {
+		name     string
+		handler  http.Handler
+		expected map[string]string
+	}{
+		{
+			name:     "slice",
+			handler:  TraceHandler(handler, scope, allowed...),
+			expected: map[string]string{"foo": "1", "tenant-id": "2", "tenant-region": "3"},
+		},
+		{
+			name:     "combined options",
+			handler:  TraceHandlerWithOptions(handler, scope, AllowedBaggage(allowed...), AllowedBaggage("other")),
+			expected: map[string]string{"foo": "1", "tenant-id": "2", "tenant-region": "3", "other": "4"},
+		},
+		{
+			name:     "allow all",
+			handler:  TraceHandlerAllowAllBaggage(handler, scope),
+			expected: map[string]string{"foo": "1", "tenant-id": "2", "tenant-region": "3", "other": "4", "tenant": "5"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			baggage = nil
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("traceparent", "00-0000000000000001-00000002-01")
+			req.Header.Set("baggage", "foo=1,tenant-id=2,tenant-region=3,other=4,tenant=5")
+			tc.handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if len(baggage) != len(tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, baggage)
+			}
+			for k, v := range tc.expected {
+				if baggage[k] != v {
+					t.Fatalf("expected %v, got %v", tc.expected, baggage)
+				}
+			}
+		})
+	}
+}
+
+func TestTraceHandlerLinkUpstream(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("links")
+
+	var trace *monkit.Trace
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace = monkit.SpanFromCtx(r.Context()).Trace()
+	})
+
+	// the orphan path has no upstream to link to.
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("tracestate", "sampled=true")
+	TraceHandlerWithOptions(handler, scope, LinkUpstream()).ServeHTTP(httptest.NewRecorder(), req)
+	if _, ok := trace.ParentTrace(); ok {
+		t.Fatal("unexpected parent trace for an orphan trace")
+	}
+
+	req = httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("traceparent", "00-0000000000000001-00000002-01")
+	rec := httptest.NewRecorder()
+	TraceHandlerWithOptions(handler, scope, LinkUpstream()).ServeHTTP(rec, req)
+	if trace.Id() == 1 {
+		t.Fatal("expected a new trace")
+	}
+	if link, ok := trace.ParentTrace(); !ok || link.TraceId != 1 || link.SpanId != 2 {
+		t.Fatalf("unexpected parent trace %v (%v)", link, ok)
+	}
+	if rec.Header().Get(traceIDHeader) != monkit.FormatTraceID(trace.Id(), monkit.IDFormatHex) {
+		t.Fatalf("expected the new trace id in the response, got %q", rec.Header().Get(traceIDHeader))
+	}
+
+	// without the option the upstream trace is continued.
+	TraceHandler(handler, scope).ServeHTTP(httptest.NewRecorder(), req)
+	if _, ok := trace.ParentTrace(); ok || trace.Id() != 1 {
+		t.Fatalf("expected the upstream trace to be continued, got %d", trace.Id())
+	}
+}
+
+func TestTraceHandlerPropagator(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("propagator")
+
+	var span *monkit.Span
+	var remote propagation.Remote
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span = monkit.SpanFromCtx(r.Context())
+		remote, _ = propagation.RemoteFromCtx(r.Context())
+	})
+
+	// a legacy header with decimal "<trace id>/<span id>/<sampled>" values.
+	legacy := propagation.Header{
+		Name: "X-Legacy-Trace",
+		Parse: func(value string) (traceId, spanId int64, flags byte, ok bool) {
+			parts := strings.Split(value, "/")
+			if len(parts) != 3 {
+				return 0, 0, 0, false
+			}
+			traceId, err1 := strconv.ParseInt(parts[0], 10, 64)
+			spanId, err2 := strconv.ParseInt(parts[1], 10, 64)
+			if err1 != nil || err2 != nil {
+				return 0, 0, 0, false
+			}
+			if parts[2] == "1" {
+				flags = monkit.TraceFlagSampled
+			}
+			return traceId, spanId, flags, true
+		},
+	}
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Legacy-Trace", "1234/5678/1")
+	// the W3C headers are not looked at.
+	req.Header.Set("traceparent", "00-0000000000000001-00000002-01")
+	TraceHandlerWithOptions(handler, scope, Propagator(legacy)).ServeHTTP(httptest.NewRecorder(), req)
+	if span.Trace().Id() != 1234 {
+		t.Fatalf("expected the trace id of the custom header, got %d", span.Trace().Id())
+	}
+	if parentId, ok := span.ParentId(); !ok || parentId != 5678 {
+		t.Fatalf("expected the parent of the custom header, got %d", parentId)
+	}
+	if sampled, _ := span.Trace().Get(present.SampledKey).(bool); !sampled {
+		t.Fatal("expected the trace to be sampled")
+	}
+	if remote.TraceId == nil || *remote.TraceId != 1234 {
+		t.Fatalf("expected the extracted context in the request context, got %+v", remote)
+	}
+
+	// the default format is the one of traceparent.
+	req = httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Trace", "00-00000000000000000000000000000003-0000000000000004-01")
+	TraceHandlerWithOptions(handler, scope, Propagator(propagation.Header{Name: "X-Trace"})).
+		ServeHTTP(httptest.NewRecorder(), req)
+	if parentId, _ := span.ParentId(); span.Trace().Id() != 3 || parentId != 4 {
+		t.Fatalf("unexpected trace %d and parent %d", span.Trace().Id(), parentId)
+	}
+}
+
+func TestTraceHandlerAnnotationKeys(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("keys")
+
+	var span *monkit.Span
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span = monkit.SpanFromCtx(r.Context())
+	})
+	annotations := func() map[string]string {
+		rv := map[string]string{}
+		for _, a := range span.Annotations() {
+			rv[a.Name] = a.Value
+		}
+		return rv
+	}
+
+	TraceHandler(handler, scope).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test?x=1", nil))
+	if got := annotations(); got["http.uri"] != "/test?x=1" || got["http.responsecode"] != "200" {
+		t.Fatalf("unexpected default annotations %v", got)
+	}
+
+	TraceHandlerWithOptions(handler, scope, URIAnnotationKey("http.target")).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test?x=1", nil))
+	got := annotations()
+	if _, ok := got["http.uri"]; ok || got["http.target"] != "/test?x=1" {
+		t.Fatalf("expected only the custom uri key, got %v", got)
+	}
+	if got["http.method"] != "GET" || got["http.responsecode"] != "200" {
+		t.Fatalf("expected the other keys to keep their defaults, got %v", got)
+	}
+
+	TraceHandlerWithOptions(handler, scope, WithAnnotationKeys(OTelAnnotationKeys)).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test?x=1", nil))
+	got = annotations()
+	if got["http.target"] != "/test?x=1" || got["http.request.method"] != "GET" ||
+		got["server.address"] != "example.com" || got["url.scheme"] != "http" ||
+		got["http.response.status_code"] != "200" {
+		t.Fatalf("unexpected otel annotations %v", got)
+	}
+}
+
+func TestTraceHandlerPanic(t *testing.T) {
+	r := monkit.NewRegistry()
+	scope := r.ScopeNamed("panics")
+
+	var span *monkit.Span
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		span = monkit.SpanFromCtx(req.Context())
+		panic("boom")
+	})
+	annotations := func() map[string]string {
+		rv := map[string]string{}
+		for _, a := range span.Annotations() {
+			rv[a.Name] = a.Value
+		}
+		return rv
+	}
+	errors := func() (n int64) {
+		scope.Funcs(func(f *monkit.Func) {
+			for _, count := range f.Errors() {
+				n += count
+			}
+		})
+		return n
+	}
+	panics := func() (n int64) {
+		scope.Funcs(func(f *monkit.Func) { n += f.Panics() })
+		return n
+	}
+
+	rec := httptest.NewRecorder()
+	TraceHandlerWithOptions(handler, scope, PanicStack()).ServeHTTP(rec, httptest.NewRequest("GET", "/test", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", rec.Code)
+	}
+	got := annotations()
+	if got["panic"] != "true" || got["panic.value"] != "boom" || got["panic.stack"] == "" ||
+		got["http.responsecode"] != "500" {
+		t.Fatalf("unexpected annotations %v", got)
+	}
+	r.AllSpans(func(s *monkit.Span) { t.Fatalf("span %v is still running", s) })
+	if errors() != 1 || panics() != 0 {
+		t.Fatalf("expected one failed call, got %d errors and %d panics", errors(), panics())
+	}
+
+	rec = httptest.NewRecorder()
+	func() {
+		defer func() {
+			if rec := recover(); rec != "boom" {
+				t.Fatalf("expected the panic to be repeated, got %v", rec)
+			}
+		}()
+		TraceHandlerWithOptions(handler, scope, Repanic()).ServeHTTP(rec, httptest.NewRequest("GET", "/test", nil))
+	}()
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", rec.Code)
+	}
+	if got := annotations(); got["panic"] != "true" || got["panic.value"] != "boom" {
+		t.Fatalf("unexpected annotations %v", got)
+	}
+	r.AllSpans(func(s *monkit.Span) { t.Fatalf("span %v is still running", s) })
+	if panics() != 1 {
+		t.Fatalf("expected one panicked call, got %d", panics())
+	}
+}
+
+func TestTraceHandlerFlags(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("flags")
+
+	var outgoing http.Header
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outgoing = http.Header{}
+		TraceInfoFromSpan(monkit.SpanFromCtx(r.Context())).SetHeader(outgoing)
+	})
+
+	for _, tc := range []struct {
+		flags    string
+		expected byte
+		outgoing string
+	}{
+		{flags: "01", expected: 0x01, outgoing: "01"},
+		{flags: "03", expected: 0x03, outgoing: "03"},
+		{flags: "02", expected: 0x02, outgoing: ""},
+	} {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("traceparent", "00-0000000000000001-00000002-"+tc.flags)
+		info := TraceInfoFromHeader(req.Header)
+		if info.Flags != tc.expected {
+			t.Fatalf("%s: expected parsed flags %#x, got %#x", tc.flags, tc.expected, info.Flags)
+		}
+
+		TraceHandler(handler, scope).ServeHTTP(httptest.NewRecorder(), req)
+		parent := outgoing.Get("traceparent")
+		if tc.outgoing == "" {
+			// unsampled traces are not propagated.
+			if parent != "" {
+				t.Fatalf("%s: unexpected traceparent %q", tc.flags, parent)
+			}
+			continue
+		}
+		if !strings.HasSuffix(parent, "-"+tc.outgoing) {
+			t.Fatalf("%s: expected flags %s in %q", tc.flags, tc.outgoing, parent)
+		}
+	}
+}
+
+func TestTraceHandlerFlagsKeepWatcherSampling(t *testing.T) {
+	registry := monkit.NewRegistry()
+	registry.ObserveTraces(func(trace *monkit.Trace) { trace.Set("sampled", true) })
+
+	var outgoing http.Header
+	var sampled bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span := monkit.SpanFromCtx(r.Context())
+		sampled, _ = span.Trace().Get("sampled").(bool)
+		outgoing = http.Header{}
+		TraceInfoFromSpan(span).SetHeader(outgoing)
+	})
+
+	TraceHandler(handler, registry.ScopeNamed("watched")).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+	if !sampled {
+		t.Fatal("expected the watcher's sampling to be kept")
+	}
+	if outgoing.Get("traceparent") == "" {
+		t.Fatal("expected the sampled trace to be propagated")
+	}
+}
+
+func TestTraceHandlerRouteParams(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("routes")
+
+	var span *monkit.Span
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a child span started before routing must not receive the route.
+		ctx := r.Context()
+		defer scope.TaskNamed("router")(&ctx)(nil)
+		span = monkit.SpanFromCtx(r.Context())
+		SetRoute(ctx, "/orgs/{org}/users/{id}", map[string]string{"org": "acme", "id": "42", "token": "x"})
+	})
+	annotations := func() map[string]string {
+		rv := map[string]string{}
+		for _, a := range span.Annotations() {
+			rv[a.Name] = a.Value
+		}
+		return rv
+	}
+
+	TraceHandler(handler, scope).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/orgs/acme/users/42", nil))
+	got := annotations()
+	if got["http.route"] != "/orgs/{org}/users/{id}" {
+		t.Fatalf("expected the route annotation, got %v", got)
+	}
+	for name := range got {
+		if strings.HasPrefix(name, "http.route.param.") {
+			t.Fatalf("expected no params without RouteParams, got %v", got)
+		}
+	}
+
+	TraceHandlerWithOptions(handler, scope, RouteParams(func(route string, params map[string]string) map[string]string {
+		return map[string]string{"org": params["org"], "id": HashRouteParam(params["id"])}
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/orgs/acme/users/42", nil))
+	got = annotations()
+	if got["http.route.param.org"] != "acme" {
+		t.Fatalf("expected org to be passed through, got %v", got)
+	}
+	if id := got["http.route.param.id"]; id == "" || id == "42" || id != HashRouteParam("42") {
+		t.Fatalf("expected id to be hashed, got %v", got)
+	}
+	if _, ok := got["http.route.param.token"]; ok {
+		t.Fatalf("expected token to be omitted, got %v", got)
+	}
+
+	// outside of TraceHandler SetRoute is a no-op.
+	SetRoute(context.Background(), "/", nil)
+}
+
+func TestTraceHandlerRouteRootName(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("routes")
+
+	var trace *monkit.Trace
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace = monkit.SpanFromCtx(r.Context()).Trace()
+		SetRoute(r.Context(), "/users/{id}", nil)
+	})
+
+	TraceHandler(handler, scope).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/42", nil))
+	if trace.RootName() != "routes.traceHandler.ServeHTTP" {
+		t.Fatalf("expected the default root name, got %q", trace.RootName())
+	}
+
+	TraceHandlerWithOptions(handler, scope, RootName(MethodAndPath), RouteRootName()).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/users/42", nil))
+	if trace.RootName() != "POST /users/{id}" {
+		t.Fatalf("expected the route as root name, got %q", trace.RootName())
+	}
+}
+
+type headerClient struct{ header http.Header }
+
+func (c *headerClient) Do(req *http.Request) (*http.Response, error) {
+	c.header = req.Header.Clone()
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestTraceHandlerSampledBaggageAnnotations(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("baggage")
+
+	var span *monkit.Span
+	client := &headerClient{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span = monkit.SpanFromCtx(r.Context())
+		downstream := httptest.NewRequest("GET", "http://downstream/", nil)
+		_, _ = TraceRequest(r.Context(), scope, client, downstream)
+	})
+	traced := TraceHandlerWithOptions(handler, scope, AllowedBaggage("tenant"), SampledBaggageAnnotations())
+
+	for _, tc := range []struct {
+		flags     string
+		annotated bool
+	}{
+		{flags: "01", annotated: true},
+		{flags: "00", annotated: false},
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("traceparent", "00-0000000000000001-00000002-"+tc.flags)
+		req.Header.Set("baggage", "tenant=acme")
+		traced.ServeHTTP(httptest.NewRecorder(), req)
+
+		annotated := false
+		for _, a := range span.Annotations() {
+			if a.Name == "tenant" && a.Value == "acme" {
+				annotated = true
+			}
+		}
+		if annotated != tc.annotated {
+			t.Fatalf("flags %s: expected annotated=%v, got %v", tc.flags, tc.annotated, span.Annotations())
+		}
+		if got := client.header.Get("baggage"); got != "tenant=acme" {
+			t.Fatalf("flags %s: expected baggage to be forwarded, got %q", tc.flags, got)
+		}
+	}
+}
+
+func TestTraceHandlerQueryParam(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("query")
+
+	var span *monkit.Span
+	handler := TraceHandlerWithOptions(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span = monkit.SpanFromCtx(r.Context())
+	}), scope, TraceQueryParam("traceparent"))
+
+	// the context is only supplied in the query.
+	req := httptest.NewRequest("GET", "/hook?traceparent=00-0000000000000001-00000002-01", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if span.Trace().Id() != 1 {
+		t.Fatalf("expected the trace id of the query, got %d", span.Trace().Id())
+	}
+	if parentId, ok := span.ParentId(); !ok || parentId != 2 {
+		t.Fatalf("expected the parent of the query, got %d", parentId)
+	}
+	if sampled, _ := span.Trace().Get(present.SampledKey).(bool); !sampled {
+		t.Fatal("expected the trace to be sampled")
+	}
+
+	// the header takes precedence.
+	req = httptest.NewRequest("GET", "/hook?traceparent=00-0000000000000001-00000002-01", nil)
+	req.Header.Set("traceparent", "00-0000000000000003-00000004-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if span.Trace().Id() != 3 {
+		t.Fatalf("expected the trace id of the header, got %d", span.Trace().Id())
+	}
+
+	// without the option the query is ignored.
+	req = httptest.NewRequest("GET", "/hook?traceparent=00-0000000000000001-00000002-01", nil)
+	TraceHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span = monkit.SpanFromCtx(r.Context())
+	}), scope).ServeHTTP(httptest.NewRecorder(), req)
+	if span.Trace().Id() == 1 {
+		t.Fatal("expected a new trace")
+	}
+}
+
+func TestTraceHandlerOnFinish(t *testing.T) {
+	registry := monkit.NewRegistry()
+	scope := registry.ScopeNamed("onfinish")
+
+	var finished []*monkit.Span
+	var codes []string
+	running := 0
+	handler := TraceHandlerWithOptions(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		}
+	}), scope, OnFinish(func(s *monkit.Span) {
+		finished = append(finished, s)
+		registry.RootSpans(func(*monkit.Span) { running++ })
+		for _, a := range s.Annotations() {
+			if a.Name == "http.responsecode" {
+				codes = append(codes, a.Value)
+			}
+		}
+	}))
+
+	for _, path := range []string{"/ok", "/missing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	if len(finished) != 2 {
+		t.Fatalf("expected 2 callbacks, got %d", len(finished))
+	}
+	if finished[0] == finished[1] {
+		t.Fatal("expected a span per request")
+	}
+	if len(codes) != 2 || codes[0] != "200" || codes[1] != "404" {
+		t.Fatalf("unexpected response codes %v", codes)
+	}
+	if running != 0 {
+		t.Fatalf("expected the spans to be finished, %d were running", running)
+	}
+}
+
+func TestTraceHandlerBaggageInheritance(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("inherit")
+
+	var baggage map[string]string
+	handler := TraceHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		defer scope.TaskNamed("service")(&ctx)(nil)
+		defer scope.TaskNamed("store")(&ctx)(nil)
+		baggage = monkit.Baggage(ctx)
+	}), scope, "tenant")
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("traceparent", "00-0000000000000001-00000002-01")
+	req.Header.Set("baggage", "tenant=acme,other=ignored")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if len(baggage) != 1 || baggage["tenant"] != "acme" {
+		t.Fatalf("expected the allowed baggage in nested spans, got %v", baggage)
+	}
+}
+
+func TestTraceHandlerWithHeaders(t *testing.T) {
+	scope := monkit.NewRegistry().ScopeNamed("headers")
+
+	var span *monkit.Span
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span = monkit.SpanFromCtx(r.Context())
+	})
+	annotations := func() map[string]string {
+		rv := map[string]string{}
+		for _, a := range span.Annotations() {
+			rv[a.Name] = a.Value
+		}
+		return rv
+	}
+	request := func() *http.Request {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("User-Agent", "curl/8.0")
+		req.Header.Add("X-Request-Id", "a")
+		req.Header.Add("X-Request-Id", "b")
+		req.Header.Set("Authorization", "Bearer secret")
+		return req
+	}
+
+	TraceHandlerWithHeaders(handler, scope, "user-agent", "X-Request-Id", "X-Missing").
+		ServeHTTP(httptest.NewRecorder(), request())
+	got := annotations()
+	if got["http.header.user-agent"] != "curl/8.0" || got["http.header.x-request-id"] != "a, b" {
+		t.Fatalf("expected the allowed headers, got %v", got)
+	}
+	for name, value := range got {
+		if strings.Contains(value, "secret") || name == "http.header.x-missing" {
+			t.Fatalf("unexpected annotation %q=%q", name, value)
+		}
+	}
+
+	TraceHandlerWithOptions(handler, scope, RecordHeaders("User-Agent"),
+		WithAnnotationKeys(OTelAnnotationKeys)).ServeHTTP(httptest.NewRecorder(), request())
+	if got := annotations(); got["http.request.header.user-agent"] != "curl/8.0" {
+		t.Fatalf("expected the otel header annotation, got %v", got)
+	}
+
+	TraceHandler(handler, scope).ServeHTTP(httptest.NewRecorder(), request())
+	for name := range annotations() {
+		if strings.HasPrefix(name, "http.header.") {
+			t.Fatalf("unexpected header annotation %q", name)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// StatsSSEHandler returns an http.Handler that streams the stats of r as
+// Server-Sent Events, one `data: {json}` frame with r.SnapshotAll every
+// interval, starting right away, for live dashboards that only need an
+// EventSource. It keeps the response open until the client goes away, that
+// is until the request's context is done. An interval <= 0 means every
+// second.
+func StatsSSEHandler(r *monkit.Registry, interval time.Duration) http.Handler {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			data, err := json.Marshal(r.SnapshotAll())
+			if err != nil {
+				return
+			}
+			frame := make([]byte, 0, len(data)+8)
+			frame = append(frame, "data: "...)
+			frame = append(append(frame, data...), "\n\n"...)
+			if _, err := w.Write(frame); err != nil {
+				return
+			}
+			flusher.Flush()
+
+			select {
+			case <-req.Context().Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
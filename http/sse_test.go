@@ -0,0 +1,78 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestStatsSSEHandler(t *testing.T) {
+	r := monkit.NewRegistry()
+	r.ScopeNamed("live").Counter("events").Inc(3)
+
+	done := make(chan struct{})
+	sse := StatsSSEHandler(r, 10*time.Millisecond)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		defer close(done)
+		sse.ServeHTTP(w, req)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	for frame := 1; frame <= 2; frame++ {
+		var snap monkit.RegistrySnapshot
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data := strings.TrimPrefix(line, "data: "); data != line {
+				if err := json.Unmarshal([]byte(data), &snap); err != nil {
+					t.Fatalf("invalid frame %q: %v", line, err)
+				}
+				break
+			}
+		}
+		found := false
+		for _, series := range snap.Series {
+			if series.Measurement == "events" && series.Tags["scope"] == "live" {
+				found = series.Fields["value"] == 3
+			}
+		}
+		if !found {
+			t.Fatalf("frame %d is missing the counter: %+v", frame, snap)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("handler didn't stop after the client went away")
+	}
+}
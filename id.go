@@ -16,8 +16,14 @@ package monkit
 
 import (
 	crand "crypto/rand"
+	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
 	"math/rand"
+	"strconv"
+	"strings"
 	"sync/atomic"
 
 	"github.com/spacemonkeygo/monkit/v3/monotime"
@@ -46,3 +52,138 @@ func NewId() int64 {
 	id := atomic.AddUint64(&idCounter, inc)
 	return int64(id >> 1)
 }
+
+// IDFormat selects the string representation FormatTraceID uses for an id.
+type IDFormat int
+
+const (
+	// IDFormatHex formats ids as 16 zero-padded lowercase hex digits, treating
+	// the id as an unsigned 64 bit number. This matches the W3C trace context
+	// headers.
+	IDFormatHex IDFormat = iota
+
+	// IDFormatDecimal formats ids as signed base 10 numbers.
+	IDFormatDecimal
+
+	// IDFormatBase64 formats the 8 big-endian bytes of the id using standard,
+	// padded base64.
+	IDFormatBase64
+)
+
+// FormatTraceID formats a trace or span id according to format. Ids are
+// signed, so negative ids in IDFormatHex and IDFormatBase64 are rendered from
+// their two's complement representation, which is what other systems
+// expecting unsigned 64 bit ids will parse back.
+func FormatTraceID(id int64, format IDFormat) string {
+	switch format {
+	case IDFormatDecimal:
+		return strconv.FormatInt(id, 10)
+	case IDFormatBase64:
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(id))
+		return base64.StdEncoding.EncodeToString(buf[:])
+	default:
+		s := strconv.FormatUint(uint64(id), 16)
+		if len(s) < 16 {
+			s = strings.Repeat("0", 16-len(s)) + s
+		}
+		return s
+	}
+}
+
+// ID is an opaque trace or span identifier of up to 128 bits. The int64 ids
+// used throughout monkit convert to and from IDs with IDFromInt64 and
+// ID.Int64; such IDs are "narrow" and only use the low 64 bits. IDs are
+// comparable with ==.
+type ID struct {
+	hi, lo uint64
+}
+
+// IDFromInt64 returns the ID equivalent of a monkit int64 id.
+func IDFromInt64(id int64) ID { return ID{lo: uint64(id)} }
+
+// IDFromParts returns the ID with the given high and low 64 bits.
+func IDFromParts(hi, lo uint64) ID { return ID{hi: hi, lo: lo} }
+
+// NewID128 returns a random ID using all 128 bits. Unlike NewId, which hands
+// out 63 bits from a counter, both halves are read from crypto/rand, so
+// neither can be derived from the other.
+func NewID128() ID {
+	var buf [16]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		rng := rand.New(rand.NewSource(monotime.Now().UnixNano()))
+		binary.BigEndian.PutUint64(buf[0:8], rng.Uint64())
+		binary.BigEndian.PutUint64(buf[8:16], rng.Uint64())
+	}
+	id := ID{hi: binary.BigEndian.Uint64(buf[0:8]), lo: binary.BigEndian.Uint64(buf[8:16])}
+	if id.IsZero() {
+		// the zero ID is not a valid id.
+		id.lo = 1
+	}
+	return id
+}
+
+// Parts returns the high and low 64 bits of the ID.
+func (id ID) Parts() (hi, lo uint64) { return id.hi, id.lo }
+
+// IsZero returns whether the ID is the zero ID, which is never a valid id.
+func (id ID) IsZero() bool { return id.hi == 0 && id.lo == 0 }
+
+// Narrow returns whether the ID fits in 64 bits.
+func (id ID) Narrow() bool { return id.hi == 0 }
+
+// Int64 narrows the ID to a monkit int64 id, returning its low 64 bits and
+// whether that was lossless.
+func (id ID) Int64() (rv int64, ok bool) { return int64(id.lo), id.hi == 0 }
+
+// Bytes returns the 16 big-endian bytes of the ID.
+func (id ID) Bytes() (rv [16]byte) {
+	binary.BigEndian.PutUint64(rv[:8], id.hi)
+	binary.BigEndian.PutUint64(rv[8:], id.lo)
+	return rv
+}
+
+// String returns the ID formatted with IDFormatHex.
+func (id ID) String() string { return FormatID(id, IDFormatHex) }
+
+// FormatID is like FormatTraceID, but for IDs. Narrow IDs are formatted
+// exactly like FormatTraceID formats the equivalent int64, while wide IDs use
+// twice the hex digits or bytes, and are formatted as unsigned 128 bit numbers
+// in IDFormatDecimal.
+func FormatID(id ID, format IDFormat) string {
+	if id.Narrow() {
+		return FormatTraceID(int64(id.lo), format)
+	}
+	b := id.Bytes()
+	switch format {
+	case IDFormatDecimal:
+		return new(big.Int).SetBytes(b[:]).String()
+	case IDFormatBase64:
+		return base64.StdEncoding.EncodeToString(b[:])
+	default:
+		return hex.EncodeToString(b[:])
+	}
+}
+
+// ParseID parses a hex ID of up to 32 digits, such as the ones FormatID
+// produces with IDFormatHex.
+func ParseID(s string) (ID, error) {
+	if len(s) == 0 || len(s) > 32 {
+		return ID{}, fmt.Errorf("invalid id %q: expected 1 to 32 hex digits", s)
+	}
+	var id ID
+	if len(s) > 16 {
+		hi, err := strconv.ParseUint(s[:len(s)-16], 16, 64)
+		if err != nil {
+			return ID{}, fmt.Errorf("invalid id %q: %w", s, err)
+		}
+		id.hi = hi
+		s = s[len(s)-16:]
+	}
+	lo, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return ID{}, fmt.Errorf("invalid id %q: %w", s, err)
+	}
+	id.lo = lo
+	return id, nil
+}
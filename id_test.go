@@ -0,0 +1,97 @@
+package monkit
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatTraceID(t *testing.T) {
+	for _, test := range []struct {
+		id     int64
+		format IDFormat
+		out    string
+	}{
+		{1, IDFormatHex, "0000000000000001"},
+		{0x1234abcd, IDFormatHex, "000000001234abcd"},
+		{-1, IDFormatHex, "ffffffffffffffff"},
+		{math.MinInt64, IDFormatHex, "8000000000000000"},
+		{1, IDFormatDecimal, "1"},
+		{-42, IDFormatDecimal, "-42"},
+		{math.MaxInt64, IDFormatDecimal, "9223372036854775807"},
+		{1, IDFormatBase64, "AAAAAAAAAAE="},
+		{-1, IDFormatBase64, "//////////8="},
+		{0, IDFormatBase64, "AAAAAAAAAAA="},
+	} {
+		if got := FormatTraceID(test.id, test.format); got != test.out {
+			t.Errorf("FormatTraceID(%d, %d): got %q, expected %q", test.id, test.format, got, test.out)
+		}
+	}
+}
+
+func TestID(t *testing.T) {
+	narrow := IDFromInt64(-1)
+	if narrow != IDFromParts(0, math.MaxUint64) || narrow == IDFromInt64(1) {
+		t.Fatal("unexpected equality")
+	}
+	if id, ok := narrow.Int64(); !ok || id != -1 {
+		t.Fatalf("unexpected narrowing %d %v", id, ok)
+	}
+	if FormatID(narrow, IDFormatHex) != FormatTraceID(-1, IDFormatHex) {
+		t.Fatalf("unexpected narrow format %q", FormatID(narrow, IDFormatHex))
+	}
+
+	wide := IDFromParts(1, 2)
+	if wide.Narrow() || wide.IsZero() || !(ID{}).IsZero() {
+		t.Fatal("unexpected width")
+	}
+	if id, ok := wide.Int64(); ok || id != 2 {
+		t.Fatalf("unexpected narrowing %d %v", id, ok)
+	}
+	for format, expected := range map[IDFormat]string{
+		IDFormatHex:     "00000000000000010000000000000002",
+		IDFormatDecimal: "18446744073709551618",
+		IDFormatBase64:  "AAAAAAAAAAEAAAAAAAAAAg==",
+	} {
+		if got := FormatID(wide, format); got != expected {
+			t.Errorf("FormatID(%d): got %q, expected %q", format, got, expected)
+		}
+	}
+
+	for _, id := range []ID{narrow, wide, NewID128(), IDFromInt64(1)} {
+		parsed, err := ParseID(id.String())
+		if err != nil || parsed != id {
+			t.Fatalf("round trip of %v: got %v, %v", id, parsed, err)
+		}
+	}
+	for _, invalid := range []string{"", "xyz", "000000000000000000000000000000001"} {
+		if _, err := ParseID(invalid); err == nil {
+			t.Fatalf("expected %q to be invalid", invalid)
+		}
+	}
+
+	trace := NewTraceWithID(wide)
+	if trace.Id() != 2 || trace.FullId() != wide {
+		t.Fatalf("unexpected trace ids %d %v", trace.Id(), trace.FullId())
+	}
+	if NewTrace(5).FullId() != IDFromInt64(5) {
+		t.Fatal("unexpected narrow trace id")
+	}
+}
+
+func TestNewID128(t *testing.T) {
+	var hiTop, loTop bool
+	seen := map[ID]bool{}
+	for i := 0; i < 64; i++ {
+		id := NewID128()
+		hi, lo := id.Parts()
+		hiTop = hiTop || hi>>63 == 1
+		loTop = loTop || lo>>63 == 1
+		if id.IsZero() || seen[id] {
+			t.Fatalf("unexpected id %v", id)
+		}
+		seen[id] = true
+	}
+	if !hiTop || !loTop {
+		t.Fatal("expected both halves to use all 64 bits")
+	}
+}
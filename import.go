@@ -0,0 +1,110 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+type importedRegistry struct {
+	registry *Registry
+	prefix   string
+}
+
+// Import makes the series of other, such as the Registry of an embedded
+// library, part of the Stats of r, so that they are exported along with r's
+// own. The series are read from other on every call to Stats, so they stay
+// live, and other's transformers apply to them before r's. A non-empty
+// prefix is prepended to the measurement names of other's series as is, so
+// it should include a separator, like "lib.".
+//
+// Series whose key and field collide with a series of r itself, or of a
+// Registry imported earlier, are skipped, so the first one wins. Registries
+// are visited at most once per call to Stats, which makes cycles of imports
+// harmless. Import returns a function that removes other again.
+func (r *Registry) Import(other *Registry, prefix string) (cancel func()) {
+	imp := &importedRegistry{registry: other, prefix: prefix}
+	r.importMtx.Lock()
+	r.imports = append(r.imports, imp)
+	r.importMtx.Unlock()
+	return func() {
+		r.importMtx.Lock()
+		defer r.importMtx.Unlock()
+		for i, existing := range r.imports {
+			if existing == imp {
+				r.imports = append(r.imports[:i:i], r.imports[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (r *Registry) importedRegistries() []*importedRegistry {
+	r.importMtx.Lock()
+	defer r.importMtx.Unlock()
+	return r.imports
+}
+
+// stats implements Stats, visiting the Registries r imports that were not
+// visited yet.
+func (r *Registry) stats(cb func(key SeriesKey, field string, val float64),
+	visited map[*registryInternal]bool) {
+	r.walk(cb, visited, func(s *Scope, cb func(key SeriesKey, field string, val float64)) {
+		s.Stats(cb)
+	})
+}
+
+// walk calls fn with every Scope of r and of the Registries r imports that
+// were not visited yet, along with a callback that passes the Scope's series
+// on to cb the way Stats does: through the transformers, the global tags and
+// the import prefixes, and skipping series that collide.
+func (r *Registry) walk(cb func(key SeriesKey, field string, val float64),
+	visited map[*registryInternal]bool,
+	fn func(s *Scope, cb func(key SeriesKey, field string, val float64))) {
+	for _, t := range r.transformers {
+		cb = t.Transform(cb)
+	}
+	cb = r.withGlobalTags(cb)
+
+	imports := r.importedRegistries()
+	if len(imports) == 0 {
+		r.Scopes(func(s *Scope) { fn(s, cb) })
+		return
+	}
+
+	if visited == nil {
+		visited = map[*registryInternal]bool{}
+	}
+	visited[r.registryInternal] = true
+
+	seen := map[string]bool{}
+	dedup := func(key SeriesKey, field string, val float64) {
+		id := key.WithField(field)
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		cb(key, field, val)
+	}
+	r.Scopes(func(s *Scope) { fn(s, dedup) })
+	for _, imp := range imports {
+		if visited[imp.registry.registryInternal] {
+			continue
+		}
+		prefix := imp.prefix
+		imp.registry.walk(func(key SeriesKey, field string, val float64) {
+			if prefix != "" {
+				key.Measurement = prefix + key.Measurement
+			}
+			dedup(key, field, val)
+		}, visited, fn)
+	}
+}
@@ -0,0 +1,50 @@
+package monkit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	main, lib := NewRegistry(), NewRegistry()
+	main.ScopeNamed("app").Counter("requests").Inc(1)
+	lib.ScopeNamed("lib").Counter("queries").Inc(2)
+	// collides with main's own series without a prefix.
+	lib.ScopeNamed("app").Counter("requests").Inc(100)
+
+	cancel := main.Import(lib, "")
+	stats := Collect(main)
+	if stats["requests,scope=app value"] != 1 {
+		t.Fatalf("expected the own series to win the collision, got %v", stats)
+	}
+	if stats["queries,scope=lib value"] != 2 {
+		t.Fatalf("expected the imported series, got %v", stats)
+	}
+
+	// values stay live.
+	lib.ScopeNamed("lib").Counter("queries").Inc(1)
+	if stats := Collect(main); stats["queries,scope=lib value"] != 3 {
+		t.Fatalf("expected the current value, got %v", stats)
+	}
+	cancel()
+	if _, ok := Collect(main)["queries,scope=lib value"]; ok {
+		t.Fatal("expected only the own series after cancel")
+	}
+
+	main.Import(lib, "lib.")
+	stats = Collect(main)
+	if stats["lib.requests,scope=app value"] != 100 || stats["lib.queries,scope=lib value"] != 3 {
+		t.Fatalf("expected the prefixed series, got %v", stats)
+	}
+
+	// cycles don't recurse forever.
+	lib.Import(main, "main.")
+	for key := range Collect(main) {
+		if strings.HasPrefix(key, "lib.main.") {
+			t.Fatalf("unexpected series %q", key)
+		}
+	}
+	if stats := Collect(lib); stats["main.requests,scope=app value"] != 1 {
+		t.Fatalf("expected main's series in lib, got %v", stats)
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+/*
+Package jaeger exports finished monkit traces to a Jaeger collector using
+Jaeger's Thrift over HTTP protocol.
+
+Only Spans of sampled traces (see present.SampledKey) are exported. Expected
+usage is like:
+
+	exporter := jaeger.NewExporter("http://jaeger:14268/api/traces",
+		jaeger.Options{ServiceName: "myservice"})
+	defer exporter.Register(monkit.Default)()
+	go exporter.Run(ctx)
+*/
+package jaeger
@@ -0,0 +1,290 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package jaeger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/collect"
+	"github.com/spacemonkeygo/monkit/v3/present"
+)
+
+// Options configures an Exporter. Every field is optional.
+type Options struct {
+	// ServiceName is the Jaeger process service name. Defaults to "monkit".
+	ServiceName string
+	// Tags are added to the Jaeger process.
+	Tags map[string]string
+
+	// BatchSize is the maximum number of spans sent per request. Defaults to
+	// 100.
+	BatchSize int
+	// FlushInterval is how long spans wait for a batch to fill up before
+	// being sent anyway. Defaults to one second.
+	FlushInterval time.Duration
+	// QueueSize is how many finished spans can wait to be sent before new
+	// ones are dropped. Defaults to 1000.
+	QueueSize int
+
+	// MaxRetries is how many times a failed request is retried. Defaults to 3.
+	MaxRetries int
+	// Backoff is how long to wait before the first retry. It doubles with
+	// every retry. Defaults to 100 milliseconds.
+	Backoff time.Duration
+
+	// Client sends the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Exporter sends Spans of sampled traces to a Jaeger collector. It
+// implements monkit.SpanObserver, see Register. The metadata of a Trace, see
+// monkit.Trace.SetMetadata, is added to the tags of each of its Spans.
+type Exporter struct {
+	// sync/atomic things
+	dropped int64
+	failed  int64
+
+	endpoint string
+	opts     Options
+	queue    chan *span
+	flushes  chan chan error
+}
+
+// NewExporter creates an Exporter posting to the collector's HTTP endpoint,
+// usually something like http://jaeger:14268/api/traces.
+func NewExporter(endpoint string, opts Options) *Exporter {
+	if opts.ServiceName == "" {
+		opts.ServiceName = "monkit"
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	} else if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = 100 * time.Millisecond
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	return &Exporter{
+		endpoint: endpoint,
+		opts:     opts,
+		queue:    make(chan *span, opts.QueueSize),
+		flushes:  make(chan chan error),
+	}
+}
+
+// Register starts observing all traces on r, present and future, until cancel
+// is called. It also registers the Exporter with r.Flush.
+func (e *Exporter) Register(r *monkit.Registry) (cancel func()) {
+	cancelObserve := collect.ObserveAllTraces(r, e)
+	cancelFlush := r.RegisterFlusher(e)
+	return func() {
+		cancelFlush()
+		cancelObserve()
+	}
+}
+
+// Flush implements monkit.Flusher. It asks Run to send all queued spans
+// right away and waits until they are sent or ctx is done. Flush only
+// works while Run is running.
+func (e *Exporter) Flush(ctx context.Context) error {
+	result := make(chan error, 1)
+	select {
+	case e.flushes <- result:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Start implements monkit.SpanObserver.
+func (e *Exporter) Start(s *monkit.Span) {}
+
+// Finish implements monkit.SpanObserver. It queues the Span to be sent if its
+// trace is sampled.
+func (e *Exporter) Finish(s *monkit.Span, err error, panicked bool, finish time.Time) {
+	if sampled, _ := s.Trace().Get(present.SampledKey).(bool); !sampled {
+		return
+	}
+	select {
+	case e.queue <- convertSpan(s, err, panicked, finish):
+	default:
+		atomic.AddInt64(&e.dropped, 1)
+	}
+}
+
+// Dropped returns how many spans were dropped because the queue was full.
+func (e *Exporter) Dropped() int64 { return atomic.LoadInt64(&e.dropped) }
+
+// Failed returns how many spans were lost because their batch could not be
+// sent, even after retrying.
+func (e *Exporter) Failed() int64 { return atomic.LoadInt64(&e.failed) }
+
+// Run sends queued spans to the collector until ctx is canceled, at which
+// point it makes a final attempt to send whatever is still queued.
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.opts.FlushInterval)
+	defer ticker.Stop()
+
+	pending := make([]*span, 0, e.opts.BatchSize)
+	flush := func(ctx context.Context) (err error) {
+		if len(pending) == 0 {
+			return nil
+		}
+		if err = e.send(ctx, pending); err != nil {
+			atomic.AddInt64(&e.failed, int64(len(pending)))
+		}
+		pending = make([]*span, 0, e.opts.BatchSize)
+		return err
+	}
+	drain := func(ctx context.Context) (err error) {
+		for len(e.queue) > 0 {
+			pending = append(pending, <-e.queue)
+			if len(pending) >= e.opts.BatchSize {
+				if ferr := flush(ctx); ferr != nil && err == nil {
+					err = ferr
+				}
+			}
+		}
+		if ferr := flush(ctx); ferr != nil && err == nil {
+			err = ferr
+		}
+		return err
+	}
+
+	for {
+		select {
+		case s := <-e.queue:
+			pending = append(pending, s)
+			if len(pending) >= e.opts.BatchSize {
+				_ = flush(ctx)
+			}
+		case <-ticker.C:
+			_ = flush(ctx)
+		case result := <-e.flushes:
+			result <- drain(ctx)
+		case <-ctx.Done():
+			_ = drain(context.Background())
+			return
+		}
+	}
+}
+
+func (e *Exporter) send(ctx context.Context, spans []*span) (err error) {
+	tags := make([]tag, 0, len(e.opts.Tags))
+	for k, v := range e.opts.Tags {
+		tags = append(tags, stringTag(k, v))
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].key < tags[j].key })
+
+	var w thriftWriter
+	(&batch{serviceName: e.opts.ServiceName, processTags: tags, spans: spans}).write(&w)
+	body := w.buf.Bytes()
+
+	backoff := e.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		err = e.post(ctx, body)
+		if err == nil || attempt >= e.opts.MaxRetries {
+			return err
+		}
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (e *Exporter) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint,
+		bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-thrift")
+	resp, err := e.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("jaeger collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+func convertSpan(s *monkit.Span, err error, panicked bool, finish time.Time) *span {
+	trace := s.Trace()
+	traceIDHigh, traceIDLow := trace.FullId().Parts()
+	js := &span{
+		traceIDLow:    int64(traceIDLow),
+		traceIDHigh:   int64(traceIDHigh),
+		spanID:        s.Id(),
+		operationName: s.Func().FullName(),
+		flags:         1, // sampled
+		startTime:     trace.WallTime(s.Start()).UnixNano() / int64(time.Microsecond),
+		duration:      int64(finish.Sub(s.Start()) / time.Microsecond),
+	}
+	if parentID, ok := s.ParentId(); ok {
+		js.parentSpanID = parentID
+	} else if link, ok := trace.ParentTrace(); ok {
+		js.references = append(js.references, spanRef{
+			refType:    spanRefTypeFollowsFrom,
+			traceIDLow: link.TraceId,
+			spanID:     link.SpanId,
+		})
+	}
+	if metadata := trace.Metadata(); len(metadata) > 0 {
+		keys := make([]string, 0, len(metadata))
+		for k := range metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			js.tags = append(js.tags, stringTag(k, metadata[k]))
+		}
+	}
+	for _, annotation := range s.Annotations() {
+		js.tags = append(js.tags, stringTag(annotation.Name, annotation.Value))
+	}
+	if kind := s.Kind(); kind != monkit.SpanKindInternal {
+		js.tags = append(js.tags, stringTag("span.kind", kind.String()))
+	}
+	if err != nil || panicked {
+		js.tags = append(js.tags, boolTag("error", true))
+	}
+	if err != nil {
+		js.tags = append(js.tags, stringTag("error.message", err.Error()))
+	}
+	if panicked {
+		js.tags = append(js.tags, boolTag("panicked", true))
+	}
+	return js
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package jaeger
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// thriftReader decodes the thrift binary protocol into nested maps of field id
+// to value, which is all the test needs to check what the exporter sent.
+type thriftReader struct {
+	buf []byte
+}
+
+func (r *thriftReader) next(n int) []byte {
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case thriftBool:
+		return r.next(1)[0] == 1
+	case thriftI32:
+		return int32(binary.BigEndian.Uint32(r.next(4)))
+	case thriftI64:
+		return int64(binary.BigEndian.Uint64(r.next(8)))
+	case thriftString:
+		n := int(binary.BigEndian.Uint32(r.next(4)))
+		return string(r.next(n))
+	case thriftStruct:
+		fields := map[int16]interface{}{}
+		for {
+			ftyp := r.next(1)[0]
+			if ftyp == thriftStop {
+				return fields
+			}
+			id := int16(binary.BigEndian.Uint16(r.next(2)))
+			fields[id] = r.value(ftyp)
+		}
+	case thriftList:
+		etyp := r.next(1)[0]
+		n := int(binary.BigEndian.Uint32(r.next(4)))
+		list := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			list = append(list, r.value(etyp))
+		}
+		return list
+	}
+	panic("unsupported thrift type")
+}
+
+func tagMap(list interface{}) map[string]interface{} {
+	tags := map[string]interface{}{}
+	for _, t := range list.([]interface{}) {
+		fields := t.(map[int16]interface{})
+		if fields[2].(int32) == tagTypeBool {
+			tags[fields[1].(string)] = fields[5]
+		} else {
+			tags[fields[1].(string)] = fields[3]
+		}
+	}
+	return tags
+}
+
+func TestExporter(t *testing.T) {
+	var mu sync.Mutex
+	var attempts int
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		attempts++
+		first := attempts == 1
+		mu.Unlock()
+		if req.Header.Get("Content-Type") != "application/x-thrift" {
+			t.Errorf("unexpected content type %q", req.Header.Get("Content-Type"))
+		}
+		if first {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		bodies <- body
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	r := monkit.NewRegistry()
+	e := NewExporter(srv.URL, Options{
+		ServiceName: "svc",
+		Tags:        map[string]string{"region": "eu"},
+		BatchSize:   1,
+		Backoff:     time.Millisecond,
+	})
+	defer e.Register(r)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// unsampled traces are not exported.
+	var unsampled context.Context
+	r.ScopeNamed("jaeger").ContinueTrace(&unsampled, 1, 2, false, nil)(nil)
+
+	var sctx context.Context
+	err := errors.New("boom")
+	finish := r.ScopeNamed("jaeger").ContinueTrace(&sctx, 5, 6, true,
+		map[string]string{"http.uri": "/x"})
+	monkit.SpanFromCtx(sctx).SetKind(monkit.SpanKindServer)
+	finish(&err)
+
+	var body []byte
+	select {
+	case body = <-bodies:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the collector")
+	}
+
+	mu.Lock()
+	if attempts != 2 {
+		t.Fatalf("expected one retry, got %d attempts", attempts)
+	}
+	mu.Unlock()
+
+	b := (&thriftReader{buf: body}).value(thriftStruct).(map[int16]interface{})
+	process := b[1].(map[int16]interface{})
+	if process[1] != "svc" {
+		t.Fatalf("unexpected service name %v", process[1])
+	}
+	if tagMap(process[2])["region"] != "eu" {
+		t.Fatalf("unexpected process tags %v", tagMap(process[2]))
+	}
+
+	spans := b[2].([]interface{})
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	s := spans[0].(map[int16]interface{})
+	if s[1] != int64(5) || s[2] != int64(0) || s[4] != int64(6) {
+		t.Fatalf("unexpected ids %v %v %v", s[1], s[2], s[4])
+	}
+	if s[5] != "jaeger.TestExporter" {
+		t.Fatalf("unexpected operation name %v", s[5])
+	}
+	if s[7] != int32(1) {
+		t.Fatalf("unexpected flags %v", s[7])
+	}
+	start := time.UnixMicro(s[8].(int64))
+	if time.Since(start) > time.Minute || time.Since(start) < 0 {
+		t.Fatalf("unexpected start time %v", start)
+	}
+	tags := tagMap(s[10])
+	if tags["http.uri"] != "/x" || tags["error"] != true ||
+		tags["error.message"] != "boom" || tags["span.kind"] != "server" {
+		t.Fatalf("unexpected span tags %v", tags)
+	}
+}
+
+func TestConvertSpanParentTrace(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("jaeger")
+
+	ctx := context.Background()
+	finish := mon.Task()(&ctx)
+	s := monkit.SpanFromCtx(ctx)
+	s.Trace().SetParentTrace(7, 8)
+	finish(nil)
+
+	var w thriftWriter
+	convertSpan(s, nil, false, time.Now()).write(&w)
+	fields := (&thriftReader{buf: w.buf.Bytes()}).value(thriftStruct).(map[int16]interface{})
+	refs, _ := fields[6].([]interface{})
+	if len(refs) != 1 {
+		t.Fatalf("expected 1 reference, got %v", fields[6])
+	}
+	ref := refs[0].(map[int16]interface{})
+	if ref[1] != int32(spanRefTypeFollowsFrom) || ref[2] != int64(7) || ref[3] != int64(0) || ref[4] != int64(8) {
+		t.Fatalf("unexpected reference %v", ref)
+	}
+}
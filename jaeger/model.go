@@ -0,0 +1,140 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package jaeger
+
+// The types in this file mirror the structs of Jaeger's jaeger.thrift IDL
+// that the exporter needs.
+
+const (
+	tagTypeString = 0
+	tagTypeBool   = 2
+)
+
+type tag struct {
+	key  string
+	str  *string
+	bool *bool
+}
+
+func stringTag(key, val string) tag    { return tag{key: key, str: &val} }
+func boolTag(key string, val bool) tag { return tag{key: key, bool: &val} }
+
+func (t tag) write(w *thriftWriter) {
+	w.fieldBegin(thriftString, 1)
+	w.string(t.key)
+	w.fieldBegin(thriftI32, 2)
+	switch {
+	case t.bool != nil:
+		w.i32(tagTypeBool)
+		w.fieldBegin(thriftBool, 5)
+		w.bool(*t.bool)
+	default:
+		w.i32(tagTypeString)
+		w.fieldBegin(thriftString, 3)
+		if t.str != nil {
+			w.string(*t.str)
+		} else {
+			w.string("")
+		}
+	}
+	w.fieldStop()
+}
+
+func writeTags(w *thriftWriter, id int16, tags []tag) {
+	if len(tags) == 0 {
+		return
+	}
+	w.fieldBegin(thriftList, id)
+	w.listBegin(thriftStruct, len(tags))
+	for _, t := range tags {
+		t.write(w)
+	}
+}
+
+const (
+	spanRefTypeFollowsFrom = 1
+)
+
+// spanRef refers to a span of another trace, such as the one linked with
+// monkit.Trace.SetParentTrace.
+type spanRef struct {
+	refType     int32
+	traceIDLow  int64
+	traceIDHigh int64
+	spanID      int64
+}
+
+func (r spanRef) write(w *thriftWriter) {
+	w.fieldBegin(thriftI32, 1)
+	w.i32(r.refType)
+	w.fieldBegin(thriftI64, 2)
+	w.i64(r.traceIDLow)
+	w.fieldBegin(thriftI64, 3)
+	w.i64(r.traceIDHigh)
+	w.fieldBegin(thriftI64, 4)
+	w.i64(r.spanID)
+	w.fieldStop()
+}
+
+type span struct {
+	traceIDLow    int64
+	traceIDHigh   int64
+	spanID        int64
+	parentSpanID  int64
+	operationName string
+	references    []spanRef
+	flags         int32
+	startTime     int64 // microseconds since the epoch
+	duration      int64 // microseconds
+	tags          []tag
+}
+
+func (s *span) write(w *thriftWriter) {
+	w.fieldBegin(thriftI64, 1)
+	w.i64(s.traceIDLow)
+	w.fieldBegin(thriftI64, 2)
+	w.i64(s.traceIDHigh)
+	w.fieldBegin(thriftI64, 3)
+	w.i64(s.spanID)
+	w.fieldBegin(thriftI64, 4)
+	w.i64(s.parentSpanID)
+	w.fieldBegin(thriftString, 5)
+	w.string(s.operationName)
+	if len(s.references) > 0 {
+		w.fieldBegin(thriftList, 6)
+		w.listBegin(thriftStruct, len(s.references))
+		for _, r := range s.references {
+			r.write(w)
+		}
+	}
+	w.fieldBegin(thriftI32, 7)
+	w.i32(s.flags)
+	w.fieldBegin(thriftI64, 8)
+	w.i64(s.startTime)
+	w.fieldBegin(thriftI64, 9)
+	w.i64(s.duration)
+	writeTags(w, 10, s.tags)
+	w.fieldStop()
+}
+
+type batch struct {
+	serviceName string
+	processTags []tag
+	spans       []*span
+}
+
+func (b *batch) write(w *thriftWriter) {
+	w.fieldBegin(thriftStruct, 1)
+	w.fieldBegin(thriftString, 1)
+	w.string(b.serviceName)
+	writeTags(w, 2, b.processTags)
+	w.fieldStop()
+
+	w.fieldBegin(thriftList, 2)
+	w.listBegin(thriftStruct, len(b.spans))
+	for _, s := range b.spans {
+		s.write(w)
+	}
+	w.fieldStop()
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package jaeger
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// thrift binary protocol type ids.
+const (
+	thriftStop   = 0
+	thriftBool   = 2
+	thriftI32    = 8
+	thriftI64    = 10
+	thriftString = 11
+	thriftStruct = 12
+	thriftList   = 15
+)
+
+// thriftWriter writes values using the thrift binary protocol, which is what
+// the Jaeger collector expects for application/x-thrift requests.
+type thriftWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *thriftWriter) fieldBegin(typ byte, id int16) {
+	w.buf.WriteByte(typ)
+	w.i16(id)
+}
+
+func (w *thriftWriter) fieldStop() { w.buf.WriteByte(thriftStop) }
+
+func (w *thriftWriter) listBegin(elemType byte, size int) {
+	w.buf.WriteByte(elemType)
+	w.i32(int32(size))
+}
+
+func (w *thriftWriter) i16(v int16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], uint16(v))
+	w.buf.Write(b[:])
+}
+
+func (w *thriftWriter) i32(v int32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(v))
+	w.buf.Write(b[:])
+}
+
+func (w *thriftWriter) i64(v int64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(v))
+	w.buf.Write(b[:])
+}
+
+func (w *thriftWriter) bool(v bool) {
+	if v {
+		w.buf.WriteByte(1)
+	} else {
+		w.buf.WriteByte(0)
+	}
+}
+
+func (w *thriftWriter) string(v string) {
+	w.i32(int32(len(v)))
+	w.buf.WriteString(v)
+}
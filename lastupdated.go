@@ -0,0 +1,97 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// LastUpdatedSource is implemented by StatSources that keep track of when
+// they were last updated, such as Counter, Timer and the Val types. It lets
+// formatters attach a per-series timestamp instead of the collection time,
+// like the Collector of the prometheus package does with Options.Timestamps.
+type LastUpdatedSource interface {
+	StatSource
+
+	// LastUpdated returns when the StatSource was last updated, or the zero
+	// time if it never was.
+	LastUpdated() time.Time
+}
+
+// lastUpdated is the time of the last update in unix nanoseconds. It should
+// be the first field of its struct, to keep it 64-bit aligned for atomic
+// access.
+type lastUpdated struct{ nanos int64 }
+
+func (l *lastUpdated) touch() {
+	atomic.StoreInt64(&l.nanos, time.Now().UnixNano())
+}
+
+func (l *lastUpdated) time() time.Time {
+	nanos := atomic.LoadInt64(&l.nanos)
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// StatsTimestamped is like Stats, but also passes when the series was last
+// updated. The time is zero for StatSources that aren't a LastUpdatedSource.
+func (s *Scope) StatsTimestamped(cb func(key SeriesKey, field string, val float64, updated time.Time)) {
+	var updated time.Time
+	s.statsTimestamped(&updated, func(key SeriesKey, field string, val float64) {
+		cb(key, field, val, updated)
+	})
+}
+
+// statsTimestamped calls cb like Stats, setting *updated to when the source
+// of the series was last updated before each call.
+func (s *Scope) statsTimestamped(updated *time.Time,
+	cb func(key SeriesKey, field string, val float64)) {
+	emit := func(source StatSource) {
+		*updated = time.Time{}
+		if lu, ok := source.(LastUpdatedSource); ok {
+			*updated = lu.LastUpdated()
+		}
+		source.Stats(func(key SeriesKey, field string, val float64) {
+			cb(key.WithTag("scope", s.name), field, val)
+		})
+	}
+
+	for _, namedSource := range s.allNamedSources() {
+		emit(namedSource.source)
+	}
+
+	s.mtx.Lock()
+	chains := append([]StatSource(nil), s.chains...)
+	s.mtx.Unlock()
+
+	for _, source := range chains {
+		emit(source)
+	}
+}
+
+// StatsTimestamped is like Stats, but also passes when the series was last
+// updated. See Scope.StatsTimestamped. Like with Stats, the series of
+// imported Registries are included.
+func (r *Registry) StatsTimestamped(cb func(key SeriesKey, field string, val float64, updated time.Time)) {
+	var updated time.Time
+	r.walk(func(key SeriesKey, field string, val float64) {
+		cb(key, field, val, updated)
+	}, nil, func(s *Scope, cb func(key SeriesKey, field string, val float64)) {
+		s.statsTimestamped(&updated, cb)
+	})
+}
@@ -0,0 +1,45 @@
+package monkit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLastUpdated(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("lu")
+	c := mon.Counter("c")
+	v := mon.IntVal("v")
+	mon.Gauge("g", func() float64 { return 1 })
+
+	if !c.LastUpdated().IsZero() || !v.LastUpdated().IsZero() {
+		t.Fatal("expected zero times before any update")
+	}
+
+	c.Inc(1)
+	v.Observe(1)
+	first := c.LastUpdated()
+	if first.IsZero() || v.LastUpdated().IsZero() {
+		t.Fatal("expected times after update")
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	c.Inc(1)
+	if !c.LastUpdated().After(first) {
+		t.Fatalf("expected %v to be after %v", c.LastUpdated(), first)
+	}
+
+	times := map[string]time.Time{}
+	r.StatsTimestamped(func(key SeriesKey, field string, val float64, updated time.Time) {
+		times[key.WithField(field)] = updated
+	})
+	if got := times["c,scope=lu value"]; !got.Equal(c.LastUpdated()) {
+		t.Fatalf("expected counter time %v, got %v", c.LastUpdated(), got)
+	}
+	if got := times["v,scope=lu recent"]; !got.Equal(v.LastUpdated()) {
+		t.Fatalf("expected val time %v, got %v", v.LastUpdated(), got)
+	}
+	if got, ok := times["g,scope=lu value"]; !ok || !got.IsZero() {
+		t.Fatalf("expected zero gauge time, got %v", got)
+	}
+}
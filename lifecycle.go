@@ -0,0 +1,117 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/monotime"
+)
+
+// SpanTransitionKind is the kind of a SpanTransition.
+type SpanTransitionKind int
+
+const (
+	// SpanCreated is the transition of a Span that just started.
+	SpanCreated SpanTransitionKind = iota
+	// SpanAnnotated is the transition of a Span that Annotate was called on.
+	SpanAnnotated
+	// SpanChildAdded is the transition of a Span that got a new child Span.
+	SpanChildAdded
+	// SpanFinished is the transition of a Span that finished.
+	SpanFinished
+)
+
+// String implements fmt.Stringer.
+func (k SpanTransitionKind) String() string {
+	switch k {
+	case SpanCreated:
+		return "created"
+	case SpanAnnotated:
+		return "annotated"
+	case SpanChildAdded:
+		return "child-added"
+	case SpanFinished:
+		return "finished"
+	}
+	return "unknown"
+}
+
+// SpanTransition describes a change of the state of a Span, see
+// SpanLifecycleObserver. Only the fields of its Kind are set.
+type SpanTransition struct {
+	Kind SpanTransitionKind
+
+	// Time is when the transition happened.
+	Time time.Time
+
+	// Annotation is the annotation as given to Annotate, for SpanAnnotated.
+	Annotation Annotation
+
+	// Child is the new child Span, for SpanChildAdded.
+	Child *Span
+
+	// Err and Panicked are how the Span finished, for SpanFinished.
+	Err      error
+	Panicked bool
+}
+
+// SpanLifecycleObserver can be implemented by a SpanObserver or
+// SpanCtxObserver registered on a Trace to additionally receive every state
+// transition of the Trace's Spans in order, so that a single method can track
+// them. Observers that don't implement it are unaffected.
+//
+// Transition is called synchronously, outside of the locks of the Span, so it
+// may read the Span but should not block. SpanCreated follows the
+// observer's Start, and SpanFinished precedes its Finish, unless Finish is
+// run asynchronously, see Registry.SetObserverAsync. A child's SpanCreated
+// precedes the parent's SpanChildAdded.
+type SpanLifecycleObserver interface {
+	Transition(s *Span, t SpanTransition)
+}
+
+// observed returns whether any observers are registered on the Trace, so
+// that callers can skip building transitions nobody receives.
+func (t *Trace) observed() bool {
+	return loadSpanObserverTuple(&t.spanObservers) != nil
+}
+
+// observeTransition passes tr to the SpanLifecycleObservers of the Trace.
+func (t *Trace) observeTransition(s *Span, tr SpanTransition) {
+	for l := loadSpanObserverTuple(&t.spanObservers); l != nil; l = loadSpanObserverTuple(&l.cdr) {
+		if observer := lifecycleObserver(l.car); observer != nil {
+			observer.Transition(s, tr)
+		}
+	}
+}
+
+func lifecycleObserver(observer SpanCtxObserver) SpanLifecycleObserver {
+	if wrapped, ok := observer.(spanObserverToSpanCtxObserver); ok {
+		lifecycle, _ := wrapped.observer.(SpanLifecycleObserver)
+		return lifecycle
+	}
+	lifecycle, _ := observer.(SpanLifecycleObserver)
+	return lifecycle
+}
+
+func (s *Span) observeAnnotation(name, val string) {
+	if s.trace.observed() {
+		s.trace.observeTransition(s, SpanTransition{
+			Kind:       SpanAnnotated,
+			Time:       monotime.Now(),
+			Annotation: Annotation{Name: name, Value: val},
+		})
+	}
+}
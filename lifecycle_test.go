@@ -0,0 +1,104 @@
+package monkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type lifecycleRecorder struct {
+	transitions []string
+}
+
+func (r *lifecycleRecorder) Start(s *Span)                                              {}
+func (r *lifecycleRecorder) Finish(s *Span, err error, panicked bool, finish time.Time) {}
+
+func (r *lifecycleRecorder) Transition(s *Span, t SpanTransition) {
+	if t.Time.IsZero() {
+		panic("transition without time")
+	}
+	entry := fmt.Sprintf("%s %s", s.Func().ShortName(), t.Kind)
+	switch t.Kind {
+	case SpanAnnotated:
+		entry += " " + t.Annotation.Name + "=" + t.Annotation.Value
+	case SpanChildAdded:
+		entry += " " + t.Child.Func().ShortName()
+	case SpanFinished:
+		entry += fmt.Sprintf(" %v", t.Err)
+	}
+	r.transitions = append(r.transitions, entry)
+}
+
+func TestSpanLifecycleObserver(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("lifecycle")
+
+	rec := &lifecycleRecorder{}
+	// observers without Transition must keep working next to it.
+	plain := &countingObserver{}
+	defer r.ObserveTraces(func(trace *Trace) {
+		trace.ObserveSpans(rec)
+		trace.ObserveSpans(plain)
+	})()
+
+	ctx := context.Background()
+	finish := mon.TaskNamed("parent")(&ctx)
+	SpanFromCtx(ctx).Annotate("k", "v")
+	func() {
+		ctx := ctx
+		err := errors.New("boom")
+		defer mon.TaskNamed("child")(&ctx)(&err)
+	}()
+	finish(nil)
+
+	expected := []string{
+		"parent created",
+		"parent annotated k=v",
+		"child created",
+		"parent child-added child",
+		"child finished boom",
+		"parent finished <nil>",
+	}
+	if !reflect.DeepEqual(rec.transitions, expected) {
+		t.Fatalf("unexpected transitions %q", rec.transitions)
+	}
+	if plain.starts != 2 || plain.finishes != 2 {
+		t.Fatalf("unexpected plain observer calls %+v", plain)
+	}
+}
+
+func TestSpanLifecycleObserverStoredAnnotations(t *testing.T) {
+	r := NewRegistry()
+	r.SetMaxAnnotationLength(3)
+	r.SetAnnotationPolicy(AnnotationFirstWins)
+	mon := r.ScopeNamed("lifecycle")
+
+	rec := &lifecycleRecorder{}
+	defer r.ObserveTraces(func(trace *Trace) { trace.ObserveSpans(rec) })()
+
+	ctx := context.Background()
+	finish := mon.TaskNamed("span")(&ctx)
+	SpanFromCtx(ctx).Annotate("k", "long")
+	// dropped by the policy, so not reported.
+	SpanFromCtx(ctx).Annotate("k", "v")
+	finish(nil)
+
+	expected := []string{
+		"span created",
+		"span annotated k=lon…(truncated)",
+		"span finished <nil>",
+	}
+	if !reflect.DeepEqual(rec.transitions, expected) {
+		t.Fatalf("unexpected transitions %q", rec.transitions)
+	}
+}
+
+type countingObserver struct{ starts, finishes int }
+
+func (o *countingObserver) Start(s *Span) { o.starts++ }
+func (o *countingObserver) Finish(s *Span, err error, panicked bool, finish time.Time) {
+	o.finishes++
+}
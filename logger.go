@@ -0,0 +1,41 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+type loggerRef struct {
+	logf func(format string, args ...interface{})
+}
+
+// SetLogger makes the Registry report internal problems that it otherwise
+// only counts or handles silently, such as span observer callbacks dropped
+// because of SetObserverAsync, Spans force-finished by SetTraceTTL, annotation
+// values truncated because of SetMaxAnnotationLength, call sites beyond the
+// limit of Func.TrackCallers or attachments rejected by Span.Attach, by
+// calling logf with a printf-style message. logf must be safe for concurrent
+// use and should not block. By default nothing is logged, and passing nil
+// restores that.
+func (r *Registry) SetLogger(logf func(format string, args ...interface{})) {
+	var ref *loggerRef
+	if logf != nil {
+		ref = &loggerRef{logf: logf}
+	}
+	storeLoggerRef(&r.logger, ref)
+}
+
+func (r *registryInternal) logf(format string, args ...interface{}) {
+	if ref := loadLoggerRef(&r.logger); ref != nil {
+		ref.logf(format, args...)
+	}
+}
@@ -0,0 +1,96 @@
+package monkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetLogger(t *testing.T) {
+	r := NewRegistry()
+	r.SetObserverAsync(1, 1)
+	defer r.SetObserverAsync(0, 0)
+
+	var mtx sync.Mutex
+	var logged []string
+	r.SetLogger(func(format string, args ...interface{}) {
+		mtx.Lock()
+		logged = append(logged, fmt.Sprintf(format, args...))
+		mtx.Unlock()
+	})
+
+	obs := &blockingObserver{
+		entered: make(chan struct{}, 3),
+		release: make(chan struct{}),
+	}
+	r.ObserveTraces(func(t *Trace) { t.ObserveSpans(obs) })
+	defer close(obs.release)
+
+	mon := r.ScopeNamed("test")
+	ctx := context.Background()
+	// the first finish occupies the only worker.
+	mon.Task()(&ctx)(nil)
+	select {
+	case <-obs.entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("observer never ran")
+	}
+	// the second one fills the queue, the third one is dropped.
+	mon.Task()(&ctx)(nil)
+	mon.Task()(&ctx)(nil)
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	if len(logged) != 1 || !strings.Contains(logged[0], "1 Finish callbacks dropped") {
+		t.Fatalf("expected the drop to be logged, got %q", logged)
+	}
+}
+
+func TestSetLoggerLimits(t *testing.T) {
+	r := NewRegistry()
+	var logged []string
+	r.SetLogger(func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+
+	r.SetMaxAnnotationLength(3)
+	mon := r.ScopeNamed("test")
+	ctx := context.Background()
+	func() {
+		defer mon.Task()(&ctx)(nil)
+		s := SpanFromCtx(ctx)
+		s.Annotate("a", "long value")
+		s.Annotate("b", "long value")
+		s.Attach("big", make([]byte, MaxAttachmentSize+1))
+		for i := 0; i < 4; i++ {
+			s.Attach("part", make([]byte, MaxAttachmentSize))
+		}
+		s.Attach("part", make([]byte, 1))
+	}()
+
+	f := mon.FuncNamed("called")
+	f.TrackCallers(1)
+	for i := 0; i < 3; i++ {
+		f.Task(&ctx)(nil) // the first call site
+	}
+	f.Task(&ctx)(nil) // overflows
+	f.Task(&ctx)(nil) // isn't logged again
+
+	expected := []string{
+		`attachment "big" of span`,
+		`attachment "part" of span`,
+		"had 2 annotation values truncated to 3 bytes",
+		"more than 1 call sites",
+	}
+	if len(logged) != len(expected) {
+		t.Fatalf("expected %d messages, got %q", len(expected), logged)
+	}
+	for i, msg := range expected {
+		if !strings.Contains(logged[i], msg) {
+			t.Fatalf("expected %q in %q", msg, logged[i])
+		}
+	}
+}
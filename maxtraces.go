@@ -0,0 +1,42 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sync/atomic"
+)
+
+// SetMaxTrackedTraces bounds how many root Spans, and with them how many
+// Traces, the Registry tracks at once for RootSpans, AllSpans,
+// OldestOpenSpan and the trace TTL (see SetTraceTTL). Once n root Spans are
+// running, further root Spans work as usual, including their stats, children
+// and observers, but are not tracked for these features until they finish,
+// and each of them counts towards UntrackedTraces. This bounds the memory
+// leaked Traces can hold on to. A limit of zero or less, the default, tracks
+// every Trace.
+func (r *Registry) SetMaxTrackedTraces(n int) {
+	atomic.StoreInt64(&r.maxTrackedTraces, int64(n))
+}
+
+// MaxTrackedTraces returns the limit set by SetMaxTrackedTraces.
+func (r *Registry) MaxTrackedTraces() int {
+	return int(atomic.LoadInt64(&r.maxTrackedTraces))
+}
+
+// UntrackedTraces returns how many root Spans were not tracked because of
+// the limit of SetMaxTrackedTraces.
+func (r *Registry) UntrackedTraces() int64 {
+	return atomic.LoadInt64(&r.untrackedTraces)
+}
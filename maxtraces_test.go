@@ -0,0 +1,55 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMaxTrackedTraces(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("tracked")
+	r.SetMaxTrackedTraces(2)
+
+	var finishes []func(*error)
+	var spans []*Span
+	for i := 0; i < 3; i++ {
+		ctx := context.Background()
+		finishes = append(finishes, mon.TaskNamed("root")(&ctx))
+		spans = append(spans, SpanFromCtx(ctx))
+	}
+	if got := r.UntrackedTraces(); got != 1 {
+		t.Fatalf("expected 1 untracked trace, got %d", got)
+	}
+	tracked := map[*Span]bool{}
+	r.RootSpans(func(s *Span) { tracked[s] = true })
+	if len(tracked) != 2 || !tracked[spans[0]] || !tracked[spans[1]] || tracked[spans[2]] {
+		t.Fatalf("unexpected tracked spans %v", tracked)
+	}
+
+	// the untracked trace still works.
+	ctx := context.Context(spans[2])
+	mon.TaskNamed("child")(&ctx)(nil)
+	if child := SpanFromCtx(ctx); child.Trace() != spans[2].Trace() {
+		t.Fatal("expected the child in the untracked trace")
+	}
+	for _, finish := range finishes {
+		finish(nil)
+	}
+	stats := Collect(mon)
+	if stats["function,name=root,scope=tracked total"] != 3 ||
+		stats["function,name=child,scope=tracked total"] != 1 {
+		t.Fatalf("unexpected stats %v", stats)
+	}
+
+	// finished traces free up room.
+	ctx = context.Background()
+	defer mon.TaskNamed("root")(&ctx)(nil)
+	if got := r.UntrackedTraces(); got != 1 {
+		t.Fatalf("expected 1 untracked trace, got %d", got)
+	}
+	count := 0
+	r.RootSpans(func(*Span) { count++ })
+	if count != 1 {
+		t.Fatalf("expected 1 tracked span, got %d", count)
+	}
+}
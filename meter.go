@@ -50,6 +50,9 @@ type meterBucket struct {
 //	  ...
 //	}
 type Meter struct {
+	// sync/atomic things
+	clock *clockRef
+
 	mtx    sync.Mutex
 	total  int64
 	slices [ticksToKeep]meterBucket
@@ -73,7 +76,7 @@ func NewMeter(key SeriesKey) *Meter {
 func (e *Meter) Reset(new_total int64) {
 	e.mtx.Lock()
 	e.total = new_total
-	now := monotime.Now()
+	now := e.now()
 	for i := range e.slices {
 		e.slices[i].count = 0
 		e.slices[i].start = now
@@ -102,7 +105,63 @@ func (e *Meter) Mark64(amount int64) {
 	e.mtx.Unlock()
 }
 
-func (e *Meter) tick(now time.Time) {
+// SetClock makes the Meter use now instead of the monotonic clock to compute
+// its rate, such as to make rates deterministic in tests. The current window
+// restarts at the new clock's time, but the totals are kept. Passing nil
+// returns to the monotonic clock. See also Registry.SetMeterClock.
+func (e *Meter) SetClock(now func() time.Time) {
+	var ref *clockRef
+	if now != nil {
+		ref = &clockRef{now: now}
+	}
+	e.mtx.Lock()
+	storeClockRef(&e.clock, ref)
+	start := e.now()
+	for i := range e.slices {
+		e.slices[i].start = start
+	}
+	e.mtx.Unlock()
+}
+
+type clockRef struct {
+	now func() time.Time
+}
+
+// SetMeterClock calls SetClock with now on every Meter created through the
+// Scopes of this Registry, both existing ones and ones created later. Passing
+// nil returns them to the monotonic clock.
+func (r *Registry) SetMeterClock(now func() time.Time) {
+	var ref *clockRef
+	if now != nil {
+		ref = &clockRef{now: now}
+	}
+	storeClockRef(&r.meterClock, ref)
+	r.Scopes(func(s *Scope) {
+		for _, source := range s.allNamedSources() {
+			if m, ok := source.source.(*Meter); ok {
+				m.SetClock(now)
+			}
+		}
+	})
+}
+
+func (r *Registry) newMeter(key SeriesKey) *Meter {
+	m := NewMeter(key)
+	if ref := loadClockRef(&r.meterClock); ref != nil {
+		m.SetClock(ref.now)
+	}
+	return m
+}
+
+func (e *Meter) now() time.Time {
+	if ref := loadClockRef(&e.clock); ref != nil {
+		return ref.now()
+	}
+	return monotime.Now()
+}
+
+func (e *Meter) tick() {
+	now := e.now()
 	e.mtx.Lock()
 	// only advance meter buckets if something happened. otherwise
 	// rare events will always just have zero rates.
@@ -133,21 +192,24 @@ func (e *Meter) stats(now time.Time) (rate float64, total int64) {
 	return rate, total
 }
 
-// Rate returns the rate over the internal sliding window
+// Rate returns the rate over the internal sliding window, in events per
+// second. It is the same rate Stats reports, computed directly, so it is
+// cheap enough for decisions like backpressure. Until any time has passed in
+// the window, the rate is zero.
 func (e *Meter) Rate() float64 {
-	rate, _ := e.stats(monotime.Now())
+	rate, _ := e.stats(e.now())
 	return rate
 }
 
 // Total returns the total over the internal sliding window
 func (e *Meter) Total() float64 {
-	_, total := e.stats(monotime.Now())
+	_, total := e.stats(e.now())
 	return float64(total)
 }
 
 // Stats implements the StatSource interface
 func (e *Meter) Stats(cb func(key SeriesKey, field string, val float64)) {
-	rate, total := e.stats(monotime.Now())
+	rate, total := e.stats(e.now())
 	cb(e.key, "rate", rate)
 	cb(e.key, "total", float64(total))
 }
@@ -173,9 +235,8 @@ func NewDiffMeter(key SeriesKey, meter1, meter2 *Meter) *DiffMeter {
 
 // Stats implements the StatSource interface
 func (m *DiffMeter) Stats(cb func(key SeriesKey, field string, val float64)) {
-	now := monotime.Now()
-	rate1, total1 := m.meter1.stats(now)
-	rate2, total2 := m.meter2.stats(now)
+	rate1, total1 := m.meter1.stats(m.meter1.now())
+	rate2, total2 := m.meter2.stats(m.meter2.now())
 	cb(m.key, "rate", rate1-rate2)
 	cb(m.key, "total", float64(total1-total2))
 }
@@ -202,9 +263,8 @@ func (t *ticker) run() {
 		t.mtx.Lock()
 		meters := t.meters // this is safe since we only use append
 		t.mtx.Unlock()
-		now := monotime.Now()
 		for _, m := range meters {
-			m.tick(now)
+			m.tick()
 		}
 	}
 }
@@ -0,0 +1,80 @@
+package monkit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMeterClock(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+
+	m := NewMeter(NewSeriesKey("events"))
+	m.SetClock(clock)
+	m.Mark(30)
+	now = now.Add(10 * time.Second)
+	if rate := m.Rate(); rate != 3 {
+		t.Fatalf("expected a rate of 3, got %v", rate)
+	}
+
+	// rates stay the same when windows move on, as long as time is kept.
+	m.tick()
+	m.Mark(30)
+	now = now.Add(10 * time.Second)
+	if rate := m.Rate(); rate != 3 {
+		t.Fatalf("expected a rate of 3 after a tick, got %v", rate)
+	}
+	if total := m.Total(); total != 60 {
+		t.Fatalf("expected a total of 60, got %v", total)
+	}
+}
+
+func TestRegistryMeterClock(t *testing.T) {
+	now := time.Unix(1000, 0)
+	r := NewRegistry()
+	mon := r.ScopeNamed("meters")
+	existing := mon.Meter("existing")
+
+	r.SetMeterClock(func() time.Time { return now })
+	created := mon.Meter("created")
+	existing.Mark(10)
+	created.Mark(20)
+	now = now.Add(5 * time.Second)
+
+	stats := Collect(mon)
+	if got := stats["existing,scope=meters rate"]; got != 2 {
+		t.Fatalf("expected a rate of 2 for the existing meter, got %v", got)
+	}
+	if got := stats["created,scope=meters rate"]; got != 4 {
+		t.Fatalf("expected a rate of 4 for the new meter, got %v", got)
+	}
+}
+
+func TestMeterRate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := NewMeter(NewSeriesKey("events"))
+	m.SetClock(func() time.Time { return now })
+
+	// no time passed yet, so there is not enough data for a rate.
+	m.Mark(10)
+	if rate := m.Rate(); rate != 0 {
+		t.Fatalf("expected a rate of 0, got %v", rate)
+	}
+
+	for i := 0; i < 4; i++ {
+		now = now.Add(500 * time.Millisecond)
+		m.Mark(10)
+	}
+	if rate := m.Rate(); rate != 25 {
+		t.Fatalf("expected a rate of 25, got %v", rate)
+	}
+	var statsRate float64
+	m.Stats(func(key SeriesKey, field string, val float64) {
+		if field == "rate" {
+			statsRate = val
+		}
+	})
+	if statsRate != m.Rate() {
+		t.Fatalf("Stats reported %v, Rate %v", statsRate, m.Rate())
+	}
+}
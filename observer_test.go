@@ -0,0 +1,99 @@
+package monkit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recursiveObserver starts and finishes a child Span, on the observed Trace,
+// from within Start and Finish of the Spans of the observed Func, and uses
+// the Trace and its observer list while doing so.
+type recursiveObserver struct {
+	observed *Func
+	helper   *Func
+
+	mtx   sync.Mutex
+	spans int
+}
+
+func (o *recursiveObserver) Start(ctx context.Context, s *Span) context.Context {
+	if s.Func() == o.observed {
+		o.child(s)
+	}
+	return ctx
+}
+
+func (o *recursiveObserver) Finish(ctx context.Context, s *Span, err error, panicked bool, finish time.Time) {
+	if s.Func() != o.observed {
+		return
+	}
+	o.child(s)
+	s.Trace().ObserveSpans(&countingObserver{})()
+}
+
+func (o *recursiveObserver) child(s *Span) {
+	s.Trace().Set("recursive", true)
+	ctx := context.Context(s)
+	o.helper.Task(&ctx)(nil)
+	o.mtx.Lock()
+	o.spans++
+	o.mtx.Unlock()
+}
+
+func TestRecursiveObserver(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		r := NewRegistry()
+		if async {
+			r.SetObserverAsync(1, 1024)
+		}
+		mon := r.ScopeNamed("recursive")
+		observer := &recursiveObserver{
+			observed: mon.FuncNamed("observed"),
+			helper:   mon.FuncNamed("helper"),
+		}
+		cancel := r.ObserveTraces(func(trace *Trace) {
+			trace.ObserveSpansCtx(observer)
+		})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 10; i++ {
+				ctx := context.Background()
+				finish := observer.observed.Task(&ctx)
+				func() {
+					ctx := ctx
+					defer observer.observed.Task(&ctx)(nil)
+				}()
+				finish(nil)
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("async %v: observers creating spans deadlocked", async)
+		}
+		cancel()
+
+		// two observed Spans per iteration, each starting a child in both
+		// Start and Finish.
+		expected := 10 * 2 * 2
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			observer.mtx.Lock()
+			spans := observer.spans
+			observer.mtx.Unlock()
+			if spans == expected {
+				break
+			}
+			if !async || time.Now().After(deadline) {
+				t.Fatalf("async %v: expected %d spans started by the observer, got %d",
+					async, expected, spans)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		r.SetObserverAsync(0, 0)
+	}
+}
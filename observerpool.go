@@ -0,0 +1,123 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// observerPool runs SpanCtxObserver Finish callbacks on a fixed set of
+// worker goroutines.
+type observerPool struct {
+	queue chan func()
+	stop  chan struct{}
+}
+
+func newObserverPool(workers, queueDepth int) *observerPool {
+	p := &observerPool{
+		queue: make(chan func(), queueDepth),
+		stop:  make(chan struct{}),
+	}
+	for i := 0; i < workers; i++ {
+		go p.run()
+	}
+	return p
+}
+
+func (p *observerPool) run() {
+	for {
+		select {
+		case cb := <-p.queue:
+			cb()
+		case <-p.stop:
+			// drain whatever was queued before the pool was replaced.
+			for {
+				select {
+				case cb := <-p.queue:
+					cb()
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// submit queues cb without blocking, returning false if the queue is full.
+func (p *observerPool) submit(cb func()) bool {
+	select {
+	case p.queue <- cb:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetObserverAsync changes how span observers registered on traces from this
+// Registry are notified when a Span finishes. By default, observers are called
+// synchronously as part of finishing the Span, so a slow observer slows down
+// the instrumented code. With workers > 0, Finish callbacks are instead handed
+// to a pool of workers goroutines through a queue holding up to queueDepth
+// pending callbacks.
+//
+// This trades completeness and ordering for latency:
+//   - when the queue is full, the callback is dropped rather than waiting.
+//     Dropped callbacks are counted, see ObserverDrops.
+//   - with more than one worker, Finish callbacks may run in any order, and
+//     may run after Start callbacks of later Spans.
+//   - a Finish callback may run after the code that finished the Span has
+//     moved on, so observers must not rely on the Span's context still being
+//     live.
+//
+// Start callbacks are always synchronous, as they are able to change the
+// context the Span uses going forward.
+//
+// Calling SetObserverAsync with workers <= 0 returns to synchronous
+// callbacks. Callbacks already queued are still run by the old workers.
+func (r *Registry) SetObserverAsync(workers, queueDepth int) {
+	var pool *observerPool
+	if workers > 0 {
+		if queueDepth < 0 {
+			queueDepth = 0
+		}
+		pool = newObserverPool(workers, queueDepth)
+	}
+	if old := swapObserverPool(&r.observerPool, pool); old != nil {
+		close(old.stop)
+	}
+}
+
+// ObserverDrops returns how many span observer Finish callbacks have been
+// dropped because the pool configured with SetObserverAsync was saturated.
+func (r *Registry) ObserverDrops() int64 {
+	return atomic.LoadInt64(&r.observerDrops)
+}
+
+func (r *Registry) observeFinish(observer SpanCtxObserver, ctx context.Context,
+	s *Span, err error, panicked bool, finish time.Time) {
+	pool := loadObserverPool(&r.observerPool)
+	if pool == nil {
+		observer.Finish(ctx, s, err, panicked, finish)
+		return
+	}
+	if !pool.submit(func() { observer.Finish(ctx, s, err, panicked, finish) }) {
+		// log at powers of two to not flood the log while saturated.
+		if drops := atomic.AddInt64(&r.observerDrops, 1); drops&(drops-1) == 0 {
+			r.logf("monkit: span observer queue full, %d Finish callbacks dropped so far", drops)
+		}
+	}
+}
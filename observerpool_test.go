@@ -0,0 +1,61 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type blockingObserver struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingObserver) Start(s *Span) {}
+
+func (b *blockingObserver) Finish(s *Span, err error, panicked bool, finish time.Time) {
+	b.entered <- struct{}{}
+	<-b.release
+}
+
+func TestObserverAsync(t *testing.T) {
+	r := NewRegistry()
+	r.SetObserverAsync(1, 1)
+	defer r.SetObserverAsync(0, 0)
+
+	obs := &blockingObserver{
+		entered: make(chan struct{}, 3),
+		release: make(chan struct{}),
+	}
+	r.ObserveTraces(func(t *Trace) { t.ObserveSpans(obs) })
+
+	mon := r.ScopeNamed("test")
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ctx := context.Background()
+		// the first finish occupies the only worker.
+		mon.Task()(&ctx)(nil)
+		<-obs.entered
+		// the second one fills the queue, the third one is dropped.
+		mon.Task()(&ctx)(nil)
+		mon.Task()(&ctx)(nil)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("span finish blocked on a slow observer")
+	}
+
+	if drops := r.ObserverDrops(); drops != 1 {
+		t.Fatalf("expected 1 drop, got %d", drops)
+	}
+
+	close(obs.release)
+	select {
+	case <-obs.entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued callback never ran")
+	}
+}
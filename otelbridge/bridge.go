@@ -0,0 +1,181 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package otelbridge
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// Bridge starts an OpenTelemetry span for every monkit Span it observes and
+// ends it when the monkit Span finishes, with the Span's annotations as
+// attributes. It implements monkit.SpanCtxObserver, see Register.
+//
+// OpenTelemetry spans started within a monkit Span, and monkit Spans started
+// within an OpenTelemetry span, get the right parent, as the OpenTelemetry
+// span is stored in the context of the monkit Span. monkit Spans continuing a
+// remote trace are parented to the remote span, which is the OpenTelemetry
+// span the upstream Bridge exported if both use IDGenerator. The root span of
+// a Trace linked to another one, see monkit.Trace.SetParentTrace, links to the
+// span of the other trace. The monkit trace and span ids are recorded as the
+// monkit.trace_id and monkit.span_id attributes, and the metadata of the
+// Trace, see monkit.Trace.SetMetadata, as attributes of every span.
+type Bridge struct {
+	tracer trace.Tracer
+}
+
+// New returns a Bridge starting spans with tracer, whose provider should use
+// IDGenerator.
+func New(tracer trace.Tracer) *Bridge {
+	return &Bridge{tracer: tracer}
+}
+
+// Register starts mirroring Spans of all traces started on r from now on,
+// until cancel is called. Spans of traces that were already observed when
+// cancel is called are still mirrored until they finish.
+func (b *Bridge) Register(r *monkit.Registry) (cancel func()) {
+	return r.ObserveTraces(func(t *monkit.Trace) {
+		t.ObserveSpansCtx(b)
+	})
+}
+
+type bridgeKey struct{}
+
+// startingKey holds the monkit Span Bridge.Start starts an OpenTelemetry span
+// for, for IDGenerator.
+type startingKey struct{}
+
+// IDGenerator returns an sdktrace.IDGenerator that gives the OpenTelemetry
+// spans of a Bridge the ids of their monkit Spans, and random ids to other
+// spans. Tracer providers passed to New should use it, for example with
+// sdktrace.WithIDGenerator, as otherwise the ids of the OpenTelemetry spans
+// are unrelated to the ids monkit propagates, and traces crossing processes
+// refer to parents that were never exported.
+func IDGenerator() sdktrace.IDGenerator { return idGenerator{} }
+
+type idGenerator struct{}
+
+func (idGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	if s, ok := ctx.Value(startingKey{}).(*monkit.Span); ok {
+		return traceID(s.Trace().FullId()), spanID(s.Id())
+	}
+	return traceID(monkit.NewID128()), spanID(monkit.NewId())
+}
+
+func (idGenerator) NewSpanID(ctx context.Context, _ trace.TraceID) trace.SpanID {
+	if s, ok := ctx.Value(startingKey{}).(*monkit.Span); ok {
+		return spanID(s.Id())
+	}
+	return spanID(monkit.NewId())
+}
+
+type bridgeSpan struct {
+	span *monkit.Span
+	otel trace.Span
+}
+
+// Start implements monkit.SpanCtxObserver.
+func (b *Bridge) Start(ctx context.Context, s *monkit.Span) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		if parentID, ok := s.ParentId(); ok {
+			ctx = trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    traceID(s.Trace().FullId()),
+				SpanID:     spanID(parentID),
+				TraceFlags: trace.TraceFlags(s.Trace().Flags()),
+				Remote:     true,
+			}))
+		}
+	}
+	opts := []trace.SpanStartOption{
+		trace.WithTimestamp(s.Start()),
+		trace.WithSpanKind(spanKind(s.Kind())),
+		trace.WithAttributes(
+			attribute.String("monkit.trace_id", strconv.FormatInt(s.Trace().Id(), 10)),
+			attribute.String("monkit.span_id", strconv.FormatInt(s.Id(), 10)),
+		),
+	}
+	if _, hasParent := s.ParentId(); !hasParent {
+		if link, ok := s.Trace().ParentTrace(); ok {
+			opts = append(opts, trace.WithLinks(trace.Link{
+				SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+					TraceID: traceID(monkit.IDFromInt64(link.TraceId)),
+					SpanID:  spanID(link.SpanId),
+					Remote:  true,
+				}),
+			}))
+		}
+	}
+	// only the span started here takes its ids from s, so the key is not
+	// kept in the returned context.
+	_, span := b.tracer.Start(context.WithValue(ctx, startingKey{}, s), s.Func().FullName(), opts...)
+	ctx = trace.ContextWithSpan(ctx, span)
+	return context.WithValue(ctx, bridgeKey{}, &bridgeSpan{span: s, otel: span})
+}
+
+// Finish implements monkit.SpanCtxObserver.
+func (b *Bridge) Finish(ctx context.Context, s *monkit.Span, err error, panicked bool, finish time.Time) {
+	bs, _ := ctx.Value(bridgeKey{}).(*bridgeSpan)
+	if bs == nil || bs.span != s {
+		return
+	}
+	annotations := s.Annotations()
+	metadata := s.Trace().Metadata()
+	attrs := make([]attribute.KeyValue, 0, len(metadata)+len(annotations)+1)
+	for k, v := range metadata {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	for _, a := range annotations {
+		attrs = append(attrs, attribute.String(a.Name, a.Value))
+	}
+	if kind := s.Kind(); kind != monkit.SpanKindInternal {
+		// the kind is often only set after the Span started.
+		attrs = append(attrs, attribute.String("monkit.span_kind", kind.String()))
+	}
+	bs.otel.SetAttributes(attrs...)
+	switch {
+	case panicked:
+		bs.otel.SetStatus(codes.Error, "panicked")
+	case err != nil:
+		bs.otel.RecordError(err, trace.WithTimestamp(finish))
+		bs.otel.SetStatus(codes.Error, fmt.Sprint(err))
+	}
+	bs.otel.End(trace.WithTimestamp(finish))
+}
+
+func spanKind(kind monkit.SpanKind) trace.SpanKind {
+	switch kind {
+	case monkit.SpanKindServer:
+		return trace.SpanKindServer
+	case monkit.SpanKindClient:
+		return trace.SpanKindClient
+	case monkit.SpanKindProducer:
+		return trace.SpanKindProducer
+	case monkit.SpanKindConsumer:
+		return trace.SpanKindConsumer
+	default:
+		return trace.SpanKindInternal
+	}
+}
+
+func traceID(monkitID monkit.ID) (id trace.TraceID) {
+	hi, lo := monkitID.Parts()
+	binary.BigEndian.PutUint64(id[:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id
+}
+
+func spanID(id int64) (rv trace.SpanID) {
+	binary.BigEndian.PutUint64(rv[:], uint64(id))
+	return rv
+}
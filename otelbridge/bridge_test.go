@@ -0,0 +1,183 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package otelbridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestBridge(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("bridge")
+	cancel := New(tracer).Register(r)
+	defer cancel()
+
+	ctx := context.Background()
+	finish := mon.TaskNamed("parent")(&ctx)
+	monkit.SpanFromCtx(ctx).Annotate("user", "alice")
+	monkit.SpanFromCtx(ctx).Trace().SetMetadata("tenant", "a")
+
+	// an OTel span started inside the monkit span is its child.
+	_, otelChild := tracer.Start(ctx, "otel-child")
+	otelChild.End()
+
+	func() {
+		child := ctx
+		err := errors.New("boom")
+		defer mon.TaskNamed("child")(&child)(&err)
+	}()
+	finish(nil)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	parent, child, otel := spans["bridge.parent"], spans["bridge.child"], spans["otel-child"]
+	if parent == nil || child == nil || otel == nil {
+		t.Fatalf("missing spans: %v", spans)
+	}
+	if parent.Parent().IsValid() {
+		t.Fatal("expected the root span to have no parent")
+	}
+	for _, s := range []sdktrace.ReadOnlySpan{child, otel} {
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() ||
+			s.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+			t.Fatalf("expected %s to be a child of the parent span", s.Name())
+		}
+	}
+	if !hasAttribute(parent, attribute.String("user", "alice")) {
+		t.Fatalf("expected the annotation as attribute, got %v", parent.Attributes())
+	}
+	if !hasAttribute(child, attribute.String("tenant", "a")) {
+		t.Fatalf("expected the trace metadata as attribute, got %v", child.Attributes())
+	}
+	if child.Status().Code != codes.Error {
+		t.Fatalf("expected an error status, got %v", child.Status())
+	}
+}
+
+func TestBridgeRemoteParent(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	r := monkit.NewRegistry()
+	defer New(tracer).Register(r)()
+
+	ctx := context.Background()
+	r.ScopeNamed("bridge").ContinueTrace(&ctx, 1234, 5678, true, nil)(nil)
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(ended))
+	}
+	parent := ended[0].Parent()
+	if !parent.IsRemote() || parent.SpanID() != (trace.SpanID{0, 0, 0, 0, 0, 0, 0x16, 0x2e}) ||
+		parent.TraceID() != (trace.TraceID{15: 0xd2, 14: 0x04}) {
+		t.Fatalf("unexpected parent %v", parent)
+	}
+}
+
+func TestBridgeLinkTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	r := monkit.NewRegistry()
+	defer New(tracer).Register(r)()
+
+	ctx := context.Background()
+	func() {
+		defer r.ScopeNamed("bridge").LinkTrace(&ctx, 1234, 5678, true, nil)(nil)
+		defer r.ScopeNamed("bridge").Task()(&ctx)(nil)
+	}()
+
+	ended := recorder.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(ended))
+	}
+	child, root := ended[0], ended[1]
+	if root.Parent().IsValid() {
+		t.Fatalf("unexpected parent %v", root.Parent())
+	}
+	links := root.Links()
+	if len(links) != 1 || !links[0].SpanContext.IsRemote() ||
+		links[0].SpanContext.SpanID() != (trace.SpanID{0, 0, 0, 0, 0, 0, 0x16, 0x2e}) ||
+		links[0].SpanContext.TraceID() != (trace.TraceID{15: 0xd2, 14: 0x04}) {
+		t.Fatalf("unexpected links %v", links)
+	}
+	if len(child.Links()) != 0 {
+		t.Fatalf("unexpected child links %v", child.Links())
+	}
+}
+
+func TestBridgeAcrossProcesses(t *testing.T) {
+	newProcess := func() (*monkit.Registry, *tracetest.SpanRecorder, func()) {
+		recorder := tracetest.NewSpanRecorder()
+		tracer := sdktrace.NewTracerProvider(
+			sdktrace.WithSpanProcessor(recorder),
+			sdktrace.WithIDGenerator(IDGenerator())).Tracer("test")
+		r := monkit.NewRegistry()
+		return r, recorder, New(tracer).Register(r)
+	}
+	upstream, upstreamSpans, cancel := newProcess()
+	defer cancel()
+	downstream, downstreamSpans, cancel := newProcess()
+	defer cancel()
+
+	ctx := context.Background()
+	func() {
+		defer upstream.ScopeNamed("up").Task()(&ctx)(nil)
+		s := monkit.SpanFromCtx(ctx)
+
+		// what a client would propagate to the downstream process.
+		remote := context.Background()
+		defer downstream.ScopeNamed("down").ContinueTrace(&remote, s.Trace().Id(), s.Id(), true, nil)(nil)
+
+		// plain OpenTelemetry spans get ids of their own.
+		_, otelChild := otel(ctx).Start(ctx, "otel-child")
+		otelChild.End()
+		if otelChild.SpanContext().SpanID() == spanID(s.Id()) {
+			t.Fatal("expected a new span id for a plain OpenTelemetry span")
+		}
+	}()
+
+	up, down := upstreamSpans.Ended(), downstreamSpans.Ended()
+	if len(up) != 2 || len(down) != 1 {
+		t.Fatalf("expected 2 upstream and 1 downstream span, got %d and %d", len(up), len(down))
+	}
+	upSpan := up[1]
+	if upSpan.Name() != "up.TestBridgeAcrossProcesses.func2" {
+		t.Fatalf("unexpected upstream span %q", upSpan.Name())
+	}
+	if down[0].Parent().SpanID() != upSpan.SpanContext().SpanID() ||
+		down[0].SpanContext().TraceID() != upSpan.SpanContext().TraceID() {
+		t.Fatalf("expected the downstream span to be a child of %v, got parent %v",
+			upSpan.SpanContext(), down[0].Parent())
+	}
+}
+
+func otel(ctx context.Context) trace.Tracer {
+	return trace.SpanFromContext(ctx).TracerProvider().Tracer("test")
+}
+
+func hasAttribute(s sdktrace.ReadOnlySpan, kv attribute.KeyValue) bool {
+	for _, a := range s.Attributes() {
+		if a == kv {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+/*
+Package otelbridge mirrors monkit Spans into an OpenTelemetry tracer as they
+happen, so that code instrumented with monkit shows up next to code
+instrumented with OpenTelemetry while a codebase migrates from one to the
+other:
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithIDGenerator(otelbridge.IDGenerator()),
+		...)
+	bridge := otelbridge.New(provider.Tracer("monkit"))
+	defer bridge.Register(monkit.Default)()
+
+The IDGenerator gives the OpenTelemetry spans the ids of the monkit Spans, so
+that traces propagated by monkit stay connected in OpenTelemetry.
+
+It lives in its own module so that the core monkit module doesn't depend on
+the OpenTelemetry libraries.
+*/
+package otelbridge // import "github.com/spacemonkeygo/monkit/v3/otelbridge"
@@ -0,0 +1,99 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// SetFuncPackageTags controls whether Funcs named after the calling function,
+// by Scope.Func, Scope.Task and Scope.ContinueTrace, get the package path of
+// the function as a package series tag, and for methods the receiver type as
+// a type tag, such as package=github.com/x/y,type=Server for (*Server).Run.
+// The tags come after those passed by the caller. It only affects Funcs
+// created afterwards, and as the tags are part of the Func's series, turning
+// it on or off changes the series of the Funcs of every Scope, so it should be
+// set once on startup. It is off by default.
+func (r *Registry) SetFuncPackageTags(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&r.packageTags, v)
+}
+
+// funcPackageTags appends the package and type tags of the fully qualified
+// function name fullName to tags, if enabled by SetFuncPackageTags.
+func (r *Registry) funcPackageTags(fullName string, tags []SeriesTag) []SeriesTag {
+	if atomic.LoadInt32(&r.packageTags) == 0 || fullName == "" {
+		return tags
+	}
+	pkg, typ := splitFuncName(fullName)
+	if pkg == "" {
+		return tags
+	}
+	tags = append(tags[:len(tags):len(tags)], NewSeriesTag("package", pkg))
+	if typ != "" {
+		tags = append(tags, NewSeriesTag("type", typ))
+	}
+	return tags
+}
+
+// splitFuncName returns the package path and, for methods, the receiver type
+// of a fully qualified function name as reported by the runtime:
+//
+//	"github.com/x/y.(*Server).Run.func1"   -> "github.com/x/y", "Server"
+//	"github.com/x/y.Server.Close"          -> "github.com/x/y", "Server"
+//	"github.com/x/y.(*List[...]).Push"     -> "github.com/x/y", "List"
+//	"gopkg.in/yaml%2ev3.(*Decoder).Decode" -> "gopkg.in/yaml.v3", "Decoder"
+//	"main.DoThings.func1"                  -> "main", ""
+func splitFuncName(fullName string) (pkg, typ string) {
+	lastSlash := strings.LastIndexByte(fullName, '/')
+	dot := strings.IndexByte(fullName[lastSlash+1:], '.')
+	if dot < 0 {
+		return "", ""
+	}
+	// dots in the last element of the package path are escaped as %2e.
+	pkg = strings.ReplaceAll(fullName[:lastSlash+1+dot], "%2e", ".")
+	rest := fullName[lastSlash+1+dot+1:]
+
+	// type parameters are reported as [...], which contains dots.
+	if i := strings.Index(rest, "[...]"); i >= 0 {
+		rest = rest[:i] + rest[i+len("[...]"):]
+	}
+	first, second, ok := strings.Cut(rest, ".")
+	if !ok || second == "" || isClosureName(second) {
+		return pkg, ""
+	}
+	typ = strings.TrimSuffix(strings.TrimPrefix(first, "(*"), ")")
+	return pkg, typ
+}
+
+// isClosureName returns whether the part of a function name after a dot is
+// the name of a closure, such as func1, or a numbered init function.
+func isClosureName(name string) bool {
+	name, _, _ = strings.Cut(name, ".")
+	name = strings.TrimPrefix(name, "func")
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
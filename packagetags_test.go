@@ -0,0 +1,82 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSplitFuncName(t *testing.T) {
+	for _, test := range []struct {
+		in, pkg, typ string
+	}{
+		{"", "", ""},
+		{"main", "", ""},
+		{"main.DoThings", "main", ""},
+		{"main.DoThings.func1", "main", ""},
+		{"main.DoThings.func1.2", "main", ""},
+		{"main.init.0", "main", ""},
+		{"github.com/x/y.(*Server).Run", "github.com/x/y", "Server"},
+		{"github.com/x/y.(*Server).Run.func1", "github.com/x/y", "Server"},
+		{"github.com/x/y.Server.Close", "github.com/x/y", "Server"},
+		{"github.com/x/y.(*List[...]).Push", "github.com/x/y", "List"},
+		{"github.com/x/y.Map[...]", "github.com/x/y", ""},
+		{"gopkg.in/yaml%2ev3.(*Decoder).Decode", "gopkg.in/yaml.v3", "Decoder"},
+	} {
+		pkg, typ := splitFuncName(test.in)
+		if pkg != test.pkg || typ != test.typ {
+			t.Errorf("%q: got %q %q, expected %q %q", test.in, pkg, typ, test.pkg, test.typ)
+		}
+	}
+}
+
+type packageTagsReceiver struct{ mon *Scope }
+
+func (p *packageTagsReceiver) method(ctx context.Context) {
+	defer p.mon.Task()(&ctx)(nil)
+}
+
+func packageTagsFunction(ctx context.Context, mon *Scope) {
+	defer mon.Task(NewSeriesTag("k", "v"))(&ctx)(nil)
+}
+
+func TestFuncPackageTags(t *testing.T) {
+	r := NewRegistry()
+	r.SetFuncPackageTags(true)
+	mon := r.ScopeNamed("tags")
+
+	ctx := context.Background()
+	(&packageTagsReceiver{mon: mon}).method(ctx)
+	packageTagsFunction(ctx, mon)
+
+	funcs := map[string]*Func{}
+	mon.Funcs(func(f *Func) { funcs[f.ShortName()] = f })
+
+	const pkg = "github.com/spacemonkeygo/monkit/v3"
+	method := funcs["(*packageTagsReceiver).method"]
+	if method == nil {
+		t.Fatalf("missing method Func in %v", funcs)
+	}
+	if tags := method.key.Tags; tags.Get("package") != pkg || tags.Get("type") != "packageTagsReceiver" {
+		t.Fatalf("unexpected method tags %v", tags.All())
+	}
+	function := funcs["packageTagsFunction"]
+	if function == nil {
+		t.Fatalf("missing function Func in %v", funcs)
+	}
+	if tags := function.key.Tags; tags.Get("package") != pkg || tags.Get("k") != "v" {
+		t.Fatalf("unexpected function tags %v", tags.All())
+	}
+	if _, ok := function.key.Tags.All()["type"]; ok {
+		t.Fatalf("expected no type tag for a function, got %v", function.key.Tags.All())
+	}
+
+	// without the setting the Funcs stay as they were.
+	r = NewRegistry()
+	mon = r.ScopeNamed("tags")
+	packageTagsFunction(ctx, mon)
+	mon.Funcs(func(f *Func) {
+		if f.key.Tags.Get("package") != "" {
+			t.Fatalf("unexpected tags %v", f.key.Tags.All())
+		}
+	})
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// SetPanicStacks controls whether Spans that end in a panic record the stack
+// of the panicking goroutine as a panic.stack annotation. The panic value is
+// always recorded as panic.value. Capturing the stack is comparatively
+// expensive, so it is off by default.
+func (r *Registry) SetPanicStacks(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&r.panicStacks, v)
+}
+
+// annotatePanic is called from the deferred Span finish path, while the
+// panic is still unwinding, so that debug.Stack includes where it started.
+func (s *Span) annotatePanic(rec interface{}) {
+	value := fmt.Sprint(rec)
+	var stack string
+	if atomic.LoadInt32(&s.f.scope.r.panicStacks) != 0 {
+		stack = string(debug.Stack())
+	}
+	s.mtx.Lock()
+	s.addAnnotation("panic.value", value)
+	if stack != "" {
+		s.addAnnotation("panic.stack", stack)
+	}
+	s.mtx.Unlock()
+}
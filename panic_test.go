@@ -0,0 +1,50 @@
+package monkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func panickingTask(mon *Scope, span **Span) {
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+	*span = SpanFromCtx(ctx)
+	panic("kaboom")
+}
+
+func TestPanicAnnotations(t *testing.T) {
+	for _, stacks := range []bool{false, true} {
+		r := NewRegistry()
+		r.SetPanicStacks(stacks)
+		mon := r.ScopeNamed("panics")
+
+		var span *Span
+		func() {
+			defer func() {
+				if rec := recover(); rec != "kaboom" {
+					t.Fatalf("expected the panic to propagate, got %v", rec)
+				}
+			}()
+			panickingTask(mon, &span)
+		}()
+
+		annotations := map[string]string{}
+		for _, a := range span.Annotations() {
+			annotations[a.Name] = a.Value
+		}
+		if annotations["panic.value"] != "kaboom" {
+			t.Fatalf("unexpected panic.value %q", annotations["panic.value"])
+		}
+		stack, exists := annotations["panic.stack"]
+		if exists != stacks {
+			t.Fatalf("expected panic.stack to exist: %v, got %v", stacks, exists)
+		}
+		if stacks && !strings.Contains(stack, "panickingTask") {
+			t.Fatalf("stack does not include the panic site:\n%s", stack)
+		}
+		if mon.FuncNamed("panickingTask").Panics() != 1 {
+			t.Fatal("expected the panic to be counted")
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/collect"
+)
+
+// AttachmentsToJSON writes the attachments of spans (see monkit.Span.Attach)
+// to w as a JSON list with an element per attachment, holding the ids of its
+// Span and Trace, its name, and its data in base64. Spans without attachments
+// are left out. The attachments are kept out of SpansToJSON so that the
+// spans stay small.
+func AttachmentsToJSON(w io.Writer, spans []*collect.FinishedSpan) error {
+	lw := newListWriter(w)
+	for _, s := range spans {
+		for _, a := range s.Span.Attachments() {
+			lw.elem(struct {
+				SpanId  int64  `json:"span_id"`
+				TraceId int64  `json:"trace_id"`
+				Name    string `json:"name"`
+				Data    []byte `json:"data"`
+			}{s.Span.Id(), s.Span.Trace().Id(), a.Name, a.Data})
+		}
+	}
+	return lw.done()
+}
+
+// TraceQueryAttachments uses WatchForSpans to write the attachments of all
+// Spans from 'reg' matching 'matcher' to 'w' in the format of
+// AttachmentsToJSON.
+func TraceQueryAttachments(reg *monkit.Registry, w io.Writer,
+	matcher func(*monkit.Span) bool, minDuration time.Duration) error {
+
+	var spans []*collect.FinishedSpan
+	var err error
+
+	if minDuration > 0 {
+		spans, err = watchForSpansWithMinDuration(
+			context.TODO(), reg, w, matcher, minDuration, []byte("\n"))
+	} else {
+		spans, err = watchForSpansWithKeepalive(
+			context.TODO(), reg, w, matcher, []byte("\n"))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return AttachmentsToJSON(w, spans)
+}
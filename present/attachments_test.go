@@ -0,0 +1,57 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/collect"
+)
+
+func TestAttachmentsToJSON(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("test")
+
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+
+	var attached *monkit.Span
+	spans := collect.CollectSpans(ctx, func(ctx context.Context) {
+		defer mon.Task()(&ctx)(nil)
+		attached = monkit.SpanFromCtx(ctx)
+		attached.Attach("request", []byte{0, 1, 2})
+	})
+
+	var buf bytes.Buffer
+	if err := AttachmentsToJSON(&buf, spans); err != nil {
+		t.Fatal(err)
+	}
+	var out []struct {
+		SpanId  int64  `json:"span_id"`
+		TraceId int64  `json:"trace_id"`
+		Name    string `json:"name"`
+		Data    []byte `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(out))
+	}
+	if out[0].SpanId != attached.Id() || out[0].TraceId != attached.Trace().Id() ||
+		out[0].Name != "request" || !bytes.Equal(out[0].Data, []byte{0, 1, 2}) {
+		t.Fatalf("unexpected attachment %+v", out[0])
+	}
+
+	buf.Reset()
+	if err := SpansToJSON(&buf, spans); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("AAEC")) || bytes.Contains(buf.Bytes(), []byte("request")) {
+		t.Fatalf("attachment leaked into the spans: %s", buf.Bytes())
+	}
+}
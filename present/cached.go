@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/monotime"
+)
+
+// CachedStats wraps source so that its Stats method is called at most once
+// per ttl, replaying the cached values for calls in between. This keeps
+// expensive sources, such as a monkit.StatSourceFunc that walks a large
+// structure or the environment stats, from being recomputed for every
+// consumer of a single scrape. Pass the result wherever a monkit.StatSource
+// is expected, e.g. to Scope.Chain.
+func CachedStats(source monkit.StatSource, ttl time.Duration) monkit.StatSource {
+	return &cachedStats{source: source, ttl: ttl, now: monotime.Now}
+}
+
+type cachedStats struct {
+	source monkit.StatSource
+	ttl    time.Duration
+	now    func() time.Time
+
+	mtx     sync.Mutex
+	updated time.Time
+	values  []cachedValue
+}
+
+type cachedValue struct {
+	key   monkit.SeriesKey
+	field string
+	val   float64
+}
+
+// Stats implements monkit.StatSource.
+func (c *cachedStats) Stats(cb func(key monkit.SeriesKey, field string, val float64)) {
+	for _, v := range c.load() {
+		cb(v.key, v.field, v.val)
+	}
+}
+
+func (c *cachedStats) load() []cachedValue {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	now := c.now()
+	if c.values != nil && now.Sub(c.updated) < c.ttl {
+		return c.values
+	}
+	// a fresh slice, since callers may still be iterating over the old one.
+	values := []cachedValue{}
+	c.source.Stats(func(key monkit.SeriesKey, field string, val float64) {
+		values = append(values, cachedValue{key: key, field: field, val: val})
+	})
+	c.values, c.updated = values, now
+	return values
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestCachedStats(t *testing.T) {
+	calls := 0
+	source := monkit.StatSourceFunc(func(cb func(key monkit.SeriesKey, field string, val float64)) {
+		calls++
+		cb(monkit.NewSeriesKey("expensive"), "value", float64(calls))
+	})
+
+	now := time.Unix(1000, 0)
+	cached := CachedStats(source, time.Minute)
+	cached.(*cachedStats).now = func() time.Time { return now }
+
+	r := monkit.NewRegistry()
+	r.ScopeNamed("cached").Chain(cached)
+
+	for i := 0; i < 3; i++ {
+		if got := monkit.Collect(r)["expensive,scope=cached value"]; got != 1 {
+			t.Fatalf("expected cached value 1, got %v", got)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call within the ttl, got %d", calls)
+	}
+
+	now = now.Add(time.Minute)
+	if got := monkit.Collect(r)["expensive,scope=cached value"]; got != 2 {
+		t.Fatalf("expected refreshed value 2, got %v", got)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls after the ttl, got %d", calls)
+	}
+}
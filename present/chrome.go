@@ -0,0 +1,153 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/collect"
+)
+
+// chromeEvent is an event of the Trace Event Format understood by
+// chrome://tracing and Perfetto.
+type chromeEvent struct {
+	Name  string                 `json:"name,omitempty"`
+	Cat   string                 `json:"cat,omitempty"`
+	Phase string                 `json:"ph"`
+	TS    float64                `json:"ts"`
+	PID   int                    `json:"pid"`
+	TID   int                    `json:"tid"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+
+	// for sorting only.
+	duration time.Duration
+}
+
+// ChromeTrace writes spans to w as JSON in the Trace Event Format, which can
+// be loaded into chrome://tracing or https://ui.perfetto.dev to look at the
+// spans on a timeline. Every span becomes a pair of begin and end events,
+// with its annotations, arguments and error as event arguments. Each trace
+// is shown as its own process, and the spans are spread over threads like
+// the rows of SpansToSVG, so that spans running concurrently don't overlap.
+func ChromeTrace(spans []*collect.FinishedSpan, w io.Writer) error {
+	events := make([]chromeEvent, 0, 2*len(spans))
+	var minStart time.Time
+	for _, s := range spans {
+		if start := s.Span.Start(); minStart.IsZero() || start.Before(minStart) {
+			minStart = start
+		}
+	}
+	micros := func(t time.Time) float64 {
+		return float64(t.Sub(minStart).Nanoseconds()) / 1e3
+	}
+
+	pids := map[*monkit.Trace]int{}
+	spanTree, _ := computeLayoutInformation(spans)
+	for _, s := range spans {
+		trace := s.Span.Trace()
+		pid, ok := pids[trace]
+		if !ok {
+			pid = len(pids) + 1
+			pids[trace] = pid
+			events = append(events, chromeEvent{
+				Name:  "process_name",
+				Phase: "M",
+				PID:   pid,
+				Args:  map[string]interface{}{"name": "trace " + monkit.FormatID(trace.FullId(), monkit.IDFormatHex)},
+			})
+		}
+
+		args := map[string]interface{}{"span_id": monkit.FormatTraceID(s.Span.Id(), monkit.IDFormatHex)}
+		for _, a := range s.Annotations() {
+			args[a.Name] = a.Value
+		}
+		if spanArgs := s.Span.Args(); len(spanArgs) > 0 {
+			// formatted like SpansToJSON does, so both exports agree.
+			formatted := make([]string, 0, len(spanArgs))
+			for _, arg := range spanArgs {
+				formatted = append(formatted, fmt.Sprintf("%#v", arg))
+			}
+			args["args"] = formatted
+		}
+		if s.Err != nil {
+			args["error"] = s.Err.Error()
+		}
+		if s.Panicked {
+			args["panicked"] = true
+		}
+
+		tid := spanTree[s.Span.Id()].Row
+		duration := s.Finish.Sub(s.Span.Start())
+		events = append(events,
+			chromeEvent{
+				Name:     s.Span.Func().FullName(),
+				Cat:      "monkit",
+				Phase:    "B",
+				TS:       micros(s.Span.Start()),
+				PID:      pid,
+				TID:      tid,
+				Args:     args,
+				duration: duration,
+			},
+			chromeEvent{
+				Phase:    "E",
+				TS:       micros(s.Finish),
+				PID:      pid,
+				TID:      tid,
+				duration: duration,
+			})
+	}
+
+	// begin and end events of a thread have to nest, so at the same time
+	// metadata comes first, spans end before others begin, and outer spans
+	// begin first and end last.
+	phaseOrder := map[string]int{"M": 0, "E": 1, "B": 2}
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if a.TS != b.TS {
+			return a.TS < b.TS
+		}
+		if a.Phase != b.Phase {
+			return phaseOrder[a.Phase] < phaseOrder[b.Phase]
+		}
+		if a.Phase == "B" {
+			return a.duration > b.duration
+		}
+		return a.duration < b.duration
+	})
+
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []chromeEvent `json:"traceEvents"`
+		DisplayTimeUnit string        `json:"displayTimeUnit"`
+	}{events, "ms"})
+}
+
+// TraceQueryChrome uses WatchForSpans to write all Spans from 'reg' matching
+// 'matcher' to 'w' in the format of ChromeTrace.
+func TraceQueryChrome(reg *monkit.Registry, w io.Writer,
+	matcher func(*monkit.Span) bool, minDuration time.Duration) error {
+
+	var spans []*collect.FinishedSpan
+	var err error
+
+	if minDuration > 0 {
+		spans, err = watchForSpansWithMinDuration(
+			context.TODO(), reg, w, matcher, minDuration, []byte("\n"))
+	} else {
+		spans, err = watchForSpansWithKeepalive(
+			context.TODO(), reg, w, matcher, []byte("\n"))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return ChromeTrace(spans, w)
+}
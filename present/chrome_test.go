@@ -0,0 +1,152 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/collect"
+)
+
+func TestChromeTrace(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("chrome")
+
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+
+	spans := collect.CollectSpans(ctx, func(ctx context.Context) {
+		defer mon.TaskNamed("parent")(&ctx)(nil)
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx := ctx
+				err := errors.New("failed")
+				defer mon.TaskNamed("child")(&ctx)(&err)
+				time.Sleep(time.Millisecond)
+			}()
+		}
+		wg.Wait()
+	})
+
+	var buf bytes.Buffer
+	if err := ChromeTrace(spans, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		TraceEvents []struct {
+			Name  string                 `json:"name"`
+			Phase string                 `json:"ph"`
+			TS    float64                `json:"ts"`
+			PID   int                    `json:"pid"`
+			TID   int                    `json:"tid"`
+			Args  map[string]interface{} `json:"args"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	type thread struct{ pid, tid int }
+	stacks := map[thread][]string{}
+	childThreads := map[thread]bool{}
+	begins, errs := 0, 0
+	lastTS := 0.0
+	for _, e := range out.TraceEvents {
+		th := thread{e.PID, e.TID}
+		switch e.Phase {
+		case "M":
+			continue
+		case "B":
+			begins++
+			if e.Args["error"] == "failed" {
+				errs++
+			}
+			if e.Name == "chrome.child" {
+				childThreads[th] = true
+			}
+			stacks[th] = append(stacks[th], e.Name)
+		case "E":
+			if len(stacks[th]) == 0 {
+				t.Fatalf("end event without begin on %v", th)
+			}
+			stacks[th] = stacks[th][:len(stacks[th])-1]
+		default:
+			t.Fatalf("unexpected phase %q", e.Phase)
+		}
+		if e.TS < lastTS {
+			t.Fatalf("events out of order: %v after %v", e.TS, lastTS)
+		}
+		lastTS = e.TS
+	}
+	for th, stack := range stacks {
+		if len(stack) != 0 {
+			t.Fatalf("unfinished spans %v on %v", stack, th)
+		}
+	}
+	if begins != len(spans) || begins != 4 || errs != 2 {
+		t.Fatalf("expected 4 spans with 2 errors, got %d spans with %d errors", begins, errs)
+	}
+	if len(childThreads) != 2 {
+		t.Fatalf("expected the concurrent children on separate threads, got %v", childThreads)
+	}
+}
+
+func TestChromeTraceArgs(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("chrome")
+
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+
+	spans := collect.CollectSpans(ctx, func(ctx context.Context) {
+		// none of these encode as JSON themselves.
+		defer mon.TaskNamed("args")(&ctx, math.NaN(), make(chan int), func() {})(nil)
+	})
+
+	var buf bytes.Buffer
+	if err := ChromeTrace(spans, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		TraceEvents []struct {
+			Phase string `json:"ph"`
+			Args  struct {
+				Args   []string `json:"args"`
+				SpanID string   `json:"span_id"`
+			} `json:"args"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(out.TraceEvents) == 0 || out.TraceEvents[0].Phase != "M" {
+		t.Fatalf("expected the metadata first, got %+v", out.TraceEvents)
+	}
+	for _, e := range out.TraceEvents {
+		if e.Phase == "B" && len(e.Args.Args) > 0 {
+			if len(e.Args.Args) != 3 || e.Args.Args[0] != `"NaN"` {
+				t.Fatalf("unexpected args %q", e.Args.Args)
+			}
+			// ids are formatted like everywhere else.
+			for _, s := range spans {
+				if id := s.Span.Id(); s.Span.Func().ShortName() == "args" &&
+					e.Args.SpanID != monkit.FormatTraceID(id, monkit.IDFormatHex) {
+					t.Fatalf("unexpected span id %q for %d", e.Args.SpanID, id)
+				}
+			}
+			return
+		}
+	}
+	t.Fatalf("no begin event with args in %s", buf.String())
+}
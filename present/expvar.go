@@ -0,0 +1,66 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"expvar"
+	"math"
+	"sync"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+var (
+	expvarMtx sync.Mutex
+	expvars   = map[string]*expvarStats{}
+)
+
+type expvarStats struct {
+	mtx sync.Mutex
+	r   *monkit.Registry
+	kf  KeyFormatter
+}
+
+func (e *expvarStats) value() interface{} {
+	e.mtx.Lock()
+	r, kf := e.r, e.kf
+	e.mtx.Unlock()
+
+	vals := map[string]float64{}
+	r.Stats(func(key monkit.SeriesKey, field string, val float64) {
+		if !math.IsNaN(val) && !math.IsInf(val, 0) {
+			vals[kf(key, field)] = val
+		}
+	})
+	return vals
+}
+
+// PublishExpvar publishes the stats of r as the expvar variable "monkit", so
+// that they show up at /debug/vars next to the ones of the runtime. The
+// variable is an object of every value in r, named with DotKeys, and is
+// computed whenever it is read, so series created later show up as well.
+// Distributions contribute their summary fields, such as avg and r99, like
+// everywhere else. NaN and infinite values are left out, as JSON can't
+// represent them. Publishing again replaces the Registry behind the
+// variable; see PublishExpvarNamed.
+func PublishExpvar(r *monkit.Registry) {
+	PublishExpvarNamed("monkit", r, DotKeys)
+}
+
+// PublishExpvarNamed is like PublishExpvar, but publishes the stats of r as
+// the expvar variable name, named with kf. Like expvar.Publish, it panics if
+// name was already published other than by PublishExpvarNamed.
+func PublishExpvarNamed(name string, r *monkit.Registry, kf KeyFormatter) {
+	expvarMtx.Lock()
+	defer expvarMtx.Unlock()
+	if e, ok := expvars[name]; ok {
+		e.mtx.Lock()
+		e.r, e.kf = r, kf
+		e.mtx.Unlock()
+		return
+	}
+	e := &expvarStats{r: r, kf: kf}
+	expvar.Publish(name, expvar.Func(e.value))
+	expvars[name] = e
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"encoding/json"
+	"expvar"
+	"math"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("example.com/pkg")
+	PublishExpvar(r)
+
+	// series created after publishing show up too.
+	mon.Counter("requests").Inc(3)
+	mon.FloatVal("ratio").Observe(math.NaN())
+
+	v := expvar.Get("monkit")
+	if v == nil {
+		t.Fatal("monkit was not published")
+	}
+	var vals map[string]float64
+	if err := json.Unmarshal([]byte(v.String()), &vals); err != nil {
+		t.Fatalf("invalid expvar %q: %v", v.String(), err)
+	}
+	if got, ok := vals["requests.example_com_pkg.value"]; !ok || got != 3 {
+		t.Fatalf("expected 3 requests, got %v %v", got, ok)
+	}
+	if _, ok := vals["ratio.example_com_pkg.count"]; !ok {
+		t.Fatal("expected the summary fields of the distribution")
+	}
+	if _, ok := vals["ratio.example_com_pkg.sum"]; ok {
+		t.Fatal("expected the NaN sum to be left out")
+	}
+}
+
+func TestPublishExpvarAgain(t *testing.T) {
+	first, second := monkit.NewRegistry(), monkit.NewRegistry()
+	first.ScopeNamed("first").Counter("requests").Inc(1)
+	second.ScopeNamed("second").Counter("requests").Inc(2)
+
+	PublishExpvarNamed("monkit_again", first, DotKeys)
+	// publishing again replaces the registry instead of panicking.
+	PublishExpvarNamed("monkit_again", second, DotKeys)
+
+	var vals map[string]float64
+	if err := json.Unmarshal([]byte(expvar.Get("monkit_again").String()), &vals); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := vals["requests.first.value"]; ok || vals["requests.second.value"] != 2 {
+		t.Fatalf("expected only the second registry, got %v", vals)
+	}
+}
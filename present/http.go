@@ -15,7 +15,10 @@
 package present
 
 import (
+	"compress/gzip"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/spacemonkeygo/monkit/v3"
 )
@@ -26,7 +29,8 @@ type handler struct {
 
 // HTTP makes an http.Handler out of a Registry. It serves paths using this
 // package's FromRequest request router. Usually HTTP is called with the
-// Default registry.
+// Default registry. Responses are gzip compressed for clients that accept
+// it.
 func HTTP(r *monkit.Registry) http.Handler {
 	return handler{Registry: r}
 }
@@ -38,5 +42,34 @@ func (h handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 	w.Header().Set("Content-Type", contentType)
-	p(w)
+	w.Header().Add("Vary", "Accept-Encoding")
+	if !acceptsGzip(req.Header.Get("Accept-Encoding")) {
+		p(w)
+		return
+	}
+	w.Header().Set("Content-Encoding", "gzip")
+	gz := gzip.NewWriter(w)
+	_ = p(gz)
+	_ = gz.Close()
+}
+
+// acceptsGzip returns whether an Accept-Encoding header value allows a gzip
+// response.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		params = strings.ReplaceAll(params, " ", "")
+		if strings.HasPrefix(params, "q=") {
+			v, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+			if err == nil && v == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
 }
@@ -0,0 +1,59 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestHTTPGzip(t *testing.T) {
+	r := monkit.NewRegistry()
+	r.ScopeNamed("example.com/a").Counter("calls").Inc(3)
+
+	var expected bytes.Buffer
+	if err := StatsText(r, &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		acceptEncoding string
+		gzipped        bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip;q=0.5", true},
+		{"gzip;q=0", false},
+		{"br", false},
+	} {
+		req := httptest.NewRequest("GET", "/stats/text", nil)
+		if test.acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", test.acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		HTTP(r).ServeHTTP(rec, req)
+
+		body := rec.Body.Bytes()
+		if gzipped := rec.Header().Get("Content-Encoding") == "gzip"; gzipped != test.gzipped {
+			t.Fatalf("%q: expected gzipped=%v, got %v", test.acceptEncoding, test.gzipped, gzipped)
+		}
+		if test.gzipped {
+			zr, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body, err = io.ReadAll(zr); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !bytes.Equal(body, expected.Bytes()) {
+			t.Fatalf("%q: unexpected body:\n%s\nexpected:\n%s", test.acceptEncoding, body, expected.Bytes())
+		}
+	}
+}
@@ -33,8 +33,12 @@ func formatSpan(s *monkit.Span) interface{} {
 			Name    string `json:"name"`
 		} `json:"func"`
 		Trace struct {
-			Id int64 `json:"id"`
+			Id          int64             `json:"id"`
+			RootName    string            `json:"root_name"`
+			ParentTrace *monkit.TraceLink `json:"parent_trace,omitempty"`
+			Metadata    map[string]string `json:"metadata,omitempty"`
 		} `json:"trace"`
+		Kind        string     `json:"kind"`
 		Start       int64      `json:"start"`
 		Elapsed     int64      `json:"elapsed"`
 		Orphaned    bool       `json:"orphaned"`
@@ -49,6 +53,11 @@ func formatSpan(s *monkit.Span) interface{} {
 	js.Func.Package = s.Func().Scope().Name()
 	js.Func.Name = s.Func().ShortName()
 	js.Trace.Id = s.Trace().Id()
+	js.Trace.RootName = s.Trace().RootName()
+	if link, ok := s.Trace().ParentTrace(); ok {
+		js.Trace.ParentTrace = &link
+	}
+	js.Trace.Metadata = s.Trace().Metadata()
 	js.Start = s.Start().UnixNano()
 	js.Elapsed = time.Since(s.Start()).Nanoseconds()
 	js.Orphaned = s.Orphaned()
@@ -73,10 +82,16 @@ func formatFinishedSpan(s *collect.FinishedSpan) interface{} {
 			Name    string `json:"name"`
 		} `json:"func"`
 		Trace struct {
-			Id int64 `json:"id"`
+			Id          int64             `json:"id"`
+			RootName    string            `json:"root_name"`
+			ParentTrace *monkit.TraceLink `json:"parent_trace,omitempty"`
+			Metadata    map[string]string `json:"metadata,omitempty"`
 		} `json:"trace"`
+		Kind        string     `json:"kind"`
 		Start       int64      `json:"start"`
 		Finish      int64      `json:"finish"`
+		StartTime   string     `json:"start_time"`
+		EndTime     string     `json:"end_time"`
 		Orphaned    bool       `json:"orphaned"`
 		Err         string     `json:"err"`
 		Panicked    bool       `json:"panicked"`
@@ -84,14 +99,22 @@ func formatFinishedSpan(s *collect.FinishedSpan) interface{} {
 		Annotations [][]string `json:"annotations"`
 	}{}
 	js.Id = s.Span.Id()
-	if parent_id, ok := s.Span.ParentId(); ok {
+	if parent_id, ok := s.ParentId(); ok {
 		js.ParentId = &parent_id
 	}
 	js.Func.Package = s.Span.Func().Scope().Name()
 	js.Func.Name = s.Span.Func().ShortName()
 	js.Trace.Id = s.Span.Trace().Id()
+	js.Trace.RootName = s.Span.Trace().RootName()
+	if link, ok := s.Span.Trace().ParentTrace(); ok {
+		js.Trace.ParentTrace = &link
+	}
+	js.Trace.Metadata = s.Span.Trace().Metadata()
+	js.Kind = s.Span.Kind().String()
 	js.Start = s.Span.Start().UnixNano()
 	js.Finish = s.Finish.UnixNano()
+	js.StartTime = s.Span.Trace().WallTime(s.Span.Start()).Format(time.RFC3339Nano)
+	js.EndTime = s.Span.Trace().WallTime(s.Finish).Format(time.RFC3339Nano)
 	js.Orphaned = s.Span.Orphaned()
 	if s.Err != nil {
 		errstr := s.Err.Error()
@@ -102,8 +125,9 @@ func formatFinishedSpan(s *collect.FinishedSpan) interface{} {
 	for _, arg := range s.Span.Args() {
 		js.Args = append(js.Args, fmt.Sprintf("%#v", arg))
 	}
-	js.Annotations = make([][]string, 0, len(s.Span.Annotations()))
-	for _, annotation := range s.Span.Annotations() {
+	annotations := s.Annotations()
+	js.Annotations = make([][]string, 0, len(annotations))
+	for _, annotation := range annotations {
 		js.Annotations = append(js.Annotations,
 			[]string{annotation.Name, annotation.Value})
 	}
@@ -0,0 +1,239 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/collect"
+)
+
+func TestSpansToJSONTimestamps(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("test")
+
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+
+	spans := collect.CollectSpans(ctx, func(ctx context.Context) {
+		defer mon.Task()(&ctx)(nil)
+		time.Sleep(time.Millisecond)
+	})
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	var buf bytes.Buffer
+	if err := SpansToJSON(&buf, spans); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []struct {
+		StartTime string `json:"start_time"`
+		EndTime   string `json:"end_time"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range out {
+		start, err := time.Parse(time.RFC3339Nano, s.StartTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		end, err := time.Parse(time.RFC3339Nano, s.EndTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if end.Before(start) {
+			t.Fatalf("end %v before start %v", end, start)
+		}
+		if time.Since(start) > time.Minute {
+			t.Fatalf("start %v is not anchored on the wall clock", start)
+		}
+	}
+}
+
+func TestSpansToJSONKind(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("test")
+
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+
+	spans := collect.CollectSpans(ctx, func(ctx context.Context) {
+		defer mon.Task()(&ctx)(nil)
+		monkit.SpanFromCtx(ctx).SetKind(monkit.SpanKindServer)
+	})
+
+	var buf bytes.Buffer
+	if err := SpansToJSON(&buf, spans); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	kinds := map[string]int{}
+	for _, s := range out {
+		kinds[s.Kind]++
+	}
+	if kinds["server"] != 1 || kinds["internal"] != 1 {
+		t.Fatalf("unexpected kinds %v", kinds)
+	}
+}
+
+func TestSpansToJSONParentTrace(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("test")
+
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+	monkit.SpanFromCtx(ctx).Trace().SetParentTrace(1, 2)
+
+	spans := collect.CollectSpans(ctx, func(ctx context.Context) {
+		defer mon.Task()(&ctx)(nil)
+	})
+
+	var buf bytes.Buffer
+	if err := SpansToJSON(&buf, spans); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []struct {
+		Trace struct {
+			ParentTrace *monkit.TraceLink `json:"parent_trace"`
+		} `json:"trace"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) == 0 {
+		t.Fatal("expected spans")
+	}
+	for _, s := range out {
+		if s.Trace.ParentTrace == nil || *s.Trace.ParentTrace != (monkit.TraceLink{TraceId: 1, SpanId: 2}) {
+			t.Fatalf("unexpected output %s", buf.String())
+		}
+	}
+}
+
+func TestSpansToJSONMetadata(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("test")
+
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+	monkit.SpanFromCtx(ctx).Trace().SetMetadata("tenant", "a")
+
+	spans := collect.CollectSpans(ctx, func(ctx context.Context) {
+		defer mon.Task()(&ctx)(nil)
+	})
+
+	var buf bytes.Buffer
+	if err := SpansToJSON(&buf, spans); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []struct {
+		Trace struct {
+			Metadata map[string]string `json:"metadata"`
+		} `json:"trace"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) == 0 {
+		t.Fatal("expected spans")
+	}
+	for _, s := range out {
+		if s.Trace.Metadata["tenant"] != "a" {
+			t.Fatalf("unexpected output %s", buf.String())
+		}
+	}
+}
+
+func TestSpansToJSONBackfilled(t *testing.T) {
+	f := monkit.NewRegistry().ScopeNamed("logs").FuncNamed("request")
+	trace := monkit.NewTrace(monkit.NewId())
+	collector := collect.NewSpanCollector(nil)
+	trace.ObserveSpans(collector)
+
+	start := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	root := trace.NewSpanAt(f, 0, start)
+	collector.ForceStart(root)
+	child := trace.NewSpanAt(f, root.Id(), start.Add(time.Second))
+	if err := child.FinishAt(nil, start.Add(2*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.FinishAt(nil, start.Add(3*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := SpansToJSON(&buf, collector.Spans()); err != nil {
+		t.Fatal(err)
+	}
+	var out []struct {
+		Id        int64  `json:"id"`
+		ParentId  *int64 `json:"parent_id"`
+		StartTime string `json:"start_time"`
+		EndTime   string `json:"end_time"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(out))
+	}
+	for i, expected := range []struct {
+		id         int64
+		parent     *int64
+		start, end time.Duration
+	}{
+		{root.Id(), nil, 0, 3 * time.Second},
+		{child.Id(), &out[0].Id, time.Second, 2 * time.Second},
+	} {
+		s := out[i]
+		if s.Id != expected.id || (s.ParentId == nil) != (expected.parent == nil) ||
+			(s.ParentId != nil && *s.ParentId != *expected.parent) {
+			t.Fatalf("span %d: unexpected ids %+v", i, s)
+		}
+		spanStart, err1 := time.Parse(time.RFC3339Nano, s.StartTime)
+		spanEnd, err2 := time.Parse(time.RFC3339Nano, s.EndTime)
+		if err1 != nil || err2 != nil ||
+			!spanStart.Equal(start.Add(expected.start)) || !spanEnd.Equal(start.Add(expected.end)) {
+			t.Fatalf("span %d: unexpected times %s - %s", i, s.StartTime, s.EndTime)
+		}
+	}
+}
+
+func TestSpansJSONRootName(t *testing.T) {
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("test")
+
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+	monkit.SpanFromCtx(ctx).Trace().SetRootName("root")
+
+	var buf bytes.Buffer
+	if err := SpansJSON(r, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []struct {
+		Trace struct {
+			RootName string `json:"root_name"`
+		} `json:"trace"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || out[0].Trace.RootName != "root" {
+		t.Fatalf("unexpected output %s", buf.String())
+	}
+}
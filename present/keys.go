@@ -0,0 +1,110 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// KeyFormatter flattens a series key and field into a single name, for
+// backends that don't understand tags. The components of the name are the
+// measurement, then the tag values ordered by tag key, then the field.
+type KeyFormatter func(key monkit.SeriesKey, field string) string
+
+var (
+	// DotKeys joins components with dots, such as
+	// function.Foo.example_com_pkg.successes. Characters other than letters,
+	// digits, '-' and '_' are replaced with '_' so that a component can never
+	// introduce a separator of its own.
+	DotKeys KeyFormatter = func(key monkit.SeriesKey, field string) string {
+		return joinComponents(key, field, ".", isDotSafe)
+	}
+
+	// UnderscoreKeys joins components with underscores, such as
+	// function_Foo_example_com_pkg_successes. Characters other than letters
+	// and digits are replaced with '_'.
+	UnderscoreKeys KeyFormatter = func(key monkit.SeriesKey, field string) string {
+		return joinComponents(key, field, "_", isAlnum)
+	}
+
+	// CamelCaseKeys joins components in camelCase, such as
+	// functionFooExampleComPkgSuccesses. Characters other than letters and
+	// digits separate words and are dropped.
+	CamelCaseKeys KeyFormatter = camelCaseKeys
+)
+
+// KeyFormatterNamed returns the built-in KeyFormatter called "dot",
+// "underscore" or "camel", or false if there is none by that name.
+func KeyFormatterNamed(name string) (KeyFormatter, bool) {
+	switch name {
+	case "dot":
+		return DotKeys, true
+	case "underscore":
+		return UnderscoreKeys, true
+	case "camel":
+		return CamelCaseKeys, true
+	}
+	return nil, false
+}
+
+func keyComponents(key monkit.SeriesKey, field string) []string {
+	tags := key.Tags.All()
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	components := make([]string, 0, len(names)+2)
+	components = append(components, key.Measurement)
+	for _, name := range names {
+		components = append(components, tags[name])
+	}
+	return append(components, field)
+}
+
+func isAlnum(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+func isDotSafe(r rune) bool { return isAlnum(r) || r == '-' || r == '_' }
+
+func joinComponents(key monkit.SeriesKey, field, sep string,
+	safe func(rune) bool) string {
+	var builder strings.Builder
+	for _, component := range keyComponents(key, field) {
+		if component == "" {
+			continue
+		}
+		if builder.Len() > 0 {
+			builder.WriteString(sep)
+		}
+		for _, r := range component {
+			if !safe(r) {
+				r = '_'
+			}
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+func camelCaseKeys(key monkit.SeriesKey, field string) string {
+	var builder strings.Builder
+	for _, component := range keyComponents(key, field) {
+		for _, word := range strings.FieldsFunc(component,
+			func(r rune) bool { return !isAlnum(r) }) {
+			runes := []rune(word)
+			if builder.Len() == 0 {
+				runes[0] = unicode.ToLower(runes[0])
+			} else {
+				runes[0] = unicode.ToUpper(runes[0])
+			}
+			builder.WriteString(string(runes))
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestKeyFormatters(t *testing.T) {
+	key := monkit.NewSeriesKey("function").
+		WithTag("scope", "example.com/pkg").
+		WithTag("name", "Foo")
+
+	for _, test := range []struct {
+		kf       KeyFormatter
+		key      monkit.SeriesKey
+		expected string
+	}{
+		{DotKeys, key, "function.Foo.example_com_pkg.successes"},
+		{UnderscoreKeys, key, "function_Foo_example_com_pkg_successes"},
+		{CamelCaseKeys, key, "functionFooExampleComPkgSuccesses"},
+
+		// components with separators, spaces and other characters that need
+		// escaping.
+		{DotKeys, monkit.NewSeriesKey("a b,c=d").WithTag("k", "x.y"),
+			"a_b_c_d.x_y.successes"},
+		{UnderscoreKeys, monkit.NewSeriesKey("a-b").WithTag("k", "x y"),
+			"a_b_x_y_successes"},
+		{CamelCaseKeys, monkit.NewSeriesKey("http_requests").WithTag("k", "..."),
+			"httpRequestsSuccesses"},
+	} {
+		if actual := test.kf(test.key, "successes"); actual != test.expected {
+			t.Fatalf("expected %q, got %q", test.expected, actual)
+		}
+	}
+}
+
+func TestStatsTextFormatted(t *testing.T) {
+	r := monkit.NewRegistry()
+	r.ScopeNamed("example.com/a").Counter("calls").Inc(3)
+
+	var buf bytes.Buffer
+	if err := StatsTextFormatted(r, &buf, UnderscoreKeys); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `calls_example_com_a_high 3.000000
+calls_example_com_a_low 3.000000
+calls_example_com_a_value 3.000000
+`
+	if buf.String() != expected {
+		t.Fatalf("unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
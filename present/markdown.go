@@ -0,0 +1,89 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+type funcRow struct {
+	name     string
+	calls    int64
+	errors   int64
+	p50, p99 time.Duration
+}
+
+// funcSortKeys are the columns FuncsMarkdown can sort by.
+var funcSortKeys = map[string]func(a, b funcRow) bool{
+	"calls":  func(a, b funcRow) bool { return a.calls > b.calls },
+	"errors": func(a, b funcRow) bool { return a.errors > b.errors },
+	"p50":    func(a, b funcRow) bool { return a.p50 > b.p50 },
+	"p99":    func(a, b funcRow) bool { return a.p99 > b.p99 },
+}
+
+// FuncsMarkdown finds all of the Funcs known by Registry r and writes the
+// topN of them as a GitHub-flavored Markdown table to w, such as for a
+// performance report in CI. The columns are the calls, the errors including
+// panics, and the p50 and p99 durations of successful calls. The Funcs are
+// sorted by the column sortBy, one of "calls", "errors", "p50" and "p99", in
+// descending order, with ties sorted by name. A topN of zero or less writes
+// all of them.
+func FuncsMarkdown(r *monkit.Registry, w io.Writer, sortBy string, topN int) error {
+	less, ok := funcSortKeys[sortBy]
+	if !ok {
+		return fmt.Errorf("unknown column %q to sort by", sortBy)
+	}
+
+	var rows []funcRow
+	r.Funcs(func(f *monkit.Func) {
+		var errors int64
+		for _, count := range f.Errors() {
+			errors += count
+		}
+		errors += f.Panics()
+		times := f.SuccessTimes()
+		rows = append(rows, funcRow{
+			name:   f.FullName(),
+			calls:  f.Success() + errors,
+			errors: errors,
+			p50:    times.Query(.5),
+			p99:    times.Query(.99),
+		})
+	})
+	sort.SliceStable(rows, func(i, j int) bool {
+		if less(rows[i], rows[j]) {
+			return true
+		}
+		if less(rows[j], rows[i]) {
+			return false
+		}
+		return rows[i].name < rows[j].name
+	})
+	if topN > 0 && topN < len(rows) {
+		rows = rows[:topN]
+	}
+
+	var b strings.Builder
+	b.WriteString("| Func | Calls | Errors | p50 | p99 |\n")
+	b.WriteString("| --- | ---: | ---: | ---: | ---: |\n")
+	for _, row := range rows {
+		fmt.Fprintf(&b, "| %s | %d | %d | %s | %s |\n",
+			escapeMarkdownCell(row.name), row.calls, row.errors, row.p50, row.p99)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var markdownCellEscaper = strings.NewReplacer(
+	`\`, `\\`, `|`, `\|`, "`", "\\`", "*", `\*`, "_", `\_`, "\n", " ")
+
+func escapeMarkdownCell(s string) string {
+	return markdownCellEscaper.Replace(s)
+}
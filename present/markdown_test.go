@@ -0,0 +1,75 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestFuncsMarkdown(t *testing.T) {
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("report")
+	call := func(name string, n int, err error, sleep time.Duration) {
+		for i := 0; i < n; i++ {
+			ctx := context.Background()
+			finish := mon.TaskNamed(name)(&ctx)
+			time.Sleep(sleep)
+			finish(&err)
+		}
+	}
+	call("busy", 5, nil, 0)
+	call("failing", 3, errors.New("boom"), 0)
+	call("slow", 1, nil, 10*time.Millisecond)
+	call("a|b", 2, nil, 0)
+
+	order := func(sortBy string, topN int) []string {
+		var buf bytes.Buffer
+		if err := FuncsMarkdown(r, &buf, sortBy, topN); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) < 2 || lines[0] != "| Func | Calls | Errors | p50 | p99 |" ||
+			lines[1] != "| --- | ---: | ---: | ---: | ---: |" {
+			t.Fatalf("invalid table header:\n%s", buf.String())
+		}
+		var names []string
+		for _, line := range lines[2:] {
+			if !strings.HasPrefix(line, "| ") || !strings.HasSuffix(line, " |") {
+				t.Fatalf("invalid table row %q", line)
+			}
+			cells := strings.Split(strings.ReplaceAll(line, `\|`, "¦"), " | ")
+			if len(cells) != 5 {
+				t.Fatalf("expected 5 cells in %q", line)
+			}
+			names = append(names, strings.TrimPrefix(cells[0], "| "))
+		}
+		return names
+	}
+
+	for _, tc := range []struct {
+		sortBy   string
+		topN     int
+		expected string
+	}{
+		{"calls", 0, "report.busy report.failing report.a¦b report.slow"},
+		{"calls", 2, "report.busy report.failing"},
+		{"errors", 1, "report.failing"},
+		{"p99", 1, "report.slow"},
+	} {
+		if got := strings.Join(order(tc.sortBy, tc.topN), " "); got != tc.expected {
+			t.Fatalf("sorted by %s: got %q, expected %q", tc.sortBy, got, tc.expected)
+		}
+	}
+
+	if err := FuncsMarkdown(r, &bytes.Buffer{}, "name", 0); err == nil {
+		t.Fatal("expected an error for an unknown column")
+	}
+}
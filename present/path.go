@@ -18,7 +18,6 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
-	"fmt"
 	"html/template"
 	"io"
 	"net/url"
@@ -58,13 +57,19 @@ func curry(reg *monkit.Registry,
 //   - /funcs, /funcs/text - returns the result of FuncsText
 //   - /funcs/dot          - returns the result of FuncsDot
 //   - /funcs/json         - returns the result of FuncsJSON
+//   - /funcs/markdown     - returns the result of FuncsMarkdown, sorted by
+//     the ?sort= query parameter (calls by default) and limited to ?n= Funcs
 //   - /stats, /stats/text - returns the result of StatsText
 //   - /stats/json         - returns the result of StatsJSON
+//   - /stats/grouped      - returns the result of StatsTextGrouped
+//   - /stats/sorted       - returns the result of StatsTextSorted
 //   - /trace/svg          - returns the result of TraceQuerySVG
 //   - /trace/json         - returns the result of TraceQueryJSON
+//   - /trace/chrome       - returns the result of TraceQueryChrome
+//   - /trace/attachments  - returns the result of TraceQueryAttachments
 //   - /trace/remote       - returns trace id or redirect
 //
-// The last two paths are worth discussing in more detail, as they take
+// The /trace paths are worth discussing in more detail, as they take
 // query parameters. All trace endpoints require at least one of the following
 // two query parameters:
 //   - regex    - If provided, the very next Span that crosses a Func that has
@@ -87,6 +92,10 @@ func curry(reg *monkit.Registry,
 // additional query param. Be advised that until a trace completes, whether
 // or not it has started, it adds a small amount of overhead (a comparison or
 // two) to every monitored function.
+//
+// The /stats/text and /stats/json paths accept an optional keys query
+// parameter of dot, underscore or camel, which flattens every series name with
+// the matching KeyFormatter (see StatsTextFormatted and StatsJSONFormatted).
 func FromRequest(reg *monkit.Registry, path string, query url.Values) (
 	f Result, contentType string, err error) {
 
@@ -130,18 +139,55 @@ func FromRequest(reg *monkit.Registry, path string, query url.Values) (
 			return curry(reg, FuncsDot), "text/plain; charset=utf-8", nil
 		case "json":
 			return curry(reg, FuncsJSON), "application/json; charset=utf-8", nil
+		case "markdown":
+			sortBy := query.Get("sort")
+			if sortBy == "" {
+				sortBy = "calls"
+			}
+			if _, ok := funcSortKeys[sortBy]; !ok {
+				return nil, "", errBadRequest.New("unknown sort column %q", sortBy)
+			}
+			topN := 0
+			if n := query.Get("n"); n != "" {
+				var err error
+				topN, err = strconv.Atoi(n)
+				if err != nil {
+					return nil, "", errBadRequest.New("invalid n %#v: %v", n, err)
+				}
+			}
+			return func(w io.Writer) error {
+				return FuncsMarkdown(reg, w, sortBy, topN)
+			}, "text/markdown; charset=utf-8", nil
 		}
 
 	case "stats":
+		var kf KeyFormatter
+		if name := query.Get("keys"); name != "" {
+			var ok bool
+			kf, ok = KeyFormatterNamed(name)
+			if !ok {
+				return nil, "", errBadRequest.New("unknown key format %q", name)
+			}
+		}
 		switch second {
 		case "", "text", "old":
 			return func(w io.Writer) error {
+				if kf != nil {
+					return StatsTextFormatted(reg, w, kf)
+				}
 				return StatsText(reg, w)
 			}, "text/plain; charset=utf-8", nil
 		case "json":
 			return func(w io.Writer) error {
+				if kf != nil {
+					return StatsJSONFormatted(reg, w, kf)
+				}
 				return StatsJSON(reg, w)
 			}, "application/json; charset=utf-8", nil
+		case "grouped":
+			return curry(reg, StatsTextGrouped), "text/plain; charset=utf-8", nil
+		case "sorted":
+			return curry(reg, StatsTextSorted), "text/plain; charset=utf-8", nil
 		}
 
 	case "trace":
@@ -222,6 +268,14 @@ func FromRequest(reg *monkit.Registry, path string, query url.Values) (
 			return func(w io.Writer) error {
 				return TraceQueryJSON(reg, w, spanMatcher, minDuration)
 			}, "application/json; charset=utf-8", nil
+		case "chrome":
+			return func(w io.Writer) error {
+				return TraceQueryChrome(reg, w, spanMatcher, minDuration)
+			}, "application/json; charset=utf-8", nil
+		case "attachments":
+			return func(w io.Writer) error {
+				return TraceQueryAttachments(reg, w, spanMatcher, minDuration)
+			}, "application/json; charset=utf-8", nil
 		case "remote":
 			viz := query.Get("viz")
 			if viz != "" && (!strings.HasPrefix(viz, "http:") && !strings.HasPrefix(viz, "https:")) {
@@ -286,7 +340,7 @@ func FromRequest(reg *monkit.Registry, path string, query url.Values) (
 				}
 
 				q := u.Query()
-				q.Add("argv", fmt.Sprint(trace.Id()))
+				q.Add("argv", monkit.FormatTraceID(trace.Id(), monkit.IDFormatDecimal))
 				u.RawQuery = q.Encode()
 
 				return vizRedirectHTML.Execute(w, u.String())
@@ -327,10 +381,12 @@ func writeIndex(w io.Writer) error {
 			<dt><a href="funcs">/funcs</a></dt>
 			<dt><a href="funcs/json">/funcs/json</a></dt>
 			<dt><a href="funcs/dot">/funcs/dot</a></dt>
+			<dt><a href="funcs/markdown">/funcs/markdown</a></dt>
 			<dd>Information about the functions and their relations.</dd>
 
 			<dt><a href="stats">/stats</a></dt>
 			<dt><a href="stats/json">/stats/json</a></dt>
+			<dt><a href="stats/grouped">/stats/grouped</a></dt>
 			<dt><a href="stats/svg">/stats/svg</a></dt>
 			<dd>Statistics about all observed functions, scopes and values.</dd>
 
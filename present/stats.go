@@ -17,6 +17,7 @@ package present
 import (
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/spacemonkeygo/monkit/v3"
 )
@@ -36,6 +37,88 @@ func StatsText(r *monkit.Registry, w io.Writer) (err error) {
 	return err
 }
 
+// StatsTextSorted is like StatsText, but writes the series sorted by key, so
+// that the output is the same no matter in what order the values were
+// registered, which makes it easy to diff or compare in tests. Fields of a
+// series keep the order they are reported in. All values are buffered before
+// anything is written, so for large Registries StatsText uses less memory.
+func StatsTextSorted(r *monkit.Registry, w io.Writer) (err error) {
+	type line struct {
+		key  string
+		text string
+	}
+	var lines []line
+	r.Stats(func(key monkit.SeriesKey, field string, val float64) {
+		lines = append(lines, line{
+			key:  key.String(),
+			text: fmt.Sprintf("%s=%f\n", key.WithField(field), val),
+		})
+	})
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].key < lines[j].key })
+	for _, l := range lines {
+		if _, err = io.WriteString(w, l.text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StatsTextFormatted is like StatsText, but names every value with kf
+// instead of the usual measurement,tags field form, writing one
+// "name value" line per value.
+func StatsTextFormatted(r *monkit.Registry, w io.Writer, kf KeyFormatter) (err error) {
+	r.Stats(func(key monkit.SeriesKey, field string, val float64) {
+		if err != nil {
+			return
+		}
+		_, err = fmt.Fprintf(w, "%s %f\n", kf(key, field), val)
+	})
+	return err
+}
+
+// StatsTextGrouped is like StatsText, but groups the series by the Scope
+// (usually the package) they come from. Every group starts with a header line
+// naming the Scope, followed by its series indented and without the scope
+// tag. Groups are written in the order the Registry reports Scopes in.
+func StatsTextGrouped(r *monkit.Registry, w io.Writer) (err error) {
+	var scopes []string
+	groups := map[string][]string{}
+	r.Stats(func(key monkit.SeriesKey, field string, val float64) {
+		scope := key.Tags.Get("scope")
+		tags := make(map[string]string, key.Tags.Len())
+		for k, v := range key.Tags.All() {
+			if k != "scope" {
+				tags[k] = v
+			}
+		}
+		key.Tags = new(monkit.TagSet).SetAll(tags)
+
+		if _, exists := groups[scope]; !exists {
+			scopes = append(scopes, scope)
+		}
+		groups[scope] = append(groups[scope],
+			fmt.Sprintf("%s=%f", key.WithField(field), val))
+	})
+
+	for i, scope := range scopes {
+		sep := "\n"
+		if i == 0 {
+			sep = ""
+		}
+		_, err = fmt.Fprintf(w, "%s[%s]\n", sep, scope)
+		if err != nil {
+			return err
+		}
+		for _, line := range groups[scope] {
+			_, err = fmt.Fprintf(w, "  %s\n", line)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // StatsJSON writes all of the name/value statistics pairs the Registry knows
 // to w in a JSON format.
 func StatsJSON(r *monkit.Registry, w io.Writer) (err error) {
@@ -45,3 +128,13 @@ func StatsJSON(r *monkit.Registry, w io.Writer) (err error) {
 	})
 	return lw.done()
 }
+
+// StatsJSONFormatted is like StatsJSON, but names every value with kf, writing
+// a list of [name, value] pairs.
+func StatsJSONFormatted(r *monkit.Registry, w io.Writer, kf KeyFormatter) (err error) {
+	lw := newListWriter(w)
+	r.Stats(func(key monkit.SeriesKey, field string, val float64) {
+		lw.elem([]interface{}{kf(key, field), val})
+	})
+	return lw.done()
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package present
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestStatsTextGrouped(t *testing.T) {
+	r := monkit.NewRegistry()
+	r.ScopeNamed("example.com/b").BoolVal("flag").Observe(true)
+	r.ScopeNamed("example.com/a").Counter("calls", monkit.NewSeriesTag("kind", "x")).Inc(3)
+
+	var buf bytes.Buffer
+	if err := StatsTextGrouped(r, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[example.com/a]
+  calls,kind=x high=3.000000
+  calls,kind=x low=3.000000
+  calls,kind=x value=3.000000
+
+[example.com/b]
+  flag disposition=1.000000
+  flag false=0.000000
+  flag recent=1.000000
+  flag true=1.000000
+`
+	if buf.String() != expected {
+		t.Fatalf("unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestStatsTextSorted(t *testing.T) {
+	register := []func(r *monkit.Registry){
+		func(r *monkit.Registry) { r.ScopeNamed("example.com/b").Counter("zeta").Inc(1) },
+		func(r *monkit.Registry) { r.ScopeNamed("example.com/b").Counter("alpha").Inc(2) },
+		func(r *monkit.Registry) {
+			r.ScopeNamed("example.com/a").Counter("calls", monkit.NewSeriesTag("kind", "x")).Inc(3)
+		},
+	}
+
+	expected := `alpha,scope=example.com/b high=2.000000
+alpha,scope=example.com/b low=2.000000
+alpha,scope=example.com/b value=2.000000
+calls,kind=x,scope=example.com/a high=3.000000
+calls,kind=x,scope=example.com/a low=3.000000
+calls,kind=x,scope=example.com/a value=3.000000
+zeta,scope=example.com/b high=1.000000
+zeta,scope=example.com/b low=1.000000
+zeta,scope=example.com/b value=1.000000
+`
+	for _, order := range [][]int{{0, 1, 2}, {2, 1, 0}, {1, 2, 0}} {
+		r := monkit.NewRegistry()
+		for _, i := range order {
+			register[i](r)
+		}
+		var buf bytes.Buffer
+		if err := StatsTextSorted(r, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != expected {
+			t.Fatalf("unexpected output for order %v:\n%s\nexpected:\n%s", order, buf.String(), expected)
+		}
+	}
+}
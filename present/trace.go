@@ -162,7 +162,7 @@ func computeSpanTree(spans []*collect.FinishedSpan) map[int64]*spanInformation {
 			out[id].LargestTime = span.Finish
 		}
 
-		if pid, ok := span.Span.ParentId(); ok {
+		if pid, ok := span.ParentId(); ok {
 			out[id].Parent = pid
 
 			if pedges := out[pid]; pedges == nil {
@@ -202,7 +202,7 @@ func includeSpanInLayoutInformation(spanTree map[int64]*spanInformation, span *c
 	}
 	si.Layout = true
 
-	parSpanId, ok := span.Span.ParentId()
+	parSpanId, ok := span.ParentId()
 	if !ok || spanTree[parSpanId] == nil {
 		return
 	}
@@ -351,7 +351,7 @@ func SpansToSVG(w io.Writer, spans []*collect.FinishedSpan) error {
 		}
 		templateVals.FuncArgs = buf.String()
 
-		if parentId, ok := s.Span.ParentId(); ok && byId[parentId] != nil {
+		if parentId, ok := s.ParentId(); ok && byId[parentId] != nil {
 			row := 0
 			pli := lis[parentId]
 			if pli != nil {
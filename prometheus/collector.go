@@ -0,0 +1,238 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package prometheus
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// Options configures a Collector.
+type Options struct {
+	// Namespace, if set, prefixes every metric name, separated by an
+	// underscore.
+	Namespace string
+
+	// Buckets maps monkit measurement names, such as "request_size", to
+	// the upper bounds of the buckets to export the distributions of the
+	// measurement with, as Prometheus histograms named after the
+	// measurement. The _count and _sum of those histograms are exact, but
+	// the bucket counts are approximate: they are the fraction of the
+	// distribution's reservoir sample of monkit.ReservoirSize values that
+	// falls into each bucket, scaled to the count. The reservoir favors
+	// recent values (see monkit.Window), so the buckets describe recent
+	// behavior more than the count does. They are exact as long as no more
+	// values than the reservoir holds were observed, and bounds below the
+	// minimum or at least the maximum observed value are always exact.
+	// The other fields of the measurement, like r50 or max, are exported as
+	// gauges as usual, except for count and sum.
+	Buckets map[string][]float64
+
+	// Timestamps exports every metric with the time its monkit value was
+	// last updated (see monkit.LastUpdatedSource) instead of the scrape
+	// time, for downstreams that care when a value changed. Values that
+	// don't track it, never were updated, and histograms keep the scrape
+	// time. Prometheus itself discourages explicit timestamps, as metrics
+	// with timestamps older than a few minutes are considered stale.
+	Timestamps bool
+}
+
+// Collector implements prometheus.Collector over a monkit Registry. Every
+// monkit value is exported as a metric named after its measurement and field,
+// such as function_successes, with the series tags as labels.
+//
+// monkit doesn't record what kind of value a field is, so fields that by
+// convention only ever grow (count, total, successes, errors, panics and
+// failures) are exported as counters, and everything else as gauges.
+type Collector struct {
+	registry *monkit.Registry
+	opts     Options
+}
+
+var _ prom.Collector = (*Collector)(nil)
+
+// NewCollector creates a Collector for r.
+func NewCollector(r *monkit.Registry, opts Options) *Collector {
+	return &Collector{registry: r, opts: opts}
+}
+
+// Describe implements prometheus.Collector. The set of monkit series changes
+// as the program runs, so Collector is an unchecked collector and sends no
+// descriptors.
+func (c *Collector) Describe(ch chan<- *prom.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	seen := map[string]bool{}
+
+	c.registry.StatsTimestamped(func(key monkit.SeriesKey, field string, val float64, updated time.Time) {
+		if _, ok := c.opts.Buckets[key.Measurement]; ok && (field == "count" || field == "sum") {
+			// they are part of the histogram.
+			return
+		}
+		name := c.metricName(key.Measurement, field)
+
+		tags := key.Tags.All()
+		labels := make(prom.Labels, len(tags))
+		for k, v := range tags {
+			labels[sanitizeLabel(k)] = v
+		}
+
+		// sanitization can map distinct monkit series to the same Prometheus
+		// series, which the Prometheus registry would reject.
+		id := name + "{" + labelsKey(labels) + "}"
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+
+		desc := prom.NewDesc(name, help(name), nil, labels)
+
+		metric, err := prom.NewConstMetric(desc, valueType(field), val)
+		if err != nil {
+			ch <- prom.NewInvalidMetric(desc, err)
+			return
+		}
+		if c.opts.Timestamps && !updated.IsZero() {
+			metric = prom.NewMetricWithTimestamp(updated, metric)
+		}
+		ch <- metric
+	})
+
+	if len(c.opts.Buckets) > 0 {
+		c.collectHistograms(ch, seen)
+	}
+}
+
+func (c *Collector) collectHistograms(ch chan<- prom.Metric, seen map[string]bool) {
+	c.registry.Distributions(func(key monkit.SeriesKey, snap monkit.DistSnapshot) {
+		bounds, ok := c.opts.Buckets[key.Measurement]
+		if !ok {
+			return
+		}
+		name := c.metricName(key.Measurement, "")
+
+		tags := key.Tags.All()
+		labels := make(prom.Labels, len(tags))
+		for k, v := range tags {
+			labels[sanitizeLabel(k)] = v
+		}
+
+		id := name + "{" + labelsKey(labels) + "}"
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+
+		desc := prom.NewDesc(name, help(name), nil, labels)
+
+		metric, err := prom.NewConstHistogram(desc, uint64(snap.Count), snap.Sum, bucketCounts(snap, bounds))
+		if err != nil {
+			ch <- prom.NewInvalidMetric(desc, err)
+			return
+		}
+		ch <- metric
+	})
+}
+
+// bucketCounts estimates the number of observed values of snap that are at
+// most each of bounds from its reservoir. See Options.Buckets.
+func bucketCounts(snap monkit.DistSnapshot, bounds []float64) map[float64]uint64 {
+	buckets := make(map[float64]uint64, len(bounds))
+	for _, bound := range bounds {
+		switch {
+		case snap.Count == 0 || bound < snap.Min:
+			buckets[bound] = 0
+		case bound >= snap.Max || len(snap.Reservoir) == 0:
+			buckets[bound] = uint64(snap.Count)
+		default:
+			// the reservoir is sorted, so this is the number of samples
+			// at most bound.
+			below := sort.Search(len(snap.Reservoir), func(i int) bool {
+				return snap.Reservoir[i] > bound
+			})
+			fraction := float64(below) / float64(len(snap.Reservoir))
+			buckets[bound] = uint64(math.Round(fraction * float64(snap.Count)))
+		}
+	}
+	return buckets
+}
+
+func (c *Collector) metricName(measurement, field string) string {
+	name := measurement
+	if field != "" {
+		name += "_" + field
+	}
+	if c.opts.Namespace != "" {
+		name = c.opts.Namespace + "_" + name
+	}
+	return sanitizeName(name)
+}
+
+// help returns the HELP text of the metric family name. It only depends on
+// the name, as distinct monkit measurements can sanitize to the same name,
+// and all metrics of a family must have the same HELP text.
+func help(name string) string {
+	return "monkit " + name
+}
+
+func labelsKey(labels prom.Labels) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+strconv.Quote(v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func valueType(field string) prom.ValueType {
+	switch field {
+	case "count", "total", "successes", "errors", "panics", "failures":
+		return prom.CounterValue
+	}
+	return prom.GaugeValue
+}
+
+// sanitizeName replaces characters that aren't allowed in Prometheus metric
+// names with underscores.
+func sanitizeName(name string) string {
+	return sanitize(name, func(r rune) bool { return r == ':' })
+}
+
+// sanitizeLabel replaces characters that aren't allowed in Prometheus label
+// names with underscores and avoids the reserved __ prefix.
+func sanitizeLabel(name string) string {
+	name = sanitize(name, func(rune) bool { return false })
+	if strings.HasPrefix(name, "__") {
+		name = "tag" + name
+	}
+	return name
+}
+
+func sanitize(name string, extra func(rune) bool) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', extra(r):
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+func TestCollector(t *testing.T) {
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("example.com/pkg")
+	mon.Counter("open.files", monkit.NewSeriesTag("__kind", "disk")).Inc(3)
+	mon.Meter("requests").Mark(5)
+
+	reg := prom.NewPedanticRegistry()
+	if err := reg.Register(NewCollector(r, Options{Namespace: "app"})); err != nil {
+		t.Fatal(err)
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := map[string]*dto.MetricFamily{}
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+
+	value := byName["app_open_files_value"]
+	if value == nil || value.GetType() != dto.MetricType_GAUGE {
+		t.Fatalf("missing or mistyped app_open_files_value: %v", value)
+	}
+	metric := value.GetMetric()[0]
+	if metric.GetGauge().GetValue() != 3 {
+		t.Fatalf("unexpected value %v", metric.GetGauge().GetValue())
+	}
+	labels := map[string]string{}
+	for _, pair := range metric.GetLabel() {
+		labels[pair.GetName()] = pair.GetValue()
+	}
+	if labels["scope"] != "example.com/pkg" || labels["tag__kind"] != "disk" {
+		t.Fatalf("unexpected labels %v", labels)
+	}
+
+	total := byName["app_requests_total"]
+	if total == nil || total.GetType() != dto.MetricType_COUNTER ||
+		total.GetMetric()[0].GetCounter().GetValue() != 5 {
+		t.Fatalf("missing or mistyped app_requests_total: %v", total)
+	}
+}
+
+func TestCollectorHelpPerFamily(t *testing.T) {
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("pkg")
+	// both sanitize to the app_open_files_value family.
+	mon.Counter("open.files", monkit.NewSeriesTag("kind", "disk")).Inc(1)
+	mon.Counter("open_files", monkit.NewSeriesTag("kind", "net")).Inc(2)
+
+	reg := prom.NewPedanticRegistry()
+	if err := reg.Register(NewCollector(r, Options{Namespace: "app"})); err != nil {
+		t.Fatal(err)
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() == "app_open_files_value" && len(family.GetMetric()) != 2 {
+			t.Fatalf("expected both series in the family, got %v", family)
+		}
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	for in, expected := range map[string]string{
+		"function_times": "function_times",
+		"a.b-c/d":        "a_b_c_d",
+		"9lives":         "_9lives",
+		"ns:name":        "ns:name",
+		"":               "_",
+	} {
+		if actual := sanitizeName(in); actual != expected {
+			t.Fatalf("sanitizeName(%q) = %q, expected %q", in, actual, expected)
+		}
+	}
+	if actual := sanitizeLabel("ns:name"); actual != "ns_name" {
+		t.Fatalf("unexpected label %q", actual)
+	}
+}
+
+func TestCollectorBuckets(t *testing.T) {
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("pkg")
+	size := mon.IntVal("request_size")
+	for i := int64(1); i <= 10; i++ {
+		size.Observe(i)
+	}
+
+	c := NewCollector(r, Options{Namespace: "app", Buckets: map[string][]float64{
+		"request_size": {2.5, 5, 7.5, 20},
+	}})
+	expected := `
+# HELP app_request_size monkit app_request_size
+# TYPE app_request_size histogram
+app_request_size_bucket{scope="pkg",le="2.5"} 2
+app_request_size_bucket{scope="pkg",le="5"} 5
+app_request_size_bucket{scope="pkg",le="7.5"} 7
+app_request_size_bucket{scope="pkg",le="20"} 10
+app_request_size_bucket{scope="pkg",le="+Inf"} 10
+app_request_size_sum{scope="pkg"} 55
+app_request_size_count{scope="pkg"} 10
+# HELP app_request_size_r50 monkit app_request_size_r50
+# TYPE app_request_size_r50 gauge
+app_request_size_r50{scope="pkg"} 5
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"app_request_size", "app_request_size_r50"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the histogram replaces the count and sum gauges, so the pedantic
+	// registry accepts it.
+	reg := prom.NewPedanticRegistry()
+	if err := reg.Register(c); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reg.Gather(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBucketCountsApproximate(t *testing.T) {
+	d := monkit.NewIntDist(monkit.NewSeriesKey("d"))
+	d.Seed(1)
+	for i := int64(0); i < 10000; i++ {
+		d.Insert(i % 100)
+	}
+	buckets := bucketCounts(d.Snapshot(), []float64{-1, 49, 99})
+	if buckets[-1] != 0 || buckets[99] != 10000 {
+		t.Fatalf("expected exact counts outside the observed range, got %v", buckets)
+	}
+	if mid := buckets[49]; mid < 3000 || mid > 7000 {
+		t.Fatalf("expected about half the values below the median, got %v", buckets)
+	}
+}
+
+func TestCollectorGlobalTags(t *testing.T) {
+	r := monkit.NewRegistry()
+	r.ScopeNamed("pkg").IntVal("request_size").Observe(4)
+	r.SetGlobalTags(monkit.NewSeriesTag("host", "web"))
+
+	c := NewCollector(r, Options{Namespace: "app", Buckets: map[string][]float64{
+		"request_size": {5},
+	}})
+	expected := `
+# HELP app_request_size monkit app_request_size
+# TYPE app_request_size histogram
+app_request_size_bucket{host="web",scope="pkg",le="5"} 1
+app_request_size_bucket{host="web",scope="pkg",le="+Inf"} 1
+app_request_size_sum{host="web",scope="pkg"} 4
+app_request_size_count{host="web",scope="pkg"} 1
+# HELP app_request_size_r50 monkit app_request_size_r50
+# TYPE app_request_size_r50 gauge
+app_request_size_r50{host="web",scope="pkg"} 4
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"app_request_size", "app_request_size_r50"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollectorTimestamps(t *testing.T) {
+	r := monkit.NewRegistry()
+	mon := r.ScopeNamed("pkg")
+	before := time.Now()
+	mon.Counter("requests").Inc(1)
+	mon.Counter("idle")
+
+	for _, timestamps := range []bool{false, true} {
+		reg := prom.NewPedanticRegistry()
+		if err := reg.Register(NewCollector(r, Options{Timestamps: timestamps})); err != nil {
+			t.Fatal(err)
+		}
+		families, err := reg.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stamps := map[string]int64{}
+		for _, family := range families {
+			stamps[family.GetName()] = family.GetMetric()[0].GetTimestampMs()
+		}
+		if !timestamps {
+			if stamps["requests_value"] != 0 {
+				t.Fatalf("unexpected timestamp %d", stamps["requests_value"])
+			}
+			continue
+		}
+		if stamps["requests_value"] < before.UnixMilli() {
+			t.Fatalf("expected the update time, got %d", stamps["requests_value"])
+		}
+		// never updated, so it keeps the scrape time.
+		if stamps["idle_value"] != 0 {
+			t.Fatalf("unexpected timestamp %d for a value never updated", stamps["idle_value"])
+		}
+	}
+}
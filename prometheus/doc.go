@@ -0,0 +1,13 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+/*
+Package prometheus exposes a monkit Registry as a prometheus.Collector, so
+that monkit stats can be served by an existing Prometheus registry:
+
+	prometheus.MustRegister(monkitprom.NewCollector(monkit.Default, monkitprom.Options{}))
+
+It lives in its own module so that the core monkit module doesn't depend on
+the Prometheus client library.
+*/
+package prometheus // import "github.com/spacemonkeygo/monkit/v3/prometheus"
@@ -0,0 +1,88 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package propagation
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// limits from https://www.w3.org/TR/baggage/#limits
+	maxBaggageMembers = 180
+	maxBaggageBytes   = 8192
+)
+
+// FormatBaggage builds a W3C baggage header value from the given key/value
+// pairs. If keys are given, only those keys are included, in that order,
+// otherwise all keys are included in sorted order. Keys that aren't valid
+// baggage keys are skipped, values are percent-encoded as needed, and members
+// that would exceed the limits of the baggage specification are dropped.
+func FormatBaggage(baggage map[string]string, keys ...string) string {
+	if len(keys) == 0 {
+		keys = make([]string, 0, len(baggage))
+		for k := range baggage {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+
+	var b strings.Builder
+	members := 0
+	for _, key := range keys {
+		value, ok := baggage[key]
+		if !ok || !isBaggageKey(key) {
+			continue
+		}
+		member := key + "=" + encodeBaggageValue(value)
+		size := len(member)
+		if members > 0 {
+			size++
+		}
+		if members >= maxBaggageMembers || b.Len()+size > maxBaggageBytes {
+			break
+		}
+		if members > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(member)
+		members++
+	}
+	return b.String()
+}
+
+// isBaggageKey reports whether key is an RFC 7230 token.
+func isBaggageKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// encodeBaggageValue percent-encodes everything that isn't a baggage-octet,
+// as well as '%' itself.
+func encodeBaggageValue(value string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c < 0x21 || c > 0x7e || c == '"' || c == ',' || c == ';' || c == '\\' || c == '%' {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xf])
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
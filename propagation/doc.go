@@ -0,0 +1,20 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+/*
+Package propagation carries monkit traces across process boundaries over any
+transport that has string key/value headers, such as HTTP, Kafka or AMQP
+messages. It is modeled after OpenTelemetry's TextMapPropagator.
+
+A producer injects the current Span into the outgoing message:
+
+	propagation.W3C{}.Inject(ctx, propagation.MapCarrier(msg.Headers))
+
+and a consumer extracts it again and continues the trace:
+
+	ctx = propagation.W3C{}.Extract(ctx, propagation.MapCarrier(msg.Headers))
+	remote, _ := propagation.RemoteFromCtx(ctx)
+	defer mon.ContinueTrace(&ctx, remote.TraceIdOrNew(), remote.ParentIdOrZero(),
+		remote.Sampled, remote.Baggage)(&err)
+*/
+package propagation
@@ -0,0 +1,65 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package propagation
+
+import (
+	"context"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/present"
+)
+
+// Header is a TextMapPropagator for trace context carried in a single header
+// with a custom name, for systems with their own propagation conventions. It
+// carries no baggage.
+type Header struct {
+	// Name is the name of the header.
+	Name string
+
+	// Format and Parse convert between the trace context and the header
+	// value. If nil, the value has the format of the W3C traceparent header,
+	// like "00-<trace id>-<span id>-<flags>".
+	Format func(traceId, spanId int64, flags byte) string
+	Parse  func(value string) (traceId, spanId int64, flags byte, ok bool)
+}
+
+var _ TextMapPropagator = Header{}
+
+// Inject implements TextMapPropagator. Only sampled traces are propagated.
+func (h Header) Inject(ctx context.Context, carrier Setter) {
+	s := monkit.SpanFromCtx(ctx)
+	if s == nil {
+		return
+	}
+	if sampled, _ := s.Trace().Get(present.SampledKey).(bool); !sampled {
+		return
+	}
+	if h.Format == nil {
+		carrier.Set(h.Name, FormatTraceParent(s.Trace().FullId(), s.Id(), s.Trace().Flags()))
+		return
+	}
+	carrier.Set(h.Name, h.Format(s.Trace().Id(), s.Id(), s.Trace().Flags()))
+}
+
+// Extract implements TextMapPropagator.
+func (h Header) Extract(ctx context.Context, carrier Getter) context.Context {
+	value := carrier.Get(h.Name)
+	if value == "" {
+		return ctx
+	}
+	parse := h.Parse
+	if parse == nil {
+		parse = parseTraceParent
+	}
+	traceId, parentId, flags, ok := parse(value)
+	if !ok {
+		return ctx
+	}
+	return WithRemote(ctx, Remote{
+		TraceId:  &traceId,
+		ParentId: &parentId,
+		Sampled:  flags&traceSampled != 0,
+		Flags:    flags,
+	})
+}
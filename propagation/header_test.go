@@ -0,0 +1,56 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package propagation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/present"
+)
+
+func TestHeaderRoundTrip(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("propagation")
+
+	ctx := context.Background()
+	trace := monkit.NewTrace(monkit.NewId())
+	trace.Set(present.SampledKey, true)
+	defer mon.FuncNamed("producer").RemoteTrace(&ctx, 0, trace)(nil)
+	producer := monkit.SpanFromCtx(ctx)
+
+	for _, header := range []Header{
+		{Name: "X-Trace"},
+		{
+			Name: "X-Legacy-Trace",
+			Format: func(traceId, spanId int64, flags byte) string {
+				return fmt.Sprintf("%d:%d:%d", traceId, spanId, flags)
+			},
+			Parse: func(value string) (traceId, spanId int64, flags byte, ok bool) {
+				_, err := fmt.Sscanf(value, "%d:%d:%d", &traceId, &spanId, &flags)
+				return traceId, spanId, flags, err == nil
+			},
+		},
+	} {
+		carrier := MapCarrier{}
+		header.Inject(ctx, carrier)
+		if carrier.Get(header.Name) == "" || carrier.Get("traceparent") != "" {
+			t.Fatalf("%s: unexpected headers %v", header.Name, carrier)
+		}
+
+		remote, ok := RemoteFromCtx(header.Extract(context.Background(), carrier))
+		if !ok {
+			t.Fatalf("%s: expected trace context", header.Name)
+		}
+		if *remote.TraceId != trace.Id() || *remote.ParentId != producer.Id() || !remote.Sampled {
+			t.Fatalf("%s: unexpected remote %+v", header.Name, remote)
+		}
+	}
+
+	if _, ok := RemoteFromCtx(Header{Name: "X-Trace"}.Extract(context.Background(),
+		MapCarrier{"X-Trace": "garbage"})); ok {
+		t.Fatal("expected no trace context for an invalid value")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package propagation
+
+import (
+	"strings"
+)
+
+// MetadataCarrier is a Getter and Setter backed by a map of multi-valued
+// keys, which is what gRPC metadata (metadata.MD) is, so that trace context
+// can be extracted from and injected into gRPC calls, with any
+// TextMapPropagator, without this package depending on gRPC:
+//
+//	md, _ := metadata.FromIncomingContext(ctx)
+//	ctx = propagation.W3C{}.Extract(ctx, propagation.MetadataCarrier(md))
+//
+// Keys are lowercased, like gRPC does. An http.Header, whose keys are
+// canonicalized instead, already is a Getter and Setter of its own.
+type MetadataCarrier map[string][]string
+
+// Get implements Getter. Several values of a key are joined with commas,
+// like repeated HTTP header fields are, so that a baggage or tracestate
+// spread over several values is read as a whole.
+func (c MetadataCarrier) Get(key string) string {
+	return strings.Join(c[strings.ToLower(key)], ",")
+}
+
+// Set implements Setter, replacing all values of key.
+func (c MetadataCarrier) Set(key, value string) {
+	c[strings.ToLower(key)] = []string{value}
+}
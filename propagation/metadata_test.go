@@ -0,0 +1,48 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package propagation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/present"
+)
+
+func TestMetadataCarrier(t *testing.T) {
+	md := map[string][]string{
+		"traceparent":  {"00-0000000000000000000000000000002a-0000000000000007-01"},
+		"baggage":      {"tenant-id=42", "tenant-region=eu,other=ignored"},
+		":authority":   {"example.com"},
+		"content-type": {"application/grpc"},
+	}
+	w3c := W3C{AllowedBaggage: []string{"tenant-*"}}
+
+	remote, ok := RemoteFromCtx(w3c.Extract(context.Background(), MetadataCarrier(md)))
+	if !ok {
+		t.Fatal("expected trace context")
+	}
+	if remote.TraceId == nil || *remote.TraceId != 42 || remote.ParentId == nil || *remote.ParentId != 7 {
+		t.Fatalf("unexpected ids %v %v", remote.TraceId, remote.ParentId)
+	}
+	if !remote.Sampled {
+		t.Fatal("expected a sampled trace")
+	}
+	if len(remote.Baggage) != 2 || remote.Baggage["tenant-id"] != "42" || remote.Baggage["tenant-region"] != "eu" {
+		t.Fatalf("expected the baggage of all values, got %v", remote.Baggage)
+	}
+
+	// injecting writes lowercase keys, as gRPC expects.
+	mon := monkit.NewRegistry().ScopeNamed("grpc")
+	ctx := context.Background()
+	trace := monkit.NewTrace(monkit.NewId())
+	trace.Set(present.SampledKey, true)
+	defer mon.FuncNamed("client").RemoteTrace(&ctx, 0, trace)(nil)
+	out := MetadataCarrier{}
+	w3c.Inject(ctx, out)
+	if values := out["traceparent"]; len(values) != 1 || values[0] == "" {
+		t.Fatalf("unexpected metadata %v", out)
+	}
+}
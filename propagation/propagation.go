@@ -0,0 +1,95 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package propagation
+
+import (
+	"context"
+	"net/textproto"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// Getter reads values from a carrier, such as message headers.
+// http.Header matches it.
+type Getter interface {
+	Get(key string) string
+}
+
+// Setter writes values to a carrier, such as message headers.
+// http.Header matches it.
+type Setter interface {
+	Set(key, value string)
+}
+
+// TextMapPropagator injects trace context into carriers and extracts it
+// again.
+type TextMapPropagator interface {
+	// Inject writes the trace context of the Span in ctx, if any, to carrier.
+	Inject(ctx context.Context, carrier Setter)
+
+	// Extract reads trace context from carrier and returns a copy of ctx
+	// carrying it, see RemoteFromCtx. If carrier has no trace context, ctx is
+	// returned unchanged.
+	Extract(ctx context.Context, carrier Getter) context.Context
+}
+
+// MapCarrier is a Getter and Setter backed by a map. Keys are case
+// insensitive, like HTTP header names, and stored in their canonical MIME
+// header form.
+type MapCarrier map[string]string
+
+// Get implements Getter.
+func (c MapCarrier) Get(key string) string {
+	return c[textproto.CanonicalMIMEHeaderKey(key)]
+}
+
+// Set implements Setter.
+func (c MapCarrier) Set(key, value string) {
+	c[textproto.CanonicalMIMEHeaderKey(key)] = value
+}
+
+// Remote is the trace context a TextMapPropagator extracted. Every field is
+// optional.
+type Remote struct {
+	TraceId  *int64
+	ParentId *int64
+	Sampled  bool
+	Baggage  map[string]string
+
+	// Flags are the W3C trace-flags, including the sampled bit, see
+	// monkit.Trace.SetFlags.
+	Flags byte
+}
+
+// TraceIdOrNew returns TraceId, or a new random id if it is not set.
+func (r Remote) TraceIdOrNew() int64 {
+	if r.TraceId != nil {
+		return *r.TraceId
+	}
+	return monkit.NewId()
+}
+
+// ParentIdOrZero returns ParentId, or 0 if it is not set.
+func (r Remote) ParentIdOrZero() int64 {
+	if r.ParentId != nil {
+		return *r.ParentId
+	}
+	return 0
+}
+
+type ctxKey int
+
+const remoteKey ctxKey = iota
+
+// WithRemote returns a copy of ctx carrying remote.
+func WithRemote(ctx context.Context, remote Remote) context.Context {
+	return context.WithValue(ctx, remoteKey, remote)
+}
+
+// RemoteFromCtx returns the trace context extracted into ctx by a
+// TextMapPropagator, or false if there is none.
+func RemoteFromCtx(ctx context.Context) (Remote, bool) {
+	remote, ok := ctx.Value(remoteKey).(Remote)
+	return remote, ok
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package propagation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/present"
+)
+
+const (
+	// see: https://github.com/w3c/trace-context/blob/main/spec/20-http_request_header_format.md
+	traceSampled      = byte(1)
+	traceParentHeader = "traceparent"
+	traceStateHeader  = "tracestate"
+
+	// see: https://www.w3.org/TR/baggage/
+	baggageHeader = "baggage"
+
+	// orphanSampling can be added to the vendor specific tracestate header to
+	// turn on sampling remotely without propagating a parent trace.
+	orphanSampling = "sampled=true"
+)
+
+// W3C is a TextMapPropagator for the W3C traceparent, tracestate and baggage
+// headers.
+type W3C struct {
+	// AllowedBaggage lists the baggage keys Extract keeps, all others are
+	// dropped. A key ending in "*" is a prefix pattern, so "tenant-*" allows
+	// "tenant-id" and "*" allows every key. Only allow every key if the
+	// carrier comes from a trusted source, as baggage ends up as annotations
+	// on Spans.
+	AllowedBaggage []string
+}
+
+var _ TextMapPropagator = W3C{}
+
+// Inject implements TextMapPropagator. Only sampled traces are propagated.
+// Baggage extracted into ctx earlier is passed on, along with the baggage of
+// the Trace, see monkit.Baggage, which wins for keys in both. AllowedBaggage
+// only applies to Extract, so baggage that reached the Trace from a carrier
+// already went through it.
+func (w W3C) Inject(ctx context.Context, carrier Setter) {
+	baggage := monkit.Baggage(ctx)
+	if remote, ok := RemoteFromCtx(ctx); ok {
+		for k, v := range remote.Baggage {
+			if _, ok := baggage[k]; !ok {
+				if baggage == nil {
+					baggage = map[string]string{}
+				}
+				baggage[k] = v
+			}
+		}
+	}
+	if len(baggage) > 0 {
+		carrier.Set(baggageHeader, FormatBaggage(baggage))
+	}
+
+	s := monkit.SpanFromCtx(ctx)
+	if s == nil {
+		return
+	}
+	if sampled, _ := s.Trace().Get(present.SampledKey).(bool); !sampled {
+		return
+	}
+	carrier.Set(traceParentHeader,
+		FormatTraceParent(s.Trace().FullId(), s.Id(), s.Trace().Flags()))
+}
+
+// Extract implements TextMapPropagator.
+func (w W3C) Extract(ctx context.Context, carrier Getter) context.Context {
+	if remote, ok := w.extract(carrier); ok {
+		return WithRemote(ctx, remote)
+	}
+	return ctx
+}
+
+func (w W3C) extract(carrier Getter) (rv Remote, ok bool) {
+	traceParent := carrier.Get(traceParentHeader)
+	traceState := carrier.Get(traceStateHeader)
+	baggage := carrier.Get(baggageHeader)
+	bm := map[string]string{}
+	if baggage != "" {
+		for _, kv := range strings.Split(baggage, ",") {
+			key, value, ok := strings.Cut(kv, "=")
+			key = strings.TrimSpace(key)
+			if !ok || !w.allowed(key) {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+			bm[key] = value
+		}
+	}
+
+	if traceParent != "" {
+		traceId, parentId, flags, ok := parseTraceParent(traceParent)
+		if !ok {
+			return rv, false
+		}
+		return Remote{
+			TraceId:  &traceId,
+			ParentId: &parentId,
+			Sampled:  (flags & traceSampled) == traceSampled,
+			Baggage:  bm,
+			Flags:    flags,
+		}, true
+	}
+
+	// trace parent is not set, but tracing can be turned on by a traceState
+	if strings.Contains(traceState, orphanSampling) {
+		return Remote{
+			Sampled: true,
+			Baggage: bm,
+			Flags:   traceSampled,
+		}, true
+	}
+	return rv, false
+}
+
+func (w W3C) allowed(key string) bool {
+	for _, b := range w.AllowedBaggage {
+		if key == b || (strings.HasSuffix(b, "*") && strings.HasPrefix(key, b[:len(b)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatTraceParent formats a W3C traceparent header value, such as
+// "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01". The trace id is
+// always written with 32 hex digits and the span id with 16, both formatted
+// like monkit.FormatID does, and the flags with two.
+func FormatTraceParent(traceId monkit.ID, spanId int64, flags byte) string {
+	trace := monkit.FormatID(traceId, monkit.IDFormatHex)
+	if len(trace) < 32 {
+		trace = strings.Repeat("0", 32-len(trace)) + trace
+	}
+	return fmt.Sprintf("00-%s-%s-%02x",
+		trace, monkit.FormatTraceID(spanId, monkit.IDFormatHex), flags)
+}
+
+// parseTraceParent parses a traceparent header value, keeping the low 64 bits
+// of the trace id.
+func parseTraceParent(value string) (traceId, spanId int64, flags byte, ok bool) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 4 {
+		return 0, 0, 0, false
+	}
+	version, err := strconv.ParseUint(parts[0], 16, 8)
+	if err != nil || version != 0 {
+		return 0, 0, 0, false
+	}
+	id, err := monkit.ParseID(parts[1])
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	_, lo := id.Parts()
+	parentId, err := strconv.ParseUint(parts[2], 16, 64)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	f, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return int64(lo), int64(parentId), byte(f), true
+}
@@ -0,0 +1,258 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package propagation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/spacemonkeygo/monkit/v3/present"
+)
+
+func TestW3CRoundTrip(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("propagation")
+	w3c := W3C{AllowedBaggage: []string{"tenant-*"}}
+
+	ctx := context.Background()
+	trace := monkit.NewTrace(monkit.NewId())
+	trace.Set(present.SampledKey, true)
+	defer mon.FuncNamed("producer").RemoteTrace(&ctx, 0, trace)(nil)
+	ctx = WithRemote(ctx, Remote{Baggage: map[string]string{"tenant-id": "42"}})
+	producer := monkit.SpanFromCtx(ctx)
+
+	carrier := MapCarrier{}
+	w3c.Inject(ctx, carrier)
+	if carrier.Get("traceparent") == "" || carrier["Traceparent"] == "" {
+		t.Fatalf("expected a canonical traceparent, got %v", carrier)
+	}
+	if carrier.Get("baggage") != "tenant-id=42" {
+		t.Fatalf("unexpected baggage %q", carrier.Get("baggage"))
+	}
+
+	remote, ok := RemoteFromCtx(w3c.Extract(context.Background(), carrier))
+	if !ok {
+		t.Fatal("expected trace context")
+	}
+	if remote.TraceId == nil || *remote.TraceId != trace.Id() {
+		t.Fatalf("expected trace id %d, got %v", trace.Id(), remote.TraceId)
+	}
+	if remote.ParentId == nil || *remote.ParentId != producer.Id() {
+		t.Fatalf("expected parent id %d, got %v", producer.Id(), remote.ParentId)
+	}
+	if !remote.Sampled || remote.Baggage["tenant-id"] != "42" {
+		t.Fatalf("unexpected remote %+v", remote)
+	}
+
+	consumerCtx := context.Background()
+	defer mon.ContinueTrace(&consumerCtx, remote.TraceIdOrNew(), remote.ParentIdOrZero(),
+		remote.Sampled, remote.Baggage)(nil)
+	consumer := monkit.SpanFromCtx(consumerCtx)
+	if consumer.Trace().Id() != trace.Id() {
+		t.Fatal("consumer did not continue the trace")
+	}
+	if parent, ok := consumer.ParentId(); !ok || parent != producer.Id() {
+		t.Fatalf("expected parent %d, got %d", producer.Id(), parent)
+	}
+}
+
+func TestW3CExtract(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		headers map[string]string
+		ok      bool
+		remote  Remote
+	}{
+		{
+			name:    "empty",
+			headers: map[string]string{},
+		},
+		{
+			name: "wide trace id",
+			headers: map[string]string{
+				"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			},
+			ok: true,
+			remote: Remote{
+				TraceId:  ref(-0x7bb714dee37fce64),
+				ParentId: ref(-0x4852948e96dfcccf),
+				Sampled:  true,
+			},
+		},
+		{
+			name: "not sampled",
+			headers: map[string]string{
+				"traceparent": "00-0000000000000001-00000010-00",
+			},
+			ok:     true,
+			remote: Remote{TraceId: ref(1), ParentId: ref(16)},
+		},
+		{
+			name: "orphan sampling",
+			headers: map[string]string{
+				"tracestate": "sampled=true",
+				"baggage":    "tenant-id=1,other=2",
+			},
+			ok:     true,
+			remote: Remote{Sampled: true, Baggage: map[string]string{"tenant-id": "1"}},
+		},
+		{
+			name: "bad version",
+			headers: map[string]string{
+				"traceparent": "01-0000000000000001-00000010-01",
+			},
+		},
+		{
+			name: "bad trace id",
+			headers: map[string]string{
+				"traceparent": "00-xyz-00000010-01",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			carrier := MapCarrier{}
+			for k, v := range tc.headers {
+				carrier.Set(k, v)
+			}
+			remote, ok := RemoteFromCtx(W3C{AllowedBaggage: []string{"tenant-*"}}.
+				Extract(context.Background(), carrier))
+			if ok != tc.ok {
+				t.Fatalf("expected ok=%v, got %v", tc.ok, ok)
+			}
+			if !equalIds(remote.TraceId, tc.remote.TraceId) ||
+				!equalIds(remote.ParentId, tc.remote.ParentId) ||
+				remote.Sampled != tc.remote.Sampled ||
+				len(remote.Baggage) != len(tc.remote.Baggage) {
+				t.Fatalf("expected %+v, got %+v", tc.remote, remote)
+			}
+			for k, v := range tc.remote.Baggage {
+				if remote.Baggage[k] != v {
+					t.Fatalf("expected baggage %v, got %v", tc.remote.Baggage, remote.Baggage)
+				}
+			}
+		})
+	}
+}
+
+func TestW3CInjectUnsampled(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("propagation")
+	ctx := context.Background()
+	defer mon.FuncNamed("producer").Task(&ctx)(nil)
+
+	carrier := MapCarrier{}
+	W3C{}.Inject(ctx, carrier)
+	if len(carrier) != 0 {
+		t.Fatalf("expected nothing injected, got %v", carrier)
+	}
+}
+
+func ref(v int64) *int64 { return &v }
+
+func equalIds(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func TestW3CFlags(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("propagation")
+
+	carrier := MapCarrier{}
+	carrier.Set("traceparent", "00-0000000000000001-0000000000000002-03")
+	remote, ok := RemoteFromCtx(W3C{}.Extract(context.Background(), carrier))
+	if !ok || remote.Flags != 0x03 || !remote.Sampled {
+		t.Fatalf("unexpected remote %+v", remote)
+	}
+
+	ctx := context.Background()
+	defer mon.ContinueTrace(&ctx, remote.TraceIdOrNew(), remote.ParentIdOrZero(),
+		remote.Sampled, remote.Baggage)(nil)
+	monkit.SpanFromCtx(ctx).Trace().SetFlags(remote.Flags)
+
+	carrier = MapCarrier{}
+	W3C{}.Inject(ctx, carrier)
+	if parent := carrier.Get("traceparent"); len(parent) != 55 || parent[53:] != "03" {
+		t.Fatalf("expected flags 03 in %q", parent)
+	}
+}
+
+func TestFormatBaggage(t *testing.T) {
+	baggage := map[string]string{
+		"b":       "x y,z;%",
+		"a":       "plain",
+		"bad key": "skipped",
+	}
+	if got := FormatBaggage(baggage); got != "a=plain,b=x%20y%2Cz%3B%25" {
+		t.Fatalf("unexpected baggage %q", got)
+	}
+	if got := FormatBaggage(baggage, "b", "missing", "a"); got != "b=x%20y%2Cz%3B%25,a=plain" {
+		t.Fatalf("unexpected baggage %q", got)
+	}
+
+	carrier := MapCarrier{}
+	carrier.Set("tracestate", orphanSampling)
+	carrier.Set("baggage", FormatBaggage(baggage))
+	remote, _ := RemoteFromCtx(W3C{AllowedBaggage: []string{"*"}}.Extract(context.Background(), carrier))
+	if remote.Baggage["a"] != "plain" || remote.Baggage["b"] != "x y,z;%" {
+		t.Fatalf("unexpected round trip %v", remote.Baggage)
+	}
+}
+
+func TestFormatTraceParent(t *testing.T) {
+	if got := FormatTraceParent(monkit.IDFromInt64(-1), -2, 1); got != "00-0000000000000000ffffffffffffffff-fffffffffffffffe-01" {
+		t.Fatalf("unexpected narrow traceparent %q", got)
+	}
+
+	// W3C and Header write wide trace ids the same way.
+	mon := monkit.NewRegistry().ScopeNamed("propagation")
+	ctx := context.Background()
+	trace := monkit.NewTraceWithID(monkit.IDFromParts(0x0af7651916cd43dd, 0x8448eb211c80319c))
+	trace.Set(present.SampledKey, true)
+	defer mon.FuncNamed("producer").RemoteTrace(&ctx, 0, trace)(nil)
+	expected := FormatTraceParent(trace.FullId(), monkit.SpanFromCtx(ctx).Id(), trace.Flags())
+
+	w3c, header := MapCarrier{}, MapCarrier{}
+	W3C{}.Inject(ctx, w3c)
+	Header{Name: "X-Trace"}.Inject(ctx, header)
+	if w3c.Get("traceparent") != expected || header.Get("X-Trace") != expected {
+		t.Fatalf("expected %q, got %q and %q", expected, w3c.Get("traceparent"), header.Get("X-Trace"))
+	}
+	if expected[:36] != "00-0af7651916cd43dd8448eb211c80319c-" {
+		t.Fatalf("unexpected trace id in %q", expected)
+	}
+}
+
+func TestW3CInjectTraceBaggage(t *testing.T) {
+	mon := monkit.NewRegistry().ScopeNamed("propagation")
+	ctx := WithRemote(context.Background(), Remote{Baggage: map[string]string{"tenant": "b", "k": "v"}})
+	defer mon.FuncNamed("producer").Task(&ctx)(nil)
+	monkit.SpanFromCtx(ctx).Trace().SetBaggage("tenant", "a")
+
+	carrier := MapCarrier{}
+	W3C{}.Inject(ctx, carrier)
+	if carrier.Get(baggageHeader) != "k=v,tenant=a" {
+		t.Fatalf("unexpected carrier %v", carrier)
+	}
+}
+
+func TestW3CInjectRestoredBaggage(t *testing.T) {
+	r := monkit.NewRegistry()
+	ctx := context.Background()
+	func() {
+		defer r.ScopeNamed("propagation").FuncNamed("producer").Task(&ctx)(nil)
+		monkit.SpanFromCtx(ctx).Trace().SetBaggage("tenant", "a")
+		var err error
+		ctx, err = monkit.RestoreTraceContext(context.Background(), monkit.SerializeTraceContext(ctx))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	carrier := MapCarrier{}
+	W3C{}.Inject(ctx, carrier)
+	if carrier.Get(baggageHeader) != "tenant=a" {
+		t.Fatalf("unexpected carrier %v", carrier)
+	}
+}
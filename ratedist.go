@@ -0,0 +1,81 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/monotime"
+)
+
+// RateDist is like IntVal, but intended for throughput-style observations
+// (counts of things per unit of work). In addition to the distribution of the
+// observed values, it reports their per second rate: the sum of the values
+// observed since the previous Stats call, divided by the time elapsed since
+// that call. The first Stats call reports a rate of zero. Constructed using
+// NewRateDist,
+// though its expected usage is like:
+//
+//	var mon = monkit.Package()
+//
+//	func MyFunc() {
+//	  ...
+//	  mon.RateDist("bytes_written").Observe(int64(n))
+//	  ...
+//	}
+type RateDist struct {
+	mtx     sync.Mutex
+	dist    IntDist
+	pending int64
+	last    time.Time
+	now     func() time.Time
+}
+
+// NewRateDist creates a RateDist
+func NewRateDist(key SeriesKey) (v *RateDist) {
+	v = &RateDist{now: monotime.Now}
+	initIntDist(&v.dist, key)
+	return v
+}
+
+// Observe observes a count
+func (v *RateDist) Observe(val int64) {
+	v.mtx.Lock()
+	v.dist.Insert(val)
+	v.pending += val
+	v.mtx.Unlock()
+}
+
+// Stats implements the StatSource interface. Every call resets the window
+// the rate is computed over, so only one consumer should be calling Stats on
+// a RateDist.
+func (v *RateDist) Stats(cb func(key SeriesKey, field string, val float64)) {
+	v.mtx.Lock()
+	vd := v.dist.Copy()
+	now := v.now()
+	rate := 0.0
+	if !v.last.IsZero() {
+		if elapsed := now.Sub(v.last).Seconds(); elapsed > 0 {
+			rate = float64(v.pending) / elapsed
+		}
+	}
+	v.pending = 0
+	v.last = now
+	v.mtx.Unlock()
+
+	vd.Stats(cb)
+	cb(vd.key, "rate", rate)
+}
@@ -0,0 +1,33 @@
+package monkit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateDist(t *testing.T) {
+	now := time.Unix(1000, 0)
+	d := NewRateDist(NewSeriesKey("rate"))
+	d.now = func() time.Time { return now }
+
+	d.Observe(10)
+	d.Observe(30)
+	stats := Collect(d)
+	if stats["rate rate"] != 0 {
+		t.Fatalf("expected zero rate on first Stats, got %v", stats["rate rate"])
+	}
+	if stats["rate count"] != 2 || stats["rate sum"] != 40 {
+		t.Fatalf("unexpected value stats %v", stats)
+	}
+
+	d.Observe(50)
+	d.Observe(50)
+	now = now.Add(4 * time.Second)
+	stats = Collect(d)
+	if stats["rate rate"] != 25 {
+		t.Fatalf("expected rate 25, got %v", stats["rate rate"])
+	}
+	if stats["rate count"] != 4 || stats["rate sum"] != 140 {
+		t.Fatalf("unexpected value stats %v", stats)
+	}
+}
@@ -17,19 +17,55 @@ package monkit
 import (
 	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type traceWatcherRef struct {
 	watcher func(*Trace)
 }
 
-type registryInternal struct {
-	// sync/atomic things
-	traceWatcher *traceWatcherRef
+type slowSpanWatcher struct {
+	threshold time.Duration
+	cb        func(*Span)
+}
 
-	watcherMtx     sync.Mutex
-	watcherCounter int64
-	traceWatchers  map[int64]func(*Trace)
+type slowSpanRef struct {
+	watchers []slowSpanWatcher
+}
+
+type registryInternal struct {
+	// sync/atomic things. The 64-bit fields come first, so they are 8-byte
+	// aligned on 32-bit platforms too.
+	observerDrops         int64
+	sampleRate            uint64
+	allocSampleRate       uint64
+	traceTTL              int64
+	maxTrackedTraces      int64
+	untrackedTraces       int64
+	maxAnnotationLength   int64
+	traceWatcher          *traceWatcherRef
+	observerPool          *observerPool
+	slowSpans             *slowSpanRef
+	annotationPolicy      int32
+	panicStacks           int32
+	traceVerbosity        int32
+	sampleRateAnnotations int32
+	childCountAnnotations int32
+	packageTags           int32
+	ttlSwept              int32
+	contextAnnotators     *contextAnnotatorRef
+	traceIDFromContext    *traceIDFromContextRef
+	logger                *loggerRef
+	meterClock            *clockRef
+	globalTags            *globalTagsRef
+
+	watcherMtx       sync.Mutex
+	watcherCounter   int64
+	traceWatchers    map[int64]func(*Trace)
+	slowSpanWatchers map[int64]slowSpanWatcher
+	flushers         map[int64]Flusher
+	annotators       map[int64]ContextAnnotator
 
 	scopeMtx sync.Mutex
 	scopes   map[string]*Scope
@@ -39,6 +75,9 @@ type registryInternal struct {
 
 	orphanMtx sync.Mutex
 	orphans   map[*Span]struct{}
+
+	importMtx sync.Mutex
+	imports   []*importedRegistry
 }
 
 // Registry encapsulates all of the top-level state for a monitoring system.
@@ -58,10 +97,14 @@ type Registry struct {
 func NewRegistry() *Registry {
 	return &Registry{
 		registryInternal: &registryInternal{
-			traceWatchers: map[int64]func(*Trace){},
-			scopes:        map[string]*Scope{},
-			spans:         map[*Span]struct{}{},
-			orphans:       map[*Span]struct{}{}}}
+			maxAnnotationLength: DefaultMaxAnnotationLength,
+			traceWatchers:       map[int64]func(*Trace){},
+			slowSpanWatchers:    map[int64]slowSpanWatcher{},
+			flushers:            map[int64]Flusher{},
+			annotators:          map[int64]ContextAnnotator{},
+			scopes:              map[string]*Scope{},
+			spans:               map[*Span]struct{}{},
+			orphans:             map[*Span]struct{}{}}}
 }
 
 // WithTransformers returns a copy of Registry but with the additional
@@ -129,6 +172,8 @@ func (r *Registry) updateWatcher() {
 // it starts, until the returned cancel method is called.
 // Note: this only applies to all new traces. If you want to find existing
 // or running traces, please pull them off of live RootSpans.
+// cb is called without locks held, like a SpanObserver, but a Span it starts
+// outside of the new trace starts another trace and calls it again.
 func (r *Registry) ObserveTraces(cb func(*Trace)) (cancel func()) {
 	// even though observeTrace doesn't get a mutex, it's only ever loading
 	// the traceWatcher pointer, so we can use this mutex here to safely
@@ -149,10 +194,66 @@ func (r *Registry) ObserveTraces(cb func(*Trace)) (cancel func()) {
 	}
 }
 
+// OnSlowSpan registers cb to be called with every Span that took longer than
+// threshold, as soon as the Span finishes and until the returned cancel
+// method is called. It works without any trace collection being enabled, so
+// it's a cheap way to log or export just the slow operations. cb is called
+// synchronously as part of finishing the Span, and the Span's annotations are
+// complete by then.
+func (r *Registry) OnSlowSpan(threshold time.Duration, cb func(*Span)) (cancel func()) {
+	r.watcherMtx.Lock()
+	defer r.watcherMtx.Unlock()
+
+	cbId := r.watcherCounter
+	r.watcherCounter += 1
+	r.slowSpanWatchers[cbId] = slowSpanWatcher{threshold: threshold, cb: cb}
+	r.updateSlowSpanWatchers()
+
+	return func() {
+		r.watcherMtx.Lock()
+		defer r.watcherMtx.Unlock()
+		delete(r.slowSpanWatchers, cbId)
+		r.updateSlowSpanWatchers()
+	}
+}
+
+func (r *Registry) updateSlowSpanWatchers() {
+	if len(r.slowSpanWatchers) == 0 {
+		storeSlowSpanRef(&r.slowSpans, nil)
+		return
+	}
+	watchers := make([]slowSpanWatcher, 0, len(r.slowSpanWatchers))
+	for _, w := range r.slowSpanWatchers {
+		watchers = append(watchers, w)
+	}
+	storeSlowSpanRef(&r.slowSpans, &slowSpanRef{watchers: watchers})
+}
+
+func (r *Registry) checkSlowSpan(s *Span, duration time.Duration) {
+	ref := loadSlowSpanRef(&r.slowSpans)
+	if ref == nil {
+		return
+	}
+	for _, w := range ref.watchers {
+		if duration > w.threshold {
+			w.cb(s)
+		}
+	}
+}
+
 func (r *Registry) rootSpanStart(s *Span) {
+	max := r.MaxTrackedTraces()
 	r.spanMtx.Lock()
+	if max > 0 && len(r.spans) >= max {
+		r.spanMtx.Unlock()
+		atomic.AddInt64(&r.untrackedTraces, 1)
+		return
+	}
 	r.spans[s] = struct{}{}
 	r.spanMtx.Unlock()
+	if atomic.LoadInt32(&r.ttlSwept) == 0 && atomic.LoadInt64(&r.traceTTL) > 0 {
+		ttls.add(r.registryInternal)
+	}
 }
 
 func (r *Registry) rootSpanEnd(s *Span) {
@@ -207,6 +308,40 @@ func (r *Registry) AllSpans(cb func(s *Span)) {
 	r.RootSpans(func(s *Span) { walkSpan(s, cb) })
 }
 
+// OldestOpenSpan returns how long the oldest currently executing Span has
+// been running, or false if no Spans are running. Children always start after
+// their parents, so only root and orphaned Spans are considered, which keeps
+// the cost proportional to the number of live traces rather than live Spans.
+func (r *Registry) OldestOpenSpan() (age time.Duration, ok bool) {
+	consider := func(s *Span) {
+		if d := s.Duration(); !ok || d > age {
+			age, ok = d, true
+		}
+	}
+	r.spanMtx.Lock()
+	for s := range r.spans {
+		consider(s)
+	}
+	r.spanMtx.Unlock()
+	r.orphanMtx.Lock()
+	for s := range r.orphans {
+		consider(s)
+	}
+	r.orphanMtx.Unlock()
+	return age, ok
+}
+
+// OpenSpanStats returns a StatSource reporting the age of the oldest currently
+// executing Span as oldest_open_span_seconds, which is 0 when nothing is
+// running. A steadily growing value usually means a stuck or leaked operation.
+// environment.Register adds this StatSource automatically.
+func (r *Registry) OpenSpanStats() StatSource {
+	return StatSourceFunc(func(cb func(key SeriesKey, field string, val float64)) {
+		age, _ := r.OldestOpenSpan()
+		cb(NewSeriesKey("oldest_open_span_seconds"), "value", age.Seconds())
+	})
+}
+
 // Scopes calls 'cb' on all currently known Scopes.
 func (r *Registry) Scopes(cb func(s *Scope)) {
 	r.scopeMtx.Lock()
@@ -226,12 +361,52 @@ func (r *Registry) Funcs(cb func(f *Func)) {
 	r.Scopes(func(s *Scope) { s.Funcs(cb) })
 }
 
-// Stats implements the StatSource interface.
+// Stats implements the StatSource interface. The series of Registries
+// imported with Import are included.
 func (r *Registry) Stats(cb func(key SeriesKey, field string, val float64)) {
-	for _, t := range r.transformers {
-		cb = t.Transform(cb)
+	r.stats(cb, nil)
+}
+
+// ReadSeries returns the current "value" field of the series with the given
+// key, or false if there is no such series. See ReadSeriesField.
+func (r *Registry) ReadSeries(key SeriesKey) (float64, bool) {
+	return r.ReadSeriesField(key, "value")
+}
+
+// ReadSeriesField returns the current value of the given field of the series
+// with the given key, as found by Stats, or false if there is no such series.
+// If the key has a scope tag, only the StatSources of that Scope are
+// consulted, otherwise the first matching series of any Scope is returned.
+func (r *Registry) ReadSeriesField(key SeriesKey, field string) (val float64, ok bool) {
+	read := func(s *Scope) {
+		want := key.WithTag("scope", s.name).WithField(field)
+		cb := func(k SeriesKey, f string, v float64) {
+			if !ok && f == field && k.Measurement == key.Measurement && k.WithField(f) == want {
+				val, ok = v, true
+			}
+		}
+		for _, t := range r.transformers {
+			cb = t.Transform(cb)
+		}
+		cb = r.withGlobalTags(cb)
+		s.Stats(cb)
 	}
-	r.Scopes(func(s *Scope) { s.Stats(cb) })
+
+	if name := key.Tags.Get("scope"); name != "" {
+		r.scopeMtx.Lock()
+		s := r.scopes[name]
+		r.scopeMtx.Unlock()
+		if s != nil {
+			read(s)
+		}
+		return val, ok
+	}
+	r.Scopes(func(s *Scope) {
+		if !ok {
+			read(s)
+		}
+	})
+	return val, ok
 }
 
 var _ StatSource = (*Registry)(nil)
@@ -239,6 +414,21 @@ var _ StatSource = (*Registry)(nil)
 // Default is the default Registry
 var Default = NewRegistry()
 
+// ResetDefaultRegistry forgets all Scopes, and through them all registered
+// Funcs and other StatSources, of the Default registry. It exists to keep
+// tests from leaking registrations into each other and should not be used
+// outside of tests.
+//
+// ResetDefaultRegistry is not safe to call concurrently with other users of
+// Default. Scopes handed out before the reset, such as package level
+// monkit.Package() variables, keep working but are no longer reported by
+// Default until they're requested again.
+func ResetDefaultRegistry() {
+	Default.scopeMtx.Lock()
+	Default.scopes = map[string]*Scope{}
+	Default.scopeMtx.Unlock()
+}
+
 // ScopeNamed is just a wrapper around Default.ScopeNamed
 func ScopeNamed(name string) *Scope { return Default.ScopeNamed(name) }
 
@@ -0,0 +1,126 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestResetDefaultRegistry(t *testing.T) {
+	Package().Counter("reset-counter").Inc(1)
+	Default.ScopeNamed("reset-scope").FuncNamed("reset-func")
+	if len(Collect(Default)) == 0 {
+		t.Fatal("expected stats before reset")
+	}
+
+	ResetDefaultRegistry()
+
+	if stats := Collect(Default); len(stats) != 0 {
+		t.Fatalf("expected no stats after reset, got %v", stats)
+	}
+	Funcs(func(f *Func) { t.Fatalf("unexpected func %q after reset", f.FullName()) })
+}
+
+func TestOnSlowSpan(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("test")
+
+	var slow []*Span
+	cancel := r.OnSlowSpan(50*time.Millisecond, func(s *Span) {
+		slow = append(slow, s)
+	})
+	defer cancel()
+
+	ctx := context.Background()
+	func() {
+		defer mon.TaskNamed("fast")(&ctx)(nil)
+	}()
+	func() {
+		ctx := ctx
+		defer mon.TaskNamed("slow")(&ctx)(nil)
+		SpanFromCtx(ctx).Annotate("k", "v")
+		time.Sleep(60 * time.Millisecond)
+	}()
+
+	if len(slow) != 1 {
+		t.Fatalf("expected one slow span, got %d", len(slow))
+	}
+	if name := slow[0].Func().ShortName(); name != "slow" {
+		t.Fatalf("unexpected slow span %q", name)
+	}
+	if ann := slow[0].Annotations(); len(ann) != 1 || ann[0].Value != "v" {
+		t.Fatalf("unexpected annotations %v", ann)
+	}
+
+	cancel()
+	func() {
+		defer mon.TaskNamed("slow")(&ctx)(nil)
+		time.Sleep(60 * time.Millisecond)
+	}()
+	if len(slow) != 1 {
+		t.Fatalf("callback called after cancel")
+	}
+}
+
+func TestOldestOpenSpan(t *testing.T) {
+	r := NewRegistry()
+	if _, ok := r.OldestOpenSpan(); ok {
+		t.Fatal("expected no open spans")
+	}
+
+	mon := r.ScopeNamed("oldest")
+	ctx := context.Background()
+	finishLong := mon.Task()(&ctx)
+	time.Sleep(50 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		ctx := ctx
+		mon.Task()(&ctx)(nil)
+	}
+	finishShort := mon.Task()(nil)
+
+	age, ok := r.OldestOpenSpan()
+	if !ok || age < 50*time.Millisecond {
+		t.Fatalf("unexpected oldest span age %v %v", age, ok)
+	}
+
+	var reported float64
+	r.OpenSpanStats().Stats(func(key SeriesKey, field string, val float64) {
+		if key.Measurement == "oldest_open_span_seconds" && field == "value" {
+			reported = val
+		}
+	})
+	if reported < 0.05 {
+		t.Fatalf("unexpected reported age %v", reported)
+	}
+
+	finishLong(nil)
+	finishShort(nil)
+	if _, ok := r.OldestOpenSpan(); ok {
+		t.Fatal("expected no open spans")
+	}
+}
+
+func TestReadSeries(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("probe")
+	mon.Counter("requests", SeriesTag{Key: "kind", Val: "get"}).Inc(3)
+	mon.IntVal("size").Observe(7)
+
+	key := NewSeriesKey("requests").WithTag("kind", "get").WithTag("scope", "probe")
+	if val, ok := r.ReadSeries(key); !ok || val != 3 {
+		t.Fatalf("expected 3, got %v (%v)", val, ok)
+	}
+	if val, ok := r.ReadSeriesField(NewSeriesKey("size"), "recent"); !ok || val != 7 {
+		t.Fatalf("expected 7, got %v (%v)", val, ok)
+	}
+
+	for _, key := range []SeriesKey{
+		NewSeriesKey("requests").WithTag("scope", "probe"),
+		NewSeriesKey("requests").WithTag("kind", "get").WithTag("scope", "other"),
+		NewSeriesKey("missing"),
+	} {
+		if val, ok := r.ReadSeries(key); ok {
+			t.Fatalf("expected no series for %v, got %v", key, val)
+		}
+	}
+}
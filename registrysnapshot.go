@@ -0,0 +1,79 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// RegistrySnapshot is a JSON-serializable copy of all series of a Registry,
+// as returned by SnapshotAll.
+type RegistrySnapshot struct {
+	Time   time.Time        `json:"time"`
+	Series []SeriesSnapshot `json:"series"`
+}
+
+// SeriesSnapshot holds the fields of a single series. Distributions and
+// other sources reporting several fields per key, like min, max and the
+// quantiles, are summarized in Fields the same way Stats reports them.
+type SeriesSnapshot struct {
+	Measurement string             `json:"measurement"`
+	Tags        map[string]string  `json:"tags"`
+	Fields      map[string]float64 `json:"fields"`
+}
+
+// SnapshotAll collects everything Stats reports into a RegistrySnapshot, in
+// a single pass, with one entry per series sorted by series key. Fields with
+// values that aren't finite (NaN or infinity) are left out, since JSON can't
+// represent them.
+func (r *Registry) SnapshotAll() RegistrySnapshot {
+	snap := RegistrySnapshot{Time: time.Now()}
+	byKey := map[string]int{}
+	var keys []string
+	r.Stats(func(key SeriesKey, field string, val float64) {
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return
+		}
+		name := key.String()
+		idx, ok := byKey[name]
+		if !ok {
+			idx = len(snap.Series)
+			byKey[name] = idx
+			keys = append(keys, name)
+			snap.Series = append(snap.Series, SeriesSnapshot{
+				Measurement: key.Measurement,
+				Tags:        key.Tags.All(),
+				Fields:      map[string]float64{},
+			})
+		}
+		snap.Series[idx].Fields[field] = val
+	})
+	sort.Sort(seriesByKey{series: snap.Series, keys: keys})
+	return snap
+}
+
+type seriesByKey struct {
+	series []SeriesSnapshot
+	keys   []string
+}
+
+func (s seriesByKey) Len() int           { return len(s.series) }
+func (s seriesByKey) Less(i, j int) bool { return s.keys[i] < s.keys[j] }
+func (s seriesByKey) Swap(i, j int) {
+	s.series[i], s.series[j] = s.series[j], s.series[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
@@ -0,0 +1,37 @@
+package monkit
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSnapshotAll(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("snap")
+	mon.Counter("requests", NewSeriesTag("kind", "get")).Inc(3)
+	mon.IntVal("size").Observe(10)
+	mon.IntVal("size").Observe(20)
+
+	data, err := json.Marshal(r.SnapshotAll())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snap RegistrySnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		t.Fatal(err)
+	}
+
+	found := map[string]SeriesSnapshot{}
+	for _, s := range snap.Series {
+		if s.Tags["scope"] != "snap" {
+			t.Fatalf("unexpected tags %v", s.Tags)
+		}
+		found[s.Measurement] = s
+	}
+	if s := found["requests"]; s.Tags["kind"] != "get" || s.Fields["value"] != 3 {
+		t.Fatalf("unexpected counter snapshot %+v", s)
+	}
+	if s := found["size"]; s.Fields["count"] != 2 || s.Fields["min"] != 10 || s.Fields["max"] != 20 {
+		t.Fatalf("unexpected distribution snapshot %+v", s)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strconv"
+	"sync/atomic"
+)
+
+// SetSampleRate makes the Registry mark a random fraction of the traces it
+// starts locally as sampled, the same way a sampled remote trace or a
+// present trace query would. rate is a probability between 0 and 1. A rate
+// of 0, the default, disables local sampling.
+func (r *Registry) SetSampleRate(rate float64) {
+	atomic.StoreUint64(&r.sampleRate, math.Float64bits(rate))
+}
+
+// SampleRate returns the rate set with SetSampleRate.
+func (r *Registry) SampleRate() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&r.sampleRate))
+}
+
+// SetSampleRateAnnotations controls whether Spans of traces sampled by the
+// Registry's sampler are annotated with the sampling.rate that led to them
+// being sampled, which helps to check observed trace volumes against the
+// configured rate. It is off by default.
+func (r *Registry) SetSampleRateAnnotations(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&r.sampleRateAnnotations, v)
+}
+
+// SampleRate returns the probability with which the Trace was sampled by the
+// Registry's sampler, or false if it wasn't sampled that way.
+func (t *Trace) SampleRate() (rate float64, ok bool) {
+	return t.sampleRate, t.sampleRate > 0
+}
+
+// newLocalTrace starts a new Trace that doesn't continue a remote one,
+// consulting the sampler.
+func (r *Registry) newLocalTrace(ctx context.Context) *Trace {
+	trace := NewTrace(r.newTraceID(ctx))
+	if rate := r.SampleRate(); rate > 0 && rand.Float64() < rate {
+		trace.sampleRate = rate
+		trace.Set(sampledKey, true)
+	}
+	r.observeTrace(trace)
+	return trace
+}
+
+func (r *Registry) sampleRateAnnotation(trace *Trace) (a Annotation, ok bool) {
+	if trace.sampleRate == 0 || atomic.LoadInt32(&r.sampleRateAnnotations) == 0 {
+		return Annotation{}, false
+	}
+	return Annotation{
+		Name:  "sampling.rate",
+		Value: strconv.FormatFloat(trace.sampleRate, 'g', -1, 64),
+	}, true
+}
@@ -0,0 +1,68 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+)
+
+func sampleRateAnnotation(s *Span) (string, bool) {
+	for _, a := range s.Annotations() {
+		if a.Name == "sampling.rate" {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+func TestSampleRateAnnotation(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("sampling")
+	r.SetSampleRate(1)
+
+	startSpan := func() *Span {
+		ctx := context.Background()
+		finish := mon.Task()(&ctx)
+		defer finish(nil)
+		child := ctx
+		defer mon.Task()(&child)(nil)
+		return SpanFromCtx(child)
+	}
+
+	// annotations are off by default.
+	s := startSpan()
+	if sampled, _ := s.Trace().Get(sampledKey).(bool); !sampled {
+		t.Fatal("expected the trace to be sampled")
+	}
+	if rate, ok := s.Trace().SampleRate(); !ok || rate != 1 {
+		t.Fatalf("unexpected trace sample rate %v %v", rate, ok)
+	}
+	if _, exists := sampleRateAnnotation(s); exists {
+		t.Fatal("expected no annotation while disabled")
+	}
+
+	r.SetSampleRateAnnotations(true)
+	r.SetSampleRate(0.999999)
+	var sampled int
+	for i := 0; i < 10; i++ {
+		s := startSpan()
+		if _, ok := s.Trace().SampleRate(); !ok {
+			continue
+		}
+		sampled++
+		if rate, _ := sampleRateAnnotation(s); rate != "0.999999" {
+			t.Fatalf("unexpected sampling.rate %q", rate)
+		}
+	}
+	if sampled == 0 {
+		t.Fatal("expected traces to be sampled")
+	}
+
+	r.SetSampleRate(0)
+	s = startSpan()
+	if _, ok := s.Trace().SampleRate(); ok {
+		t.Fatal("expected no sampling once disabled")
+	}
+	if _, exists := sampleRateAnnotation(s); exists {
+		t.Fatal("expected no annotation on unsampled traces")
+	}
+}
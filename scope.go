@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Scope represents a named collection of StatSources. Scopes are constructed
@@ -39,8 +40,12 @@ func newScope(r *Registry, name string) *Scope {
 
 // Func retrieves or creates a Func named after the currently executing
 // function name (via runtime.Caller. See FuncNamed to choose your own name.
-func (s *Scope) Func() *Func {
-	return s.FuncNamed(callerFunc(0))
+//
+// The SeriesTags, if any, are part of the Func's series and are added as
+// annotations to every Span the Func starts.
+func (s *Scope) Func(tags ...SeriesTag) *Func {
+	name, fullName := callerFunc(0)
+	return s.FuncNamed(name, s.r.funcPackageTags(fullName, tags)...)
 }
 
 func (s *Scope) newSource(name string, constructor func() StatSource) (
@@ -104,7 +109,9 @@ func (s *Scope) FuncNamed(name string, tags ...SeriesTag) *Func {
 	return f
 }
 
-// Funcs calls 'cb' for all Funcs registered on this Scope.
+// Funcs calls 'cb' for all Funcs registered on this Scope. The Funcs are
+// collected before cb is first called, so cb may safely create more Funcs,
+// and Funcs registered concurrently may or may not be included.
 func (s *Scope) Funcs(cb func(f *Func)) {
 	s.mtx.Lock()
 	funcs := make(map[*Func]struct{}, len(s.sources))
@@ -119,10 +126,65 @@ func (s *Scope) Funcs(cb func(f *Func)) {
 	}
 }
 
+// RemoveFunc unregisters the Func created by FuncNamed with the same name and
+// SeriesTags, so it no longer shows up in Stats or Funcs, and no longer as a
+// parent of the Funcs it called. It returns false if there was no such Func.
+// Callers still holding the Func can keep using it, but its data is no longer
+// reported, and a later FuncNamed call creates a new Func with fresh stats.
+func (s *Scope) RemoveFunc(name string, tags ...SeriesTag) bool {
+	key := sourceName("func:", name, tags)
+	s.mtx.Lock()
+	f, ok := s.sources[key].(*Func)
+	if ok {
+		delete(s.sources, key)
+	}
+	s.mtx.Unlock()
+	if ok {
+		s.r.forgetParents(map[*Func]struct{}{f: {}})
+	}
+	return ok
+}
+
+// EvictIdleFuncs unregisters every Func on this Scope that is not currently
+// running and has not completed within the last idle duration, returning how
+// many were removed. It is meant to be called periodically by programs that
+// create Funcs dynamically (e.g. with FuncNamed and per-request tags) to keep
+// the number of reported series bounded. See RemoveFunc for what happens to
+// removed Funcs that are still referenced.
+func (s *Scope) EvictIdleFuncs(idle time.Duration) (evicted int) {
+	removed := map[*Func]struct{}{}
+	s.mtx.Lock()
+	for key, source := range s.sources {
+		f, ok := source.(*Func)
+		if !ok || f.Current() != 0 {
+			continue
+		}
+		if f.now().Sub(f.LastUsed()) >= idle {
+			delete(s.sources, key)
+			removed[f] = struct{}{}
+		}
+	}
+	s.mtx.Unlock()
+	if len(removed) > 0 {
+		s.r.forgetParents(removed)
+	}
+	return len(removed)
+}
+
+// forgetParents removes the given Funcs from the parents of every Func of the
+// Registry, so that removed Funcs don't stay referenced by their children.
+func (r *Registry) forgetParents(funcs map[*Func]struct{}) {
+	r.Funcs(func(child *Func) {
+		for f := range funcs {
+			child.parentsAndMutex.Remove(f)
+		}
+	})
+}
+
 // Meter retrieves or creates a Meter named after the given name. See Event.
 func (s *Scope) Meter(name string, tags ...SeriesTag) *Meter {
 	source := s.newSource(sourceName("", name, tags), func() StatSource {
-		return NewMeter(NewSeriesKey(name).WithTags(tags...))
+		return s.r.newMeter(NewSeriesKey(name).WithTags(tags...))
 	})
 	m, ok := source.(*Meter)
 	if !ok {
@@ -163,6 +225,32 @@ func (s *Scope) IntVal(name string, tags ...SeriesTag) *IntVal {
 	return m
 }
 
+// BufferedIntVal retrieves or creates a BufferedIntVal after the given name.
+func (s *Scope) BufferedIntVal(name string, tags ...SeriesTag) *BufferedIntVal {
+	source := s.newSource(sourceName("", name, tags), func() StatSource {
+		return NewBufferedIntVal(NewSeriesKey(name).WithTags(tags...))
+	})
+	m, ok := source.(*BufferedIntVal)
+	if !ok {
+		panic(fmt.Sprintf("%s already used for another stats source: %#v",
+			name, source))
+	}
+	return m
+}
+
+// RateDist retrieves or creates a RateDist after the given name.
+func (s *Scope) RateDist(name string, tags ...SeriesTag) *RateDist {
+	source := s.newSource(sourceName("", name, tags), func() StatSource {
+		return NewRateDist(NewSeriesKey(name).WithTags(tags...))
+	})
+	m, ok := source.(*RateDist)
+	if !ok {
+		panic(fmt.Sprintf("%s already used for another stats source: %#v",
+			name, source))
+	}
+	return m
+}
+
 // IntValf retrieves or creates an IntVal after the given printf-formatted
 // name.
 func (s *Scope) IntValf(template string, args ...interface{}) *IntVal {
@@ -256,6 +344,20 @@ func (s *Scope) RawValf(template string, args ...interface{}) *RawVal {
 	return s.RawVal(fmt.Sprintf(template, args...))
 }
 
+// ExpHistogram retrieves or creates an ExpHistogram after the given name. The
+// scale only applies when the ExpHistogram is created.
+func (s *Scope) ExpHistogram(name string, scale int32, tags ...SeriesTag) *ExpHistogram {
+	source := s.newSource(sourceName("", name, tags), func() StatSource {
+		return NewExpHistogram(NewSeriesKey(name).WithTags(tags...), scale)
+	})
+	m, ok := source.(*ExpHistogram)
+	if !ok {
+		panic(fmt.Sprintf("%s already used for another stats source: %#v",
+			name, source))
+	}
+	return m
+}
+
 // Timer retrieves or creates a Timer after the given name.
 func (s *Scope) Timer(name string, tags ...SeriesTag) *Timer {
 	source := s.newSource(sourceName("", name, tags), func() StatSource {
@@ -0,0 +1,84 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type sloThresholds struct {
+	mtx    sync.Mutex
+	limits map[float64]time.Duration
+}
+
+// SetPercentileThreshold sets a limit for the quantile q, where 0 <= q <= 1,
+// of the durations of this Func's successful calls, such as 0.99 and 500ms
+// for "the p99 latency must stay below 500ms". Whenever the Func's stats are
+// collected, the quantile is queried from the reservoir of successful calls
+// (see FuncStats.SuccessTimes) and reported as the slo_breached stat, tagged
+// with the quantile, which is 1 if it exceeds the limit and 0 otherwise. The
+// untagged slo_breached stat is 1 if any threshold is breached.
+//
+// A Func may have thresholds for several quantiles. Setting a quantile again
+// replaces its limit, and a limit of zero or less removes it.
+func (f *Func) SetPercentileThreshold(q float64, limit time.Duration) {
+	c := &f.slos
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if limit <= 0 {
+		delete(c.limits, q)
+		return
+	}
+	if c.limits == nil {
+		c.limits = map[float64]time.Duration{}
+	}
+	c.limits[q] = limit
+}
+
+func (f *Func) sloStats(cb func(key SeriesKey, field string, val float64)) {
+	c := &f.slos
+	c.mtx.Lock()
+	quantiles := make([]float64, 0, len(c.limits))
+	limits := make(map[float64]time.Duration, len(c.limits))
+	for q, limit := range c.limits {
+		quantiles = append(quantiles, q)
+		limits[q] = limit
+	}
+	c.mtx.Unlock()
+	if len(quantiles) == 0 {
+		return
+	}
+
+	sort.Float64s(quantiles)
+	times := f.SuccessTimes()
+	anyBreached := false
+	for _, q := range quantiles {
+		breached := times.Count > 0 && times.Query(q) > limits[q]
+		anyBreached = anyBreached || breached
+		cb(f.key.WithTag("quantile", strconv.FormatFloat(q, 'f', -1, 64)),
+			"slo_breached", boolStat(breached))
+	}
+	cb(f.key, "slo_breached", boolStat(anyBreached))
+}
+
+func boolStat(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
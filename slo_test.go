@@ -0,0 +1,67 @@
+package monkit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentileThreshold(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("slo")
+	f := mon.FuncNamed("handler")
+
+	// no thresholds, no stat.
+	if _, ok := Collect(mon)["function,name=handler,scope=slo slo_breached"]; ok {
+		t.Fatal("unexpected slo_breached stat")
+	}
+
+	f.SetPercentileThreshold(0.99, 500*time.Millisecond)
+	f.SetPercentileThreshold(0.5, 50*time.Millisecond)
+
+	// nothing recorded yet, nothing breached.
+	stats := Collect(mon)
+	if stats["function,name=handler,scope=slo slo_breached"] != 0 ||
+		stats["function,name=handler,quantile=0.99,scope=slo slo_breached"] != 0 {
+		t.Fatalf("unexpected breach: %v", stats)
+	}
+
+	for i := 0; i < 100; i++ {
+		f.start(nil)
+		f.end(nil, false, 10*time.Millisecond)
+	}
+	stats = Collect(mon)
+	for key, expected := range map[string]float64{
+		"function,name=handler,scope=slo slo_breached":               0,
+		"function,name=handler,quantile=0.5,scope=slo slo_breached":  0,
+		"function,name=handler,quantile=0.99,scope=slo slo_breached": 0,
+	} {
+		if got, ok := stats[key]; !ok || got != expected {
+			t.Fatalf("%s: got %v %v, expected %v", key, got, ok, expected)
+		}
+	}
+
+	// a slow tail crosses the p99 threshold but not the p50 one.
+	for i := 0; i < 10; i++ {
+		f.start(nil)
+		f.end(nil, false, time.Second)
+	}
+	stats = Collect(mon)
+	for key, expected := range map[string]float64{
+		"function,name=handler,scope=slo slo_breached":               1,
+		"function,name=handler,quantile=0.5,scope=slo slo_breached":  0,
+		"function,name=handler,quantile=0.99,scope=slo slo_breached": 1,
+	} {
+		if got := stats[key]; got != expected {
+			t.Fatalf("%s: got %v, expected %v", key, got, expected)
+		}
+	}
+
+	// removing the breached threshold clears the breach.
+	f.SetPercentileThreshold(0.99, 0)
+	stats = Collect(mon)
+	if _, ok := stats["function,name=handler,quantile=0.99,scope=slo slo_breached"]; ok {
+		t.Fatal("removed threshold still reported")
+	}
+	if stats["function,name=handler,scope=slo slo_breached"] != 0 {
+		t.Fatal("expected no breach")
+	}
+}
@@ -27,8 +27,46 @@ type ctxKey int
 
 const (
 	spanKey ctxKey = iota
+	restoredKey
 )
 
+// SpanKind describes the relationship between a Span and the work around it,
+// following the span kinds of OpenTelemetry. Exporters use it to decide how
+// to render Spans.
+type SpanKind int
+
+const (
+	// SpanKindInternal is the default kind, for work that stays within the
+	// process.
+	SpanKindInternal SpanKind = iota
+	// SpanKindServer is for the handling of a remote request.
+	SpanKindServer
+	// SpanKindClient is for a request to a remote service.
+	SpanKindClient
+	// SpanKindProducer is for enqueueing work that is processed elsewhere
+	// later.
+	SpanKindProducer
+	// SpanKindConsumer is for processing work that a producer enqueued.
+	SpanKindConsumer
+)
+
+// String returns the lowercase name of the kind, such as "server".
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindInternal:
+		return "internal"
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	case SpanKindProducer:
+		return "producer"
+	case SpanKindConsumer:
+		return "consumer"
+	}
+	return fmt.Sprintf("SpanKind(%d)", int(k))
+}
+
 // Annotation represents an arbitrary name and value string pair
 type Annotation struct {
 	Name  string
@@ -38,6 +76,9 @@ type Annotation struct {
 func (s *Span) addChild(child *Span) {
 	s.mtx.Lock()
 	s.children.Add(child)
+	if s.countChildren {
+		s.childCount++
+	}
 	done := s.done
 	s.mtx.Unlock()
 	if done {
@@ -152,6 +193,9 @@ func interfacesToString(args []interface{}) string {
 // Id returns the Span id.
 func (s *Span) Id() int64 { return s.id }
 
+// FullId returns the id of the Span as an ID. Span ids are always narrow.
+func (s *Span) FullId() ID { return IDFromInt64(s.id) }
+
 // ParentId returns the id of the parent Span, if it has a parent.
 func (s *Span) ParentId() (int64, bool) {
 	if s.parentId != nil {
@@ -177,11 +221,52 @@ func (s *Span) Annotations() []Annotation {
 	return append([]Annotation(nil), annotations...)
 }
 
-// Annotate adds an annotation to the existing Span.
+// Annotate adds an annotation to the existing Span. How repeated names are
+// handled depends on the Span's AnnotationPolicy.
 func (s *Span) Annotate(name, val string) {
 	s.mtx.Lock()
-	s.annotations = append(s.annotations, Annotation{Name: name, Value: val})
+	val, ok := s.addAnnotation(name, val)
+	s.mtx.Unlock()
+	if ok {
+		s.observeAnnotation(name, val)
+	}
+}
+
+// SetKind sets the SpanKind of the Span. Spans are SpanKindInternal unless
+// set otherwise.
+func (s *Span) SetKind(kind SpanKind) {
+	s.mtx.Lock()
+	s.kind = kind
+	s.mtx.Unlock()
+}
+
+// Kind returns the SpanKind set with SetKind.
+func (s *Span) Kind() (kind SpanKind) {
+	s.mtx.Lock()
+	kind = s.kind
+	s.mtx.Unlock()
+	return kind
+}
+
+// DuplicateAnnotation is the annotation MarkDuplicate records whether the
+// operation of a Span was a duplicate with.
+const DuplicateAnnotation = "operation.duplicate"
+
+// MarkDuplicate records whether the operation of the Span is a duplicate or
+// replay of one that was already done, such as a message redelivered by a
+// queue with at-least-once delivery, as the operation.duplicate annotation
+// with the value "true" or "false", so that exporters and dashboards can
+// filter on it. Marking a Span again replaces the value, whatever the
+// AnnotationPolicy of the Span.
+func (s *Span) MarkDuplicate(duplicate bool) {
+	val := strconv.FormatBool(duplicate)
+	s.mtx.Lock()
+	if !s.replaceAnnotation(DuplicateAnnotation, val) {
+		// there is no annotation of the name yet, so it's always stored.
+		val, _ = s.addAnnotation(DuplicateAnnotation, val)
+	}
 	s.mtx.Unlock()
+	s.observeAnnotation(DuplicateAnnotation, val)
 }
 
 // Orphaned returns true if the Parent span ended before this Span did.
@@ -69,6 +69,25 @@ func taskArgs(f *Func, args []interface{}) bool {
 // Func.
 //
 // You may also include any SeriesTags which should be included with the Task.
+// Besides being part of the Func's series, the SeriesTags are added as
+// annotations to every Span the returned Task starts.
 func (s *Scope) TaskNamed(name string, tags ...SeriesTag) Task {
 	return s.FuncNamed(name, tags...).Task
 }
+
+// Measure runs fn as a Task of the Func called name on scope and returns
+// fn's error, which is recorded on the Func along with the duration of the
+// call. Panics are recorded as well and then propagate, just like with any
+// other Task. It is shorthand for
+//
+//	func() (err error) {
+//	  defer scope.TaskNamed(name)(nil)(&err)
+//	  return fn()
+//	}()
+//
+// Measure starts a new trace, as it has no context to take a parent Span
+// from.
+func Measure(scope *Scope, name string, fn func() error) (err error) {
+	defer scope.TaskNamed(name)(nil)(&err)
+	return fn()
+}
@@ -37,8 +37,9 @@ import (
 //
 // Timers implement StatSource.
 type Timer struct {
-	mtx   sync.Mutex
-	times *DurationDist
+	updated lastUpdated
+	mtx     sync.Mutex
+	times   *DurationDist
 }
 
 // NewTimer constructs a new Timer.
@@ -72,12 +73,34 @@ func (r *RunningTimer) Stop() time.Duration {
 	r.t.mtx.Lock()
 	if !r.stopped {
 		r.t.times.Insert(elapsed)
+		r.t.updated.touch()
 		r.stopped = true
 	}
 	r.t.mtx.Unlock()
 	return elapsed
 }
 
+// LastUpdated implements the LastUpdatedSource interface.
+func (t *Timer) LastUpdated() time.Time { return t.updated.time() }
+
+// OnNewMax registers cb to be called with every timed duration that is a new
+// maximum, see DurationDist.OnNewMax. cb is called while t is locked, so it
+// has to be quick and must not use t.
+func (t *Timer) OnNewMax(cb func(time.Duration)) {
+	t.mtx.Lock()
+	t.times.OnNewMax(cb)
+	t.mtx.Unlock()
+}
+
+// OnThreshold registers cb to be called each time the timed durations cross
+// limit upwards, see DurationDist.OnThreshold. cb is called like the ones of
+// OnNewMax.
+func (t *Timer) OnThreshold(limit time.Duration, cb func(time.Duration)) {
+	t.mtx.Lock()
+	t.times.OnThreshold(limit, cb)
+	t.mtx.Unlock()
+}
+
 // Values returns the main timer values
 func (t *Timer) Values() *DurationDist {
 	t.mtx.Lock()
@@ -15,13 +15,34 @@
 package monkit
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/monotime"
+)
+
+const (
+	// sampledKey and sampledCBKey are the Trace keys also known as
+	// present.SampledKey and present.SampledCBKey.
+	sampledKey   = "sampled"
+	sampledCBKey = "sampled-cb"
 )
 
+// TraceFlagSampled is the sampled bit of the W3C trace-flags, see
+// Trace.Flags.
+const TraceFlagSampled byte = 0x01
+
 // SpanObserver is the interface plugins must implement if they want to observe
 // all spans on a given trace as they happen.
+//
+// Observers are called without any lock of the Trace, its Spans or the
+// Registry held, so they may start and finish Spans themselves, including
+// children of the observed Span, use the Trace's Get, Set and other methods,
+// and register or cancel observers. Spans an observer starts on an observed
+// Trace are observed as well, so an observer that starts a Span for every
+// Span it observes has to end the recursion itself.
 type SpanObserver interface {
 	// Start is called when a Span starts
 	Start(s *Span)
@@ -39,18 +60,50 @@ type Trace struct {
 	// sync/atomic things
 	spanCount     int64
 	spanObservers *spanObserverTuple
+	expired       int32
 
 	// immutable things from construction
-	id int64
+	id     int64
+	fullId ID
+
+	// set before the Trace is shared, see Registry.SetSampleRate
+	sampleRate float64
+
+	// wall clock anchor, see WallTime
+	wallStart time.Time
+	monoStart time.Time
 
 	// protected by mtx
-	mtx  sync.Mutex
-	vals map[interface{}]interface{}
+	mtx      sync.Mutex
+	vals     map[interface{}]interface{}
+	rootName string
+	link     *TraceLink
+	flags    byte
+	metadata map[string]string
+	baggage  map[string]string
+}
+
+// TraceLink refers to a Span of another Trace that caused a Trace, see
+// Trace.SetParentTrace.
+type TraceLink struct {
+	TraceId int64 `json:"trace_id"`
+	SpanId  int64 `json:"span_id"`
 }
 
 // NewTrace creates a new Trace.
 func NewTrace(id int64) *Trace {
-	return &Trace{id: id}
+	return NewTraceWithID(IDFromInt64(id))
+}
+
+// NewTraceWithID creates a new Trace with an ID of up to 128 bits. Id returns
+// the low 64 bits of wide IDs.
+func NewTraceWithID(id ID) *Trace {
+	return &Trace{
+		id:        int64(id.lo),
+		fullId:    id,
+		wallStart: time.Now(),
+		monoStart: monotime.Now(),
+	}
 }
 
 func (t *Trace) getObserver() SpanCtxObserver {
@@ -108,6 +161,170 @@ func removeObserverFrom(parent **spanObserverTuple, ref *spanObserverTuple) (
 // Id returns the id of the Trace
 func (t *Trace) Id() int64 { return t.id }
 
+// FullId returns the full ID of the Trace, which is wider than Id if the Trace
+// was created with a wide ID by NewTraceWithID.
+func (t *Trace) FullId() ID { return t.fullId }
+
+// RootName returns the name of the entry point of the Trace. Unless changed
+// with SetRootName, it is the full name of the Func of the first Span started
+// on the Trace.
+func (t *Trace) RootName() string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.rootName
+}
+
+// SetRootName replaces the RootName of the Trace, for instance with something
+// more descriptive of the request that started it, such as "GET /users".
+func (t *Trace) SetRootName(name string) {
+	t.mtx.Lock()
+	t.rootName = name
+	t.mtx.Unlock()
+}
+
+func (t *Trace) initRootName(f *Func) {
+	t.mtx.Lock()
+	if t.rootName == "" {
+		t.rootName = f.FullName()
+	}
+	t.mtx.Unlock()
+}
+
+// SetParentTrace records that the Trace was caused by the given Span of
+// another Trace, such as an upstream request that is deliberately not
+// continued. Unlike a parent Span, this doesn't make the Trace part of the
+// other one, it only links them.
+func (t *Trace) SetParentTrace(traceId, spanId int64) {
+	t.mtx.Lock()
+	t.link = &TraceLink{TraceId: traceId, SpanId: spanId}
+	t.mtx.Unlock()
+}
+
+// ParentTrace returns the link set by SetParentTrace, or false if there is
+// none.
+func (t *Trace) ParentTrace() (link TraceLink, ok bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.link == nil {
+		return TraceLink{}, false
+	}
+	return *t.link, true
+}
+
+// SetMetadata sets a trace-level key/value pair, such as the service version
+// or deployment that handled the Trace, for exporters to report along with
+// its Spans. Unlike the values of Set, metadata is meant to be exported, and
+// unlike annotations, it belongs to the whole Trace rather than a Span.
+func (t *Trace) SetMetadata(key, value string) {
+	t.mtx.Lock()
+	if t.metadata == nil {
+		t.metadata = map[string]string{}
+	}
+	t.metadata[key] = value
+	t.mtx.Unlock()
+}
+
+// Metadata returns a copy of the metadata set with SetMetadata, or nil if
+// there is none.
+func (t *Trace) Metadata() map[string]string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if len(t.metadata) == 0 {
+		return nil
+	}
+	rv := make(map[string]string, len(t.metadata))
+	for k, v := range t.metadata {
+		rv[k] = v
+	}
+	return rv
+}
+
+// SetBaggage sets a trace-level key/value pair that is carried along with the
+// Trace when its context is passed on, such as with SerializeTraceContext, or
+// with the baggage header sent by the http package's TraceRequest and
+// propagation.W3C. Unlike metadata, baggage is meant for the code handling the
+// Trace rather than for exporters.
+func (t *Trace) SetBaggage(key, value string) {
+	t.mtx.Lock()
+	if t.baggage == nil {
+		t.baggage = map[string]string{}
+	}
+	t.baggage[key] = value
+	t.mtx.Unlock()
+}
+
+// Baggage returns a copy of the baggage set with SetBaggage, or nil if there
+// is none.
+func (t *Trace) Baggage() map[string]string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if len(t.baggage) == 0 {
+		return nil
+	}
+	rv := make(map[string]string, len(t.baggage))
+	for k, v := range t.baggage {
+		rv[k] = v
+	}
+	return rv
+}
+
+// Baggage returns the baggage visible at the Span in ctx, that is the
+// baggage of its Trace (see Trace.SetBaggage), so that every Span of a
+// Trace, however deeply nested, sees the baggage set on any of them, such as
+// by the TraceHandler of the http package. If ctx has no Span, it returns the
+// baggage of the Trace a context of RestoreTraceContext continues, if any.
+// The result is a copy, or nil if there is no baggage.
+func Baggage(ctx context.Context) map[string]string {
+	if s := SpanFromCtx(ctx); s != nil {
+		return s.Trace().Baggage()
+	}
+	if restored, ok := ctx.Value(restoredKey).(*restoredTrace); ok {
+		return restored.trace.Baggage()
+	}
+	return nil
+}
+
+// Flags returns the W3C trace-flags of the Trace. The TraceFlagSampled bit
+// reflects whether the Trace is sampled, that is whether its "sampled" value
+// (see Get) is true. The other bits are the ones set with SetFlags, which
+// lets them be propagated even though monkit doesn't interpret them.
+func (t *Trace) Flags() byte {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	flags := t.flags &^ TraceFlagSampled
+	if sampled, _ := t.vals[sampledKey].(bool); sampled {
+		flags |= TraceFlagSampled
+	}
+	return flags
+}
+
+// SetFlags sets the W3C trace-flags of the Trace. The TraceFlagSampled bit
+// marks the Trace as sampled or not sampled.
+func (t *Trace) SetFlags(flags byte) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.flags = flags
+	sampled, _ := t.vals[sampledKey].(bool)
+	switch {
+	case flags&TraceFlagSampled != 0 && !sampled:
+		if t.vals == nil {
+			t.vals = map[interface{}]interface{}{}
+		}
+		t.vals[sampledKey] = true
+	case flags&TraceFlagSampled == 0 && sampled:
+		t.vals[sampledKey] = false
+	}
+}
+
+// WallTime converts a time from the monotonic clock Span start and finish
+// times are measured with (see Span.Start) into a wall clock time, anchored
+// on the wall clock time the Trace was created at. Times converted this way
+// keep their relative ordering even if the wall clock is adjusted while the
+// Trace is running.
+func (t *Trace) WallTime(monotonic time.Time) time.Time {
+	return t.wallStart.Add(monotonic.Sub(t.monoStart))
+}
+
 // GetAll returns values associated with a trace. See SetAll.
 func (t *Trace) GetAll() (val map[interface{}]interface{}) {
 	t.mtx.Lock()
@@ -0,0 +1,86 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTraceFlags(t *testing.T) {
+	trace := NewTrace(NewId())
+	if trace.Flags() != 0 {
+		t.Fatalf("expected no flags, got %#x", trace.Flags())
+	}
+
+	trace.Set(sampledKey, true)
+	if trace.Flags() != TraceFlagSampled {
+		t.Fatalf("expected the sampled flag, got %#x", trace.Flags())
+	}
+
+	for _, flags := range []byte{0x00, 0x01, 0x02, 0x03, 0xff} {
+		trace.SetFlags(flags)
+		if trace.Flags() != flags {
+			t.Fatalf("expected %#x, got %#x", flags, trace.Flags())
+		}
+		sampled, _ := trace.Get(sampledKey).(bool)
+		if sampled != (flags&TraceFlagSampled != 0) {
+			t.Fatalf("flags %#x: unexpected sampled %v", flags, sampled)
+		}
+	}
+}
+
+func TestTraceMetadata(t *testing.T) {
+	trace := NewTrace(NewId())
+	if trace.Metadata() != nil {
+		t.Fatalf("expected no metadata, got %v", trace.Metadata())
+	}
+
+	trace.SetMetadata("tenant", "a")
+	trace.SetMetadata("region", "us")
+	trace.SetMetadata("tenant", "b")
+
+	md := trace.Metadata()
+	if len(md) != 2 || md["tenant"] != "b" || md["region"] != "us" {
+		t.Fatalf("unexpected metadata %v", md)
+	}
+
+	md["tenant"] = "c"
+	if trace.Metadata()["tenant"] != "b" {
+		t.Fatal("expected Metadata to return a copy")
+	}
+	if trace.Get("tenant") != nil {
+		t.Fatal("expected metadata to be distinct from Get/Set storage")
+	}
+}
+
+func TestBaggageInheritance(t *testing.T) {
+	mon := NewRegistry().ScopeNamed("baggage")
+
+	ctx := context.Background()
+	if baggage := Baggage(ctx); baggage != nil {
+		t.Fatalf("expected no baggage, got %v", baggage)
+	}
+
+	defer mon.TaskNamed("root")(&ctx)(nil)
+	SpanFromCtx(ctx).Trace().SetBaggage("tenant", "acme")
+
+	child := ctx
+	defer mon.TaskNamed("child")(&child)(nil)
+	grandchild := child
+	defer mon.TaskNamed("grandchild")(&grandchild)(nil)
+
+	if got := Baggage(grandchild); len(got) != 1 || got["tenant"] != "acme" {
+		t.Fatalf("expected the root's baggage, got %v", got)
+	}
+
+	// baggage set deep down is visible to the whole trace.
+	SpanFromCtx(grandchild).Trace().SetBaggage("user", "bob")
+	if got := Baggage(ctx); len(got) != 2 || got["user"] != "bob" {
+		t.Fatalf("expected the grandchild's baggage, got %v", got)
+	}
+
+	// the result is a copy.
+	Baggage(ctx)["tenant"] = "other"
+	if got := Baggage(child); got["tenant"] != "acme" {
+		t.Fatalf("baggage was modified through Baggage: %v", got)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// The serialized trace context layout is:
+//
+//	byte  0      version, always 0
+//	bytes 1-16   trace id
+//	bytes 17-24  span id of the parent
+//	byte  25     trace flags, see Trace.Flags
+//
+// followed by the number of baggage entries and, for each entry, its key and
+// value, all of which are prefixed with their length. Numbers are big-endian
+// and lengths and counts unsigned varints.
+const (
+	traceContextVersion   = 0
+	traceContextHeaderLen = 26
+)
+
+// ErrTraceContext is wrapped by all errors RestoreTraceContext returns.
+var ErrTraceContext = errors.New("invalid serialized trace context")
+
+// SerializeTraceContext encodes the context of the Trace of the Span in ctx,
+// that is the Trace's id and flags, which include whether it is sampled, its
+// baggage (see Trace.SetBaggage) and the id of the Span, to a compact blob
+// that can be stored, for instance while a workflow waits for a human, and
+// passed to RestoreTraceContext later, even in another process, to continue
+// the Trace. It returns nil if ctx has no Span. A context returned by
+// RestoreTraceContext that no Span was started from yet is serialized again
+// as is.
+func SerializeTraceContext(ctx context.Context) []byte {
+	var trace *Trace
+	var parentId int64
+	if s := SpanFromCtx(ctx); s != nil {
+		trace, parentId = s.Trace(), s.Id()
+	} else if restored, ok := ctx.Value(restoredKey).(*restoredTrace); ok {
+		trace, parentId = restored.trace, restored.parentId
+	} else {
+		return nil
+	}
+
+	baggage := trace.Baggage()
+	keys := make([]string, 0, len(baggage))
+	size := traceContextHeaderLen + binary.MaxVarintLen64
+	for k, v := range baggage {
+		keys = append(keys, k)
+		size += 2*binary.MaxVarintLen64 + len(k) + len(v)
+	}
+	sort.Strings(keys)
+
+	buf := make([]byte, traceContextHeaderLen, size)
+	buf[0] = traceContextVersion
+	id := trace.FullId().Bytes()
+	copy(buf[1:17], id[:])
+	binary.BigEndian.PutUint64(buf[17:25], uint64(parentId))
+	buf[25] = trace.Flags()
+	buf = binary.AppendUvarint(buf, uint64(len(keys)))
+	for _, k := range keys {
+		buf = appendString(buf, k)
+		buf = appendString(buf, baggage[k])
+	}
+	return buf
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// RestoreTraceContext returns a copy of ctx that continues the Trace
+// serialized with SerializeTraceContext: the next Span started from it, with
+// a Task of any Func, belongs to the serialized Trace, with the serialized
+// Span as its remote parent, and the Trace has the serialized flags and
+// baggage. A Span already in ctx is replaced.
+func RestoreTraceContext(ctx context.Context, blob []byte) (context.Context, error) {
+	if len(blob) < traceContextHeaderLen {
+		return ctx, fmt.Errorf("%w: got %d bytes, need at least %d",
+			ErrTraceContext, len(blob), traceContextHeaderLen)
+	}
+	if blob[0] != traceContextVersion {
+		return ctx, fmt.Errorf("%w: unsupported version %d", ErrTraceContext, blob[0])
+	}
+	hi := binary.BigEndian.Uint64(blob[1:9])
+	lo := binary.BigEndian.Uint64(blob[9:17])
+	parentId := int64(binary.BigEndian.Uint64(blob[17:25]))
+	flags := blob[25]
+
+	rest := blob[traceContextHeaderLen:]
+	count, rest, err := readUvarint(rest)
+	if err != nil {
+		return ctx, err
+	}
+	trace := NewTraceWithID(IDFromParts(hi, lo))
+	trace.SetFlags(flags)
+	for i := uint64(0); i < count; i++ {
+		var k, v string
+		if k, rest, err = readString(rest); err != nil {
+			return ctx, err
+		}
+		if v, rest, err = readString(rest); err != nil {
+			return ctx, err
+		}
+		trace.SetBaggage(k, v)
+	}
+	if len(rest) != 0 {
+		return ctx, fmt.Errorf("%w: %d trailing bytes", ErrTraceContext, len(rest))
+	}
+
+	return context.WithValue(ResetContextSpan(ctx), restoredKey,
+		&restoredTrace{trace: trace, parentId: parentId}), nil
+}
+
+func readUvarint(buf []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return 0, nil, fmt.Errorf("%w: truncated", ErrTraceContext)
+	}
+	return v, buf[n:], nil
+}
+
+func readString(buf []byte) (string, []byte, error) {
+	n, buf, err := readUvarint(buf)
+	if err != nil {
+		return "", nil, err
+	}
+	if n > uint64(len(buf)) {
+		return "", nil, fmt.Errorf("%w: truncated", ErrTraceContext)
+	}
+	return string(buf[:n]), buf[n:], nil
+}
+
+// restoredTrace is the Trace a context returned by RestoreTraceContext
+// continues.
+type restoredTrace struct {
+	trace    *Trace
+	parentId int64
+	observed sync.Once
+}
+
+// resume returns the Trace and remote parent for a Span started from the
+// restored context, letting the trace observers of r know about the Trace
+// the first time.
+func (t *restoredTrace) resume(r *Registry) (*Trace, *int64) {
+	t.observed.Do(func() { r.observeTrace(t.trace) })
+	parentId := t.parentId
+	return t.trace, &parentId
+}
@@ -0,0 +1,83 @@
+package monkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTraceContextRoundTrip(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("resume")
+
+	ctx := context.Background()
+	finish := mon.FuncNamed("suspend").Task(&ctx)
+	suspended := SpanFromCtx(ctx)
+	suspended.Trace().Set(sampledKey, true)
+	suspended.Trace().SetBaggage("tenant", "acme")
+	suspended.Trace().SetBaggage("workflow", "")
+	blob := SerializeTraceContext(ctx)
+	finish(nil)
+
+	var observed []*Trace
+	r.ObserveTraces(func(tr *Trace) { observed = append(observed, tr) })
+
+	restored, err := RestoreTraceContext(context.Background(), blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again := SerializeTraceContext(restored); string(again) != string(blob) {
+		t.Fatalf("got %x, expected %x", again, blob)
+	}
+
+	for i := 0; i < 2; i++ {
+		ctx := restored
+		mon.FuncNamed("resume").Task(&ctx)(nil)
+		s := SpanFromCtx(ctx)
+		if s.Trace().FullId() != suspended.Trace().FullId() {
+			t.Fatalf("got trace %v, expected %v", s.Trace().FullId(), suspended.Trace().FullId())
+		}
+		if parentId, ok := s.ParentId(); !ok || parentId != suspended.Id() {
+			t.Fatalf("got parent %d %v, expected %d", parentId, ok, suspended.Id())
+		}
+		if sampled, _ := s.Trace().Get(sampledKey).(bool); !sampled {
+			t.Fatal("expected a sampled trace")
+		}
+		baggage := s.Trace().Baggage()
+		if len(baggage) != 2 || baggage["tenant"] != "acme" || baggage["workflow"] != "" {
+			t.Fatalf("got baggage %v", baggage)
+		}
+	}
+	if len(observed) != 1 {
+		t.Fatalf("trace observed %d times, expected once", len(observed))
+	}
+}
+
+func TestTraceContextNoSpan(t *testing.T) {
+	if blob := SerializeTraceContext(context.Background()); blob != nil {
+		t.Fatalf("got %x, expected nil", blob)
+	}
+}
+
+func TestTraceContextInvalid(t *testing.T) {
+	ctx := context.Background()
+	mon := NewRegistry().ScopeNamed("resume")
+	mon.FuncNamed("suspend").Task(&ctx)(nil)
+	SpanFromCtx(ctx).Trace().SetBaggage("key", "value")
+	blob := SerializeTraceContext(ctx)
+
+	version := append([]byte(nil), blob...)
+	version[0] = 1
+	for _, invalid := range [][]byte{
+		nil,
+		blob[:traceContextHeaderLen-1],
+		blob[:traceContextHeaderLen],
+		blob[:len(blob)-1],
+		append(append([]byte(nil), blob...), 0),
+		version,
+	} {
+		if _, err := RestoreTraceContext(context.Background(), invalid); !errors.Is(err, ErrTraceContext) {
+			t.Fatalf("%x: got %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+)
+
+type traceIDFromContextRef struct {
+	fn func(ctx context.Context) (traceID int64, ok bool)
+}
+
+// SetTraceIDFromContext registers fn to pick the id of new Traces started on
+// this Registry, such as a correlation id a middleware stored in the context.
+// fn is consulted with the context of every root Span that starts a new
+// Trace, and when it returns false a random id is used as usual. It does not
+// affect Traces continued from a remote parent. Passing nil removes the hook.
+func (r *Registry) SetTraceIDFromContext(fn func(ctx context.Context) (traceID int64, ok bool)) {
+	var ref *traceIDFromContextRef
+	if fn != nil {
+		ref = &traceIDFromContextRef{fn: fn}
+	}
+	storeTraceIDFromContextRef(&r.traceIDFromContext, ref)
+}
+
+func (r *Registry) newTraceID(ctx context.Context) int64 {
+	if ref := loadTraceIDFromContextRef(&r.traceIDFromContext); ref != nil {
+		if id, ok := ref.fn(ctx); ok {
+			return id
+		}
+	}
+	return NewId()
+}
@@ -0,0 +1,42 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+)
+
+type correlationKey struct{}
+
+func TestSetTraceIDFromContext(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("traceid")
+	r.SetTraceIDFromContext(func(ctx context.Context) (int64, bool) {
+		id, ok := ctx.Value(correlationKey{}).(int64)
+		return id, ok
+	})
+
+	ctx := context.WithValue(context.Background(), correlationKey{}, int64(12345))
+	finish := mon.Task()(&ctx)
+	if id := SpanFromCtx(ctx).Trace().Id(); id != 12345 {
+		t.Fatalf("expected trace id 12345, got %d", id)
+	}
+	child := ctx
+	mon.TaskNamed("child")(&child)(nil)
+	if id := SpanFromCtx(child).Trace().Id(); id != 12345 {
+		t.Fatalf("expected child to share trace id 12345, got %d", id)
+	}
+	finish(nil)
+
+	ctx = context.Background()
+	mon.Task()(&ctx)(nil)
+	if id := SpanFromCtx(ctx).Trace().Id(); id == 12345 {
+		t.Fatal("expected a random trace id without a correlation id")
+	}
+
+	r.SetTraceIDFromContext(nil)
+	ctx = context.WithValue(context.Background(), correlationKey{}, int64(12345))
+	mon.Task()(&ctx)(nil)
+	if id := SpanFromCtx(ctx).Trace().Id(); id == 12345 {
+		t.Fatal("expected the hook to be removed")
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3/monotime"
+)
+
+// ErrSpanExpired is the error Spans force-finished because of the TTL set
+// by Registry.SetTraceTTL finish with.
+var ErrSpanExpired = errors.New("monkit: span expired")
+
+// SetTraceTTL sets the maximum lifetime of a Trace. Once a Trace has a
+// running Span that started more than ttl ago, the Trace is marked expired
+// and all of its running Spans are force-finished as failed with
+// ErrSpanExpired and an expired=true annotation, which removes them from
+// RootSpans and AllSpans. Calling the Task's exit function of an expired Span
+// later on is a no-op. This keeps leaked Spans from being tracked forever.
+//
+// Traces are checked by a single shared sweeper goroutine, which only runs
+// while some Registry with a TTL has running Spans. A ttl of zero, the
+// default, disables this.
+func (r *Registry) SetTraceTTL(ttl time.Duration) {
+	atomic.StoreInt64(&r.traceTTL, int64(ttl))
+	if ttl > 0 {
+		ttls.add(r.registryInternal)
+	} else {
+		ttls.remove(r.registryInternal)
+	}
+}
+
+// TraceTTL returns the duration set by SetTraceTTL.
+func (r *Registry) TraceTTL() time.Duration {
+	return time.Duration(atomic.LoadInt64(&r.traceTTL))
+}
+
+// Expired returns true if the Trace outlived the TTL set by
+// Registry.SetTraceTTL.
+func (t *Trace) Expired() bool {
+	return atomic.LoadInt32(&t.expired) != 0
+}
+
+func (r *registryInternal) expireTraces(now time.Time) {
+	ttl := time.Duration(atomic.LoadInt64(&r.traceTTL))
+	if ttl <= 0 {
+		return
+	}
+
+	traces := map[*Trace]struct{}{}
+	consider := func(s *Span) {
+		if now.Sub(s.start) >= ttl {
+			traces[s.trace] = struct{}{}
+		}
+	}
+	r.spanMtx.Lock()
+	for s := range r.spans {
+		consider(s)
+	}
+	r.spanMtx.Unlock()
+	r.orphanMtx.Lock()
+	for s := range r.orphans {
+		consider(s)
+	}
+	r.orphanMtx.Unlock()
+	if len(traces) == 0 {
+		return
+	}
+
+	var spans []*Span
+	(&Registry{registryInternal: r}).RootSpans(func(root *Span) {
+		if _, ok := traces[root.trace]; ok {
+			walkSpan(root, func(s *Span) { spans = append(spans, s) })
+		}
+	})
+	for t := range traces {
+		atomic.StoreInt32(&t.expired, 1)
+	}
+	// children first, so parents don't needlessly orphan them.
+	for i := len(spans) - 1; i >= 0; i-- {
+		spans[i].expire(now)
+	}
+}
+
+func (s *Span) expire(now time.Time) {
+	if !s.claimFinish() {
+		return
+	}
+	if s.budget > 0 {
+		budgets.remove(s)
+	}
+	s.mtx.Lock()
+	s.addAnnotation("expired", "true")
+	s.mtx.Unlock()
+	s.f.scope.r.logf("monkit: span %s of trace %d expired after running for %s",
+		s.f.FullName(), s.trace.Id(), now.Sub(s.start))
+	s.finished(s, ErrSpanExpired, false, now)
+}
+
+// ttlSweeper periodically expires the Traces of the Registries that have a
+// TTL. It only keeps hold of Registries while they have running Spans, so
+// that Registries that are no longer used can be garbage collected.
+type ttlSweeper struct {
+	mtx        sync.Mutex
+	registries map[*registryInternal]struct{}
+	running    bool
+	next       time.Time // when the next sweep is due
+	wake       chan struct{}
+}
+
+var ttls = ttlSweeper{
+	registries: map[*registryInternal]struct{}{},
+	wake:       make(chan struct{}, 1),
+}
+
+func (t *ttlSweeper) add(r *registryInternal) {
+	t.mtx.Lock()
+	t.registries[r] = struct{}{}
+	atomic.StoreInt32(&r.ttlSwept, 1)
+	start := !t.running
+	t.running = true
+	// wake the sweeper if r's TTL, such as a shortened one, is due sooner.
+	ttl := time.Duration(atomic.LoadInt64(&r.traceTTL))
+	wake := !start && time.Now().Add(ttl/2).Before(t.next)
+	t.mtx.Unlock()
+	if start {
+		go t.run()
+	}
+	if wake {
+		select {
+		case t.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (t *ttlSweeper) remove(r *registryInternal) {
+	t.mtx.Lock()
+	delete(t.registries, r)
+	atomic.StoreInt32(&r.ttlSwept, 0)
+	t.mtx.Unlock()
+}
+
+// release stops sweeping r if it has no running Spans left. Spans starting
+// afterwards add r again, see Registry.rootSpanStart.
+func (t *ttlSweeper) release(r *registryInternal) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	atomic.StoreInt32(&r.ttlSwept, 0)
+	r.spanMtx.Lock()
+	idle := len(r.spans) == 0
+	r.spanMtx.Unlock()
+	if idle {
+		r.orphanMtx.Lock()
+		idle = len(r.orphans) == 0
+		r.orphanMtx.Unlock()
+	}
+	if idle {
+		delete(t.registries, r)
+		return
+	}
+	atomic.StoreInt32(&r.ttlSwept, 1)
+}
+
+// interval returns how long to wait until the next sweep, which is half the
+// shortest TTL, or false if there is nothing left to sweep.
+func (t *ttlSweeper) interval() (time.Duration, bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	var min time.Duration
+	for r := range t.registries {
+		if ttl := time.Duration(atomic.LoadInt64(&r.traceTTL)); ttl > 0 && (min == 0 || ttl < min) {
+			min = ttl
+		}
+	}
+	if min == 0 {
+		t.running = false
+		return 0, false
+	}
+	t.next = time.Now().Add(min / 2)
+	return min / 2, true
+}
+
+func (t *ttlSweeper) run() {
+	for {
+		interval, ok := t.interval()
+		if !ok {
+			return
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-t.wake:
+			// recompute the interval for a changed TTL.
+			timer.Stop()
+			continue
+		}
+
+		t.mtx.Lock()
+		registries := make([]*registryInternal, 0, len(t.registries))
+		for r := range t.registries {
+			registries = append(registries, r)
+		}
+		t.mtx.Unlock()
+
+		now := monotime.Now()
+		for _, r := range registries {
+			r.expireTraces(now)
+			t.release(r)
+		}
+	}
+}
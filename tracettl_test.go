@@ -0,0 +1,122 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func hasAnnotation(s *Span, name, value string) bool {
+	for _, a := range s.Annotations() {
+		if a.Name == name && a.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTraceTTL(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("ttl")
+	r.SetTraceTTL(20 * time.Millisecond)
+	defer r.SetTraceTTL(0)
+
+	// leak a root span and one of its children.
+	ctx := context.Background()
+	finishRoot := mon.FuncNamed("root").Task(&ctx)
+	root := SpanFromCtx(ctx)
+	childCtx := ctx
+	mon.FuncNamed("child").Task(&childCtx)
+	child := SpanFromCtx(childCtx)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !root.Trace().Expired() {
+		if time.Now().After(deadline) {
+			t.Fatal("trace never expired")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	var open int
+	r.AllSpans(func(s *Span) { open++ })
+	if open != 0 {
+		t.Fatalf("expected no open spans, got %d", open)
+	}
+	if !hasAnnotation(root, "expired", "true") || !hasAnnotation(child, "expired", "true") {
+		t.Fatalf("expected expired annotations, got %v and %v",
+			root.Annotations(), child.Annotations())
+	}
+	// expired spans count as failures, not successes.
+	rootFunc := mon.FuncNamed("root")
+	if got := rootFunc.Errors()["Span Expired"]; got != 1 || rootFunc.Success() != 0 {
+		t.Fatalf("expected 1 expired root call, got %d and %d successes", got, rootFunc.Success())
+	}
+
+	// finishing an expired span afterwards doesn't count it twice.
+	finishRoot(nil)
+	if got := rootFunc.Errors()["Span Expired"]; got != 1 || rootFunc.Success() != 0 {
+		t.Fatalf("expected 1 expired root call, got %d and %d successes", got, rootFunc.Success())
+	}
+
+	// fresh traces are left alone.
+	ctx = context.Background()
+	finish := mon.FuncNamed("fresh").Task(&ctx)
+	finish(nil)
+	if trace := SpanFromCtx(ctx).Trace(); trace.Expired() {
+		t.Fatal("unexpected expired trace")
+	}
+}
+
+func TestTraceTTLShrink(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("ttl")
+	r.SetTraceTTL(time.Hour)
+	defer r.SetTraceTTL(0)
+
+	ctx := context.Background()
+	mon.FuncNamed("leaked").Task(&ctx)
+	// the sweeper waits for half an hour now, a shorter TTL wakes it.
+	time.Sleep(10 * time.Millisecond)
+	r.SetTraceTTL(20 * time.Millisecond)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !SpanFromCtx(ctx).Trace().Expired() {
+		if time.Now().After(deadline) {
+			t.Fatal("trace never expired")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTraceTTLReleasesIdleRegistries(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("ttl")
+	r.SetTraceTTL(20 * time.Millisecond)
+	defer r.SetTraceTTL(0)
+
+	swept := func() bool {
+		ttls.mtx.Lock()
+		defer ttls.mtx.Unlock()
+		_, ok := ttls.registries[r.registryInternal]
+		return ok
+	}
+	waitFor := func(want bool) {
+		deadline := time.Now().Add(10 * time.Second)
+		for swept() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected swept to be %v", want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// without running spans the sweeper lets go of r.
+	waitFor(false)
+
+	// running spans make it sweep r again, until they finished.
+	ctx := context.Background()
+	finish := mon.FuncNamed("work").Task(&ctx)
+	waitFor(true)
+	finish(nil)
+	waitFor(false)
+}
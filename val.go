@@ -31,8 +31,9 @@ import (
 //	  ...
 //	}
 type IntVal struct {
-	mtx  sync.Mutex
-	dist IntDist
+	updated lastUpdated
+	mtx     sync.Mutex
+	dist    IntDist
 }
 
 // NewIntVal creates an IntVal
@@ -47,8 +48,12 @@ func (v *IntVal) Observe(val int64) {
 	v.mtx.Lock()
 	v.dist.Insert(val)
 	v.mtx.Unlock()
+	v.updated.touch()
 }
 
+// LastUpdated implements the LastUpdatedSource interface.
+func (v *IntVal) LastUpdated() time.Time { return v.updated.time() }
+
 // Stats implements the StatSource interface.
 func (v *IntVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	v.mtx.Lock()
@@ -58,6 +63,13 @@ func (v *IntVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	vd.Stats(cb)
 }
 
+// Snapshot returns a summary of the observed values, see DistSnapshot.
+func (v *IntVal) Snapshot() DistSnapshot {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.dist.Snapshot()
+}
+
 // Quantile returns an estimate of the requested quantile of observed values.
 // 0 <= quantile <= 1
 func (v *IntVal) Quantile(quantile float64) (rv int64) {
@@ -67,6 +79,24 @@ func (v *IntVal) Quantile(quantile float64) (rv int64) {
 	return rv
 }
 
+// OnNewMax registers cb to be called with every observed value that is a new
+// maximum, see IntDist.OnNewMax. cb is called while v is locked, so it has to
+// be quick and must not use v.
+func (v *IntVal) OnNewMax(cb func(int64)) {
+	v.mtx.Lock()
+	v.dist.OnNewMax(cb)
+	v.mtx.Unlock()
+}
+
+// OnThreshold registers cb to be called each time the observed values cross
+// limit upwards, see IntDist.OnThreshold. cb is called like the ones of
+// OnNewMax.
+func (v *IntVal) OnThreshold(limit int64, cb func(int64)) {
+	v.mtx.Lock()
+	v.dist.OnThreshold(limit, cb)
+	v.mtx.Unlock()
+}
+
 // FloatVal is a convenience wrapper around an FloatDist. Constructed using
 // NewFloatVal, though its expected usage is like:
 //
@@ -78,8 +108,9 @@ func (v *IntVal) Quantile(quantile float64) (rv int64) {
 //	  ...
 //	}
 type FloatVal struct {
-	mtx  sync.Mutex
-	dist FloatDist
+	updated lastUpdated
+	mtx     sync.Mutex
+	dist    FloatDist
 }
 
 // NewFloatVal creates a FloatVal
@@ -94,8 +125,12 @@ func (v *FloatVal) Observe(val float64) {
 	v.mtx.Lock()
 	v.dist.Insert(val)
 	v.mtx.Unlock()
+	v.updated.touch()
 }
 
+// LastUpdated implements the LastUpdatedSource interface.
+func (v *FloatVal) LastUpdated() time.Time { return v.updated.time() }
+
 // Stats implements the StatSource interface.
 func (v *FloatVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	v.mtx.Lock()
@@ -105,6 +140,13 @@ func (v *FloatVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	vd.Stats(cb)
 }
 
+// Snapshot returns a summary of the observed values, see DistSnapshot.
+func (v *FloatVal) Snapshot() DistSnapshot {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.dist.Snapshot()
+}
+
 // Quantile returns an estimate of the requested quantile of observed values.
 // 0 <= quantile <= 1
 func (v *FloatVal) Quantile(quantile float64) (rv float64) {
@@ -114,6 +156,24 @@ func (v *FloatVal) Quantile(quantile float64) (rv float64) {
 	return rv
 }
 
+// OnNewMax registers cb to be called with every observed value that is a new
+// maximum, see FloatDist.OnNewMax. cb is called while v is locked, so it has to
+// be quick and must not use v.
+func (v *FloatVal) OnNewMax(cb func(float64)) {
+	v.mtx.Lock()
+	v.dist.OnNewMax(cb)
+	v.mtx.Unlock()
+}
+
+// OnThreshold registers cb to be called each time the observed values cross
+// limit upwards, see FloatDist.OnThreshold. cb is called like the ones of
+// OnNewMax.
+func (v *FloatVal) OnThreshold(limit float64, cb func(float64)) {
+	v.mtx.Lock()
+	v.dist.OnThreshold(limit, cb)
+	v.mtx.Unlock()
+}
+
 // BoolVal keeps statistics about boolean values. It keeps the number of trues,
 // number of falses, and the disposition (number of trues minus number of
 // falses). Constructed using NewBoolVal, though its expected usage is like:
@@ -126,10 +186,11 @@ func (v *FloatVal) Quantile(quantile float64) (rv float64) {
 //	  ...
 //	}
 type BoolVal struct {
-	trues  int64
-	falses int64
-	recent int32
-	key    SeriesKey
+	updated lastUpdated
+	trues   int64
+	falses  int64
+	recent  int32
+	key     SeriesKey
 }
 
 // NewBoolVal creates a BoolVal
@@ -146,8 +207,12 @@ func (v *BoolVal) Observe(val bool) {
 		atomic.AddInt64(&v.falses, 1)
 		atomic.StoreInt32(&v.recent, 0)
 	}
+	v.updated.touch()
 }
 
+// LastUpdated implements the LastUpdatedSource interface.
+func (v *BoolVal) LastUpdated() time.Time { return v.updated.time() }
+
 // Stats implements the StatSource interface.
 func (v *BoolVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	trues := atomic.LoadInt64(&v.trues)
@@ -170,9 +235,10 @@ func (v *BoolVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 //	  ...
 //	}
 type StructVal struct {
-	mtx    sync.Mutex
-	recent interface{}
-	key    SeriesKey
+	updated lastUpdated
+	mtx     sync.Mutex
+	recent  interface{}
+	key     SeriesKey
 }
 
 // NewStructVal creates a StructVal
@@ -187,8 +253,12 @@ func (v *StructVal) Observe(val interface{}) {
 	v.mtx.Lock()
 	v.recent = val
 	v.mtx.Unlock()
+	v.updated.touch()
 }
 
+// LastUpdated implements the LastUpdatedSource interface.
+func (v *StructVal) LastUpdated() time.Time { return v.updated.time() }
+
 // Stats implements the StatSource interface.
 func (v *StructVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	v.mtx.Lock()
@@ -211,8 +281,9 @@ func (v *StructVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 //	  ...
 //	}
 type DurationVal struct {
-	mtx  sync.Mutex
-	dist DurationDist
+	updated lastUpdated
+	mtx     sync.Mutex
+	dist    DurationDist
 }
 
 // NewDurationVal creates an DurationVal
@@ -227,6 +298,16 @@ func (v *DurationVal) Observe(val time.Duration) {
 	v.mtx.Lock()
 	v.dist.Insert(val)
 	v.mtx.Unlock()
+	v.updated.touch()
+}
+
+// LastUpdated implements the LastUpdatedSource interface.
+func (v *DurationVal) LastUpdated() time.Time { return v.updated.time() }
+
+// ObserveNanos is like Observe, but takes a duration as an integer number of
+// nanoseconds.
+func (v *DurationVal) ObserveNanos(nanos int64) {
+	v.Observe(time.Duration(nanos))
 }
 
 // Stats implements the StatSource interface.
@@ -238,6 +319,13 @@ func (v *DurationVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	vd.Stats(cb)
 }
 
+// Snapshot returns a summary of the observed values, see DistSnapshot.
+func (v *DurationVal) Snapshot() DistSnapshot {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	return v.dist.Snapshot()
+}
+
 // Quantile returns an estimate of the requested quantile of observed values.
 // 0 <= quantile <= 1
 func (v *DurationVal) Quantile(quantile float64) (rv time.Duration) {
@@ -247,6 +335,24 @@ func (v *DurationVal) Quantile(quantile float64) (rv time.Duration) {
 	return rv
 }
 
+// OnNewMax registers cb to be called with every observed value that is a new
+// maximum, see DurationDist.OnNewMax. cb is called while v is locked, so it has to
+// be quick and must not use v.
+func (v *DurationVal) OnNewMax(cb func(time.Duration)) {
+	v.mtx.Lock()
+	v.dist.OnNewMax(cb)
+	v.mtx.Unlock()
+}
+
+// OnThreshold registers cb to be called each time the observed values cross
+// limit upwards, see DurationDist.OnThreshold. cb is called like the ones of
+// OnNewMax.
+func (v *DurationVal) OnThreshold(limit time.Duration, cb func(time.Duration)) {
+	v.mtx.Lock()
+	v.dist.OnThreshold(limit, cb)
+	v.mtx.Unlock()
+}
+
 // Aggregate can implement additional aggregation for collected values.
 type Aggregate func() (observe func(val float64), stat func() (field string, val float64))
 
@@ -261,6 +367,7 @@ type Aggregate func() (observe func(val float64), stat func() (field string, val
 //	  ...
 //	}
 type RawVal struct {
+	updated   lastUpdated
 	mtx       sync.Mutex
 	value     float64
 	key       SeriesKey
@@ -287,8 +394,12 @@ func (v *RawVal) Observe(val float64) {
 		o(val)
 	}
 	v.mtx.Unlock()
+	v.updated.touch()
 }
 
+// LastUpdated implements the LastUpdatedSource interface.
+func (v *RawVal) LastUpdated() time.Time { return v.updated.time() }
+
 // Stats implements the StatSource interface.
 func (v *RawVal) Stats(cb func(key SeriesKey, field string, val float64)) {
 	v.mtx.Lock()
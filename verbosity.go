@@ -0,0 +1,75 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/spacemonkeygo/monkit/v3/monotime"
+)
+
+// SetVerbosity sets the verbosity level of the Func. Tasks of a Func only
+// create Spans while its verbosity is at or above the threshold set with
+// Registry.SetTraceVerbosity. Below the threshold, calls are still counted
+// and timed in the Func's stats, but no Span is created, so they don't show
+// up in traces and their callees attach to the nearest traced caller.
+//
+// Verbosity only affects Spans made from a local context. Tasks that start or
+// continue a particular trace, such as Func.RemoteTrace, are always traced.
+func (f *Func) SetVerbosity(level int) {
+	atomic.StoreInt32(&f.verbosity, int32(level))
+}
+
+// Verbosity returns the level set with SetVerbosity, 0 by default.
+func (f *Func) Verbosity() int { return int(atomic.LoadInt32(&f.verbosity)) }
+
+// SetTraceVerbosity sets the verbosity threshold for Span creation. See
+// Func.SetVerbosity. The default threshold is 0, under which every Func with
+// the default verbosity is traced. It can be changed at any time.
+func (r *Registry) SetTraceVerbosity(level int) {
+	atomic.StoreInt32(&r.traceVerbosity, int32(level))
+}
+
+// TraceVerbosity returns the threshold set with SetTraceVerbosity.
+func (r *Registry) TraceVerbosity() int {
+	return int(atomic.LoadInt32(&r.traceVerbosity))
+}
+
+func (f *Func) traced() bool {
+	return f.Verbosity() >= f.scope.r.TraceVerbosity()
+}
+
+// untracedTask records a call to f in its stats without creating a Span.
+func (f *Func) untracedTask(ctx context.Context) (context.Context, func(*error)) {
+	var parent *Func
+	if s := SpanFromCtx(ctx); s != nil {
+		parent = s.f
+	}
+	f.start(parent)
+	start := monotime.Now()
+	return ctx, func(errptr *error) {
+		rec := recover()
+		panicked := rec != nil
+		var err error
+		if errptr != nil {
+			err = *errptr
+		}
+		f.end(err, panicked, monotime.Now().Sub(start))
+		if panicked {
+			panic(rec)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package monkit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTraceVerbosity(t *testing.T) {
+	r := NewRegistry()
+	mon := r.ScopeNamed("verbosity")
+	outer := mon.FuncNamed("outer")
+	detail := mon.FuncNamed("detail")
+	inner := mon.FuncNamed("inner")
+	detail.SetVerbosity(-1)
+	r.SetTraceVerbosity(0)
+
+	var innerSpan *Span
+	run := func() {
+		ctx := context.Background()
+		defer outer.Task(&ctx)(nil)
+		outerSpan := SpanFromCtx(ctx)
+		func(ctx context.Context) {
+			defer detail.Task(&ctx)(nil)
+			if SpanFromCtx(ctx) != outerSpan {
+				t.Fatal("below-threshold Func created a span")
+			}
+			func(ctx context.Context) {
+				defer inner.Task(&ctx)(nil)
+				innerSpan = SpanFromCtx(ctx)
+			}(ctx)
+		}(ctx)
+		if innerSpan.parent != outerSpan {
+			t.Fatal("expected inner span to attach to the traced caller")
+		}
+	}
+	run()
+
+	if detail.Success() != 1 || outer.Success() != 1 || inner.Success() != 1 {
+		t.Fatal("expected all Funcs to keep counting")
+	}
+	var parents []*Func
+	detail.Parents(func(f *Func) { parents = append(parents, f) })
+	if len(parents) != 1 || parents[0] != outer {
+		t.Fatalf("unexpected parents %v", parents)
+	}
+
+	// lowering the threshold traces the detail Func again.
+	r.SetTraceVerbosity(-1)
+	ctx := context.Background()
+	finish := detail.Task(&ctx)
+	if SpanFromCtx(ctx) == nil || SpanFromCtx(ctx).Func() != detail {
+		t.Fatal("expected a span once the threshold allows it")
+	}
+	finish(nil)
+	if detail.Success() != 2 {
+		t.Fatal("expected detail to keep counting")
+	}
+}
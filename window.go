@@ -0,0 +1,74 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monkit
+
+import "time"
+
+const (
+	windowBuckets     = 12
+	windowBucketWidth = 5 * time.Second
+)
+
+// rollingWindow counts successes and failures over roughly the last minute,
+// using fixed buckets that are lazily recycled as time moves forward, so no
+// background goroutine is needed. It is not safe for concurrent use.
+type rollingWindow struct {
+	buckets [windowBuckets]windowBucket
+}
+
+type windowBucket struct {
+	epoch     int64
+	successes int64
+	failures  int64
+}
+
+func windowEpoch(now time.Time) int64 {
+	return now.UnixNano() / int64(windowBucketWidth)
+}
+
+func (w *rollingWindow) observe(now time.Time, failed bool) {
+	epoch := windowEpoch(now)
+	b := &w.buckets[epoch%windowBuckets]
+	if b.epoch != epoch {
+		*b = windowBucket{epoch: epoch}
+	}
+	if failed {
+		b.failures++
+	} else {
+		b.successes++
+	}
+}
+
+// counts returns the successes and failures in the window ending at now.
+func (w *rollingWindow) counts(now time.Time) (successes, failures int64) {
+	epoch := windowEpoch(now)
+	for _, b := range w.buckets {
+		if b.epoch > epoch-windowBuckets && b.epoch <= epoch {
+			successes += b.successes
+			failures += b.failures
+		}
+	}
+	return successes, failures
+}
+
+// errorRate returns the fraction of failures among the calls in the window
+// ending at now, or 0 if there were none.
+func (w *rollingWindow) errorRate(now time.Time) float64 {
+	successes, failures := w.counts(now)
+	if successes+failures == 0 {
+		return 0
+	}
+	return float64(failures) / float64(successes+failures)
+}
@@ -0,0 +1,58 @@
+package monkit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFuncErrorRate(t *testing.T) {
+	f := NewRegistry().ScopeNamed("window").FuncNamed("f")
+	now := time.Unix(1000000, 0)
+	f.now = func() time.Time { return now }
+
+	errorRate := func() float64 {
+		return Collect(f)["function,name=f error_rate_1m"]
+	}
+	call := func(err error) {
+		f.start(nil)
+		f.end(err, false, time.Millisecond)
+	}
+
+	if rate := errorRate(); rate != 0 {
+		t.Fatalf("expected 0 for an empty window, got %v", rate)
+	}
+
+	// ancient failures.
+	for i := 0; i < 10; i++ {
+		call(errors.New("fail"))
+	}
+	if rate := errorRate(); rate != 1 {
+		t.Fatalf("expected 1, got %v", rate)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if rate := errorRate(); rate != 0 {
+		t.Fatalf("expected old failures to have expired, got %v", rate)
+	}
+
+	call(nil)
+	call(nil)
+	call(nil)
+	now = now.Add(30 * time.Second)
+	call(errors.New("fail"))
+	if rate := errorRate(); rate != 0.25 {
+		t.Fatalf("expected 0.25, got %v", rate)
+	}
+	if calls := f.RecentCalls(); calls != 4 {
+		t.Fatalf("expected 4 recent calls, got %d", calls)
+	}
+
+	now = now.Add(45 * time.Second)
+	if rate := errorRate(); rate != 1 {
+		t.Fatalf("expected only the recent failure to count, got %v", rate)
+	}
+	if calls := f.RecentCalls(); calls != 1 {
+		t.Fatalf("expected 1 recent call, got %d", calls)
+	}
+}